@@ -12,7 +12,8 @@ import (
 
 // MockInferenceClient is a mock implementation of pb.InferenceClient for testing
 type MockInferenceClient struct {
-	PredictFunc func(ctx context.Context, in *pb.PredictRequest, opts ...grpc.CallOption) (*pb.PredictResponse, error)
+	PredictFunc         func(ctx context.Context, in *pb.PredictRequest, opts ...grpc.CallOption) (*pb.PredictResponse, error)
+	EnsemblePredictFunc func(ctx context.Context, in *pb.EnsembleRequest, opts ...grpc.CallOption) (*pb.EnsembleResponse, error)
 }
 
 func (m *MockInferenceClient) Predict(ctx context.Context, in *pb.PredictRequest, opts ...grpc.CallOption) (*pb.PredictResponse, error) {
@@ -22,6 +23,21 @@ func (m *MockInferenceClient) Predict(ctx context.Context, in *pb.PredictRequest
 	return &pb.PredictResponse{}, nil
 }
 
+func (m *MockInferenceClient) PredictLargeInput(ctx context.Context, opts ...grpc.CallOption) (pb.Inference_PredictLargeInputClient, error) {
+	return nil, nil
+}
+
+func (m *MockInferenceClient) EnsemblePredict(ctx context.Context, in *pb.EnsembleRequest, opts ...grpc.CallOption) (*pb.EnsembleResponse, error) {
+	if m.EnsemblePredictFunc != nil {
+		return m.EnsemblePredictFunc(ctx, in, opts...)
+	}
+	return &pb.EnsembleResponse{}, nil
+}
+
+func (m *MockInferenceClient) LoadTest(ctx context.Context, in *pb.LoadTestRequest, opts ...grpc.CallOption) (*pb.LoadTestResponse, error) {
+	return &pb.LoadTestResponse{}, nil
+}
+
 func TestMakePrediction_Success(t *testing.T) {
 	// Arrange
 	expectedResponse := &pb.PredictResponse{
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/status"
+)
+
+// CSVOptions describes the layout of a CSV file of inputs.
+type CSVOptions struct {
+	// Header is set when the first row names the columns.
+	Header bool
+	// ModelColumn names the column holding each row's model; it requires
+	// Header. Every other column is a feature.
+	ModelColumn string
+	// Model is used for every row when there is no ModelColumn.
+	Model string
+}
+
+// CSVRequest is a PredictRequest built from the CSV row on Line.
+type CSVRequest struct {
+	Line    int
+	Request *pb.PredictRequest
+}
+
+/*
+* ReadCSVRequests builds one PredictRequest per CSV row, using the row's
+* numeric columns as the feature vector. Malformed rows (a different number
+* of columns than the first row, a value that is not a finite number, or no
+* model name) are skipped with a warning so one bad row doesn't stop a bulk
+* run.
+ */
+
+func ReadCSVRequests(r io.Reader, opts CSVOptions) ([]CSVRequest, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	modelIdx := -1
+	if opts.Header {
+		header, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV header: %w", err)
+		}
+		if opts.ModelColumn != "" {
+			modelIdx = slices.Index(header, opts.ModelColumn)
+			if modelIdx < 0 {
+				return nil, fmt.Errorf("CSV header has no %q column", opts.ModelColumn)
+			}
+		}
+		reader.FieldsPerRecord = len(header)
+	} else if opts.ModelColumn != "" {
+		return nil, errors.New("a model column requires a header row")
+	}
+
+	var requests []CSVRequest
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return requests, nil
+		}
+		if errors.Is(err, csv.ErrFieldCount) {
+			log.Printf("Skipping CSV %v", err)
+			continue
+		}
+		if err != nil {
+			return requests, fmt.Errorf("reading CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if reader.FieldsPerRecord < 0 {
+			// Without a header, the first row fixes the column count.
+			reader.FieldsPerRecord = len(row)
+		}
+
+		req, err := csvRowRequest(row, modelIdx, opts.Model)
+		if err != nil {
+			log.Printf("Skipping CSV line %d: %v", line, err)
+			continue
+		}
+		requests = append(requests, CSVRequest{Line: line, Request: req})
+	}
+}
+
+// csvRowRequest builds the request for one row; modelIdx is the position of
+// the model column, or -1 to use model.
+func csvRowRequest(row []string, modelIdx int, model string) (*pb.PredictRequest, error) {
+	var features []float64
+	for i, field := range row {
+		if i == modelIdx {
+			model = strings.TrimSpace(field)
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("column %d: %q is not a finite number", i+1, field)
+		}
+		features = append(features, v)
+	}
+	if len(features) == 0 {
+		return nil, errors.New("no feature columns")
+	}
+	if model == "" {
+		return nil, errors.New("no model name")
+	}
+	input, err := json.Marshal(features)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PredictRequest{ModelName: model, InputData: input}, nil
+}
+
+/*
+* RunCSV sends the requests one at a time and writes one CSV row per request
+* to w: its input line, model, status, output values as a JSON array, and
+* the error if the call failed. The server's streaming RPC carries one large
+* input rather than many small ones, so the rows are sent as unary calls.
+* All rows share budget for their retries; rows that were not retried because
+* it ran out get the RetryBudgetExhausted status. It returns the number of
+* failed calls; failures don't stop the run.
+ */
+
+func RunCSV(client pb.InferenceClient, requests []CSVRequest, w io.Writer, budget *retryBudget) (int, error) {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"line", "model", "status", "output", "error"}); err != nil {
+		return 0, err
+	}
+
+	failed := 0
+	for _, r := range requests {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		resp, err := predictWithRetry(ctx, client, r.Request, *retries, *backoff, budget)
+		cancel()
+
+		record := []string{strconv.Itoa(r.Line), r.Request.GetModelName(), "", "", ""}
+		if err == nil {
+			var values []float64
+			if values, err = DecodeOutput(resp); err == nil {
+				output, _ := json.Marshal(values)
+				record[2], record[3] = resp.GetStatus(), string(output)
+			}
+		}
+		if err != nil {
+			failed++
+			record[2], record[4] = status.Code(err).String(), err.Error()
+			if errors.Is(err, errRetryBudgetExhausted) {
+				record[2] = "RetryBudgetExhausted"
+			}
+		}
+		if err := out.Write(record); err != nil {
+			return failed, err
+		}
+	}
+	out.Flush()
+	return failed, out.Error()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReadCSVRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		contents   string
+		opts       CSVOptions
+		wantModels []string
+		wantInputs []string
+		wantLines  []int
+	}{
+		{
+			name:       "header",
+			contents:   "a,b,c\n1,2,3\n4.5,5,6\n",
+			opts:       CSVOptions{Header: true, Model: "m"},
+			wantModels: []string{"m", "m"},
+			wantInputs: []string{"[1,2,3]", "[4.5,5,6]"},
+			wantLines:  []int{2, 3},
+		},
+		{
+			name:       "no header",
+			contents:   "1,2\n3,4\n",
+			opts:       CSVOptions{Model: "m"},
+			wantModels: []string{"m", "m"},
+			wantInputs: []string{"[1,2]", "[3,4]"},
+			wantLines:  []int{1, 2},
+		},
+		{
+			name:       "model column",
+			contents:   "x,model,y\n1,fraud,2\n3,churn,4\n",
+			opts:       CSVOptions{Header: true, ModelColumn: "model", Model: "m"},
+			wantModels: []string{"fraud", "churn"},
+			wantInputs: []string{"[1,2]", "[3,4]"},
+			wantLines:  []int{2, 3},
+		},
+		{
+			name:       "malformed rows skipped",
+			contents:   "model,a,b\nm1,1,2\nm2,x,2\nm3,1\n,1,2\nm4,NaN,1\nm5,3,4\n",
+			opts:       CSVOptions{Header: true, ModelColumn: "model"},
+			wantModels: []string{"m1", "m5"},
+			wantInputs: []string{"[1,2]", "[3,4]"},
+			wantLines:  []int{2, 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got, err := ReadCSVRequests(strings.NewReader(tt.contents), tt.opts)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("ReadCSVRequests returned error: %v", err)
+			}
+			if len(got) != len(tt.wantModels) {
+				t.Fatalf("Expected %d requests, got %d", len(tt.wantModels), len(got))
+			}
+			for i, r := range got {
+				if r.Request.ModelName != tt.wantModels[i] || string(r.Request.InputData) != tt.wantInputs[i] || r.Line != tt.wantLines[i] {
+					t.Errorf("request %d: expected line %d %s %s, got line %d %s %s", i,
+						tt.wantLines[i], tt.wantModels[i], tt.wantInputs[i], r.Line, r.Request.ModelName, r.Request.InputData)
+				}
+			}
+		})
+	}
+}
+
+func TestReadCSVRequests_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts CSVOptions
+	}{
+		{"model column without header", CSVOptions{ModelColumn: "model"}},
+		{"unknown model column", CSVOptions{Header: true, ModelColumn: "name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadCSVRequests(strings.NewReader("model,a\nm,1\n"), tt.opts); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestRunCSV_SendsEachRow(t *testing.T) {
+	// Arrange
+	requests, err := ReadCSVRequests(strings.NewReader("a,b\n1,2\n3,4\n5,6\n"), CSVOptions{Header: true, Model: "m"})
+	if err != nil {
+		t.Fatalf("ReadCSVRequests returned error: %v", err)
+	}
+	calls := 0
+	mockClient := &MockInferenceClient{
+		PredictFunc: func(ctx context.Context, in *pb.PredictRequest, opts ...grpc.CallOption) (*pb.PredictResponse, error) {
+			calls++
+			if string(in.InputData) == "[3,4]" {
+				return nil, status.Error(codes.InvalidArgument, "bad input")
+			}
+			return &pb.PredictResponse{OutputData: []byte(`[0.5]`), Status: "ok"}, nil
+		},
+	}
+	var out bytes.Buffer
+
+	// Act
+	failed, err := RunCSV(mockClient, requests, &out, nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunCSV returned error: %v", err)
+	}
+	if calls != 3 || failed != 1 {
+		t.Errorf("Expected 3 calls with 1 failure, got %d calls and %d failures", calls, failed)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	want := [][]string{
+		{"line", "model", "status", "output", "error"},
+		{"2", "m", "ok", "[0.5]", ""},
+		{"3", "m", "InvalidArgument", "", "rpc error: code = InvalidArgument desc = bad input"},
+		{"4", "m", "ok", "[0.5]", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("Expected %d output rows, got %v", len(want), rows)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d: expected %v, got %v", i, want[i], rows[i])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Schemes of -addr targets that pick the transport security.
+const (
+	schemeInsecure = "grpc"
+	schemeTLS      = "grpcs"
+)
+
+// parseTarget splits a -addr target into the address to dial and whether
+// the connection uses TLS. "grpcs://host:port" uses TLS; "grpc://host:port"
+// and a bare "host:port" don't. Other gRPC targets, such as "dns:///host",
+// are dialed as they are, without TLS.
+func parseTarget(target string) (addr string, useTLS bool, err error) {
+	scheme, rest, ok := strings.Cut(target, "://")
+	if !ok || (scheme != schemeInsecure && scheme != schemeTLS) {
+		return target, false, nil
+	}
+	if rest == "" || strings.Contains(rest, "/") {
+		return "", false, fmt.Errorf("target %q must be %s://host:port", target, scheme)
+	}
+	return rest, scheme == schemeTLS, nil
+}
+
+// transportCredentials returns TLS credentials that trust the system roots,
+// or only the PEM certificates in caFile when set, or insecure credentials
+// when useTLS is false.
+func transportCredentials(useTLS bool, caFile string) (credentials.TransportCredentials, error) {
+	if !useTLS {
+		if caFile != "" {
+			return nil, fmt.Errorf("a CA certificate needs a %s:// target", schemeTLS)
+		}
+		return insecure.NewCredentials(), nil
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pemData, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// dialOptions returns the address to dial for target and the options that
+// secure the connection as its scheme asks, so callers can pass both
+// straight to grpc.NewClient.
+func dialOptions(target, caFile string) (string, []grpc.DialOption, error) {
+	addr, useTLS, err := parseTarget(target)
+	if err != nil {
+		return "", nil, err
+	}
+	creds, err := transportCredentials(useTLS, caFile)
+	if err != nil {
+		return "", nil, err
+	}
+	return addr, []grpc.DialOption{grpc.WithTransportCredentials(creds)}, nil
+}
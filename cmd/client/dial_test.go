@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		target   string
+		wantAddr string
+		wantTLS  bool
+		wantErr  bool
+	}{
+		{"localhost:50051", "localhost:50051", false, false},
+		{"grpc://localhost:50051", "localhost:50051", false, false},
+		{"grpcs://models.example.com:443", "models.example.com:443", true, false},
+		{"dns:///models.example.com:443", "dns:///models.example.com:443", false, false},
+		{"grpcs://", "", false, true},
+		{"grpcs://host:443/path", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			addr, useTLS, err := parseTarget(tt.target)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if addr != tt.wantAddr || useTLS != tt.wantTLS {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tt.wantAddr, tt.wantTLS, addr, useTLS)
+			}
+		})
+	}
+}
+
+func TestTransportCredentials(t *testing.T) {
+	caFile, _ := writeTestCA(t)
+	tests := []struct {
+		name     string
+		useTLS   bool
+		caFile   string
+		wantProt string
+		wantErr  bool
+	}{
+		{"insecure", false, "", "insecure", false},
+		{"system roots", true, "", "tls", false},
+		{"custom CA", true, caFile, "tls", false},
+		{"CA without TLS", false, caFile, "", true},
+		{"missing CA file", true, filepath.Join(t.TempDir(), "missing.pem"), "", true},
+		{"CA file without certificates", true, writeFile(t, "not a certificate"), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := transportCredentials(tt.useTLS, tt.caFile)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && creds.Info().SecurityProtocol != tt.wantProt {
+				t.Errorf("Expected %s credentials, got %s", tt.wantProt, creds.Info().SecurityProtocol)
+			}
+		})
+	}
+}
+
+func TestDialOptions_ConnectsOverTLSWithCustomCA(t *testing.T) {
+	// Arrange: a TLS server whose certificate only the custom CA trusts.
+	caFile, cert := writeTestCA(t)
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	_, port, _ := net.SplitHostPort(lis.Addr().String())
+	target := "grpcs://localhost:" + port
+
+	// Act
+	addr, opts, err := dialOptions(target, caFile)
+	if err != nil {
+		t.Fatalf("dialOptions returned error: %v", err)
+	}
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected the TLS call to succeed, got %v", err)
+	}
+}
+
+func TestDialOptions_InsecureTargetFailsAgainstTLSServer(t *testing.T) {
+	_, cert := writeTestCA(t)
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	addr, opts, err := dialOptions("grpc://"+lis.Addr().String(), "")
+	if err != nil {
+		t.Fatalf("dialOptions returned error: %v", err)
+	}
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err == nil {
+		t.Error("Expected a plaintext call to a TLS server to fail")
+	}
+}
+
+// writeTestCA writes a throwaway self-signed certificate for localhost to a
+// PEM file and returns its path and the certificate with its key.
+func writeTestCA(t *testing.T) (string, tls.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeFile(t, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	return path, tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// writeFile writes contents to a temporary file and returns its path.
+func writeFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "file.pem")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -2,17 +2,29 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"log"
+	"os"
 	"time"
-	"encoding/json"
+
+	"github.com/arhantsg07/ml-inference-system/internal/recording"
 	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
-	serverAddr = flag.String("addr", "localhost:50051", "The server address in the format of host:port")
+	serverAddr = flag.String("addr", "localhost:50051", "The server address as host:port or grpc://host:port, or grpcs://host:port to connect over TLS")
+	caCert     = flag.String("ca-cert", "", "PEM file of CA certificates to trust for a grpcs:// -addr instead of the system roots")
+	replayPath = flag.String("replay", "", "Replay a file recorded with the server's -record-path flag and report differing outputs")
+	retries    = flag.Int("retries", 3, "Number of times to retry a prediction the server rejected as overloaded (RESOURCE_EXHAUSTED)")
+	backoff    = flag.Duration("retry-backoff", 100*time.Millisecond, "Initial delay between retries when the server sends no Retry-After; doubles each attempt")
+	csvPath    = flag.String("csv", "", "Send one prediction per row of this CSV file of feature vectors and write the outputs as CSV")
+	csvOut     = flag.String("csv-out", "", "File the -csv outputs are written to (defaults to stdout)")
+	csvHeader  = flag.Bool("csv-header", true, "Whether the first row of the -csv file is a header")
+	modelCol   = flag.String("csv-model-column", "", "Header name of the -csv column holding each row's model name")
+	model      = flag.String("model", "sample", "Model used for -csv rows without a model column")
+	budget     = flag.Int("csv-retry-budget", 100, "Total retries shared by all -csv rows; rows that would need more are marked RetryBudgetExhausted (negative means unlimited)")
 )
 
 /*
@@ -26,7 +38,7 @@ func MakePrediction(client pb.InferenceClient, req *pb.PredictRequest) {
 	log.Printf("Getting the prediction from the model %s for the input %x", req.ModelName, req.InputData)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	prediction, err := client.Predict(ctx, req)
+	prediction, err := predictWithRetry(ctx, client, req, *retries, *backoff, nil)
 	if err != nil {
 		log.Printf("client.Predict failed: %v", err)
 	}
@@ -41,15 +53,28 @@ func MakePrediction(client pb.InferenceClient, req *pb.PredictRequest) {
  */
 
 func main() {
-	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	conn, err := grpc.NewClient(*serverAddr, opts...)
+	flag.Parse()
+
+	addr, opts, err := dialOptions(*serverAddr, *caCert)
+	if err != nil {
+		log.Fatalf("invalid -addr or -ca-cert: %v", err)
+	}
+	conn, err := grpc.NewClient(addr, opts...)
 	if err != nil {
 		log.Fatalf("fail to dial: %v", err)
 	}
 	defer conn.Close()
 	client := pb.NewInferenceClient(conn)
 
+	if *replayPath != "" {
+		runReplay(client, *replayPath)
+		return
+	}
+	if *csvPath != "" {
+		runCSV(client, *csvPath, *csvOut)
+		return
+	}
+
 	inputArray := []float64{32.0, 54.1, 12.5}
 
 	// Convert array to JSON bytes
@@ -57,9 +82,60 @@ func main() {
 	if err != nil {
 		log.Fatalf("Error marshaling input: %v", err)
 	}
+	if err := ValidateInput(inputBytes); err != nil {
+		log.Fatalf("Invalid input: %v", err)
+	}
 
 	MakePrediction(client, &pb.PredictRequest{
 		ModelName: "sample",
 		InputData: inputBytes,
 	})
 }
+
+func runReplay(client pb.InferenceClient, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("failed to open recording: %v", err)
+	}
+	defer f.Close()
+
+	records, err := recording.Read(f)
+	if err != nil {
+		log.Fatalf("failed to read recording: %v", err)
+	}
+
+	diffs, err := Replay(client, records)
+	if err != nil {
+		log.Fatalf("replay failed: %v", err)
+	}
+	for _, d := range diffs {
+		log.Printf("record %d differs:\n  want: %s\n  got:  %s", d.Index, d.Want, d.Got)
+	}
+	log.Printf("Replayed %d records, %d differed", len(records), len(diffs))
+}
+
+func runCSV(client pb.InferenceClient, path, outPath string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("failed to open CSV: %v", err)
+	}
+	defer f.Close()
+
+	requests, err := ReadCSVRequests(f, CSVOptions{Header: *csvHeader, ModelColumn: *modelCol, Model: *model})
+	if err != nil {
+		log.Fatalf("failed to read CSV: %v", err)
+	}
+
+	out := os.Stdout
+	if outPath != "" {
+		if out, err = os.Create(outPath); err != nil {
+			log.Fatalf("failed to create output CSV: %v", err)
+		}
+		defer out.Close()
+	}
+	failed, err := RunCSV(client, requests, out, newRetryBudget(*budget))
+	if err != nil {
+		log.Fatalf("failed to write output CSV: %v", err)
+	}
+	log.Printf("Sent %d predictions from %s, %d failed", len(requests), path, failed)
+}
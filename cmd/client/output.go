@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/arhantsg07/ml-inference-system/internal/quantize"
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+// DecodeOutput returns the output values of resp, dequantizing them when
+// the server encoded them at reduced precision, joining them when it sent
+// one per line and unpacking them when it sent raw little-endian floats.
+func DecodeOutput(resp *pb.PredictResponse) ([]float64, error) {
+	switch resp.GetOutputEncoding() {
+	case "":
+		var out []float64
+		if err := json.Unmarshal(resp.GetOutputData(), &out); err != nil {
+			return nil, fmt.Errorf("decoding JSON output: %w", err)
+		}
+		return out, nil
+	case "ndjson":
+		var out []float64
+		dec := json.NewDecoder(bytes.NewReader(resp.GetOutputData()))
+		for dec.More() {
+			var v float64
+			if err := dec.Decode(&v); err != nil {
+				return nil, fmt.Errorf("decoding ndjson output: %w", err)
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case "float32-le":
+		data := resp.GetOutputData()
+		if len(data)%4 != 0 {
+			return nil, fmt.Errorf("float32-le output has %d bytes, not a multiple of 4", len(data))
+		}
+		out := make([]float64, len(data)/4)
+		for i := range out {
+			out[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:])))
+		}
+		return out, nil
+	case "float64-le":
+		data := resp.GetOutputData()
+		if len(data)%8 != 0 {
+			return nil, fmt.Errorf("float64-le output has %d bytes, not a multiple of 8", len(data))
+		}
+		out := make([]float64, len(data)/8)
+		for i := range out {
+			out[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[i*8:]))
+		}
+		return out, nil
+	case quantize.Float16:
+		return quantize.DecodeFloat16(resp.GetOutputData())
+	case quantize.Int8:
+		return quantize.DecodeInt8(resp.GetOutputData(), resp.GetQuantScale(), resp.GetQuantZeroPoint()), nil
+	default:
+		return nil, fmt.Errorf("unknown output encoding %q", resp.GetOutputEncoding())
+	}
+}
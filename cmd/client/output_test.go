@@ -0,0 +1,47 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/arhantsg07/ml-inference-system/internal/quantize"
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+func TestDecodeOutput(t *testing.T) {
+	values := []float64{0.125, -2.5, 7}
+	int8Data, scale, zeroPoint := quantize.EncodeInt8(values)
+
+	tests := []struct {
+		name      string
+		resp      *pb.PredictResponse
+		tolerance float64
+	}{
+		{"json", &pb.PredictResponse{OutputData: []byte(`[0.125, -2.5, 7]`)}, 0},
+		{"ndjson", &pb.PredictResponse{OutputEncoding: "ndjson", OutputData: []byte("0.125\n-2.5\n7\n")}, 0},
+		{"float32-le", &pb.PredictResponse{OutputEncoding: "float32-le", OutputData: []byte{0, 0, 0, 0x3e, 0, 0, 0x20, 0xc0, 0, 0, 0xe0, 0x40}}, 0},
+		{"float64-le", &pb.PredictResponse{OutputEncoding: "float64-le", OutputData: []byte{0, 0, 0, 0, 0, 0, 0xc0, 0x3f, 0, 0, 0, 0, 0, 0, 0x04, 0xc0, 0, 0, 0, 0, 0, 0, 0x1c, 0x40}}, 0},
+		{"float16", &pb.PredictResponse{OutputEncoding: quantize.Float16, OutputData: quantize.EncodeFloat16(values)}, 0},
+		{"int8", &pb.PredictResponse{OutputEncoding: quantize.Int8, OutputData: int8Data, QuantScale: scale, QuantZeroPoint: zeroPoint}, scale / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeOutput(tt.resp)
+			if err != nil {
+				t.Fatalf("DecodeOutput returned error: %v", err)
+			}
+			for i, want := range values {
+				if math.Abs(got[i]-want) > tt.tolerance {
+					t.Errorf("value %d: expected %v, got %v", i, want, got[i])
+				}
+			}
+		})
+	}
+
+	if _, err := DecodeOutput(&pb.PredictResponse{OutputEncoding: "bfloat16"}); err == nil {
+		t.Error("Expected an error for an unknown encoding")
+	}
+	if _, err := DecodeOutput(&pb.PredictResponse{OutputEncoding: "float64-le", OutputData: make([]byte, 12)}); err == nil {
+		t.Error("Expected an error for a truncated float64-le output")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arhantsg07/ml-inference-system/internal/recording"
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ReplayDiff describes a recorded call whose replayed outcome differs from
+// the recording.
+type ReplayDiff struct {
+	Index int
+	Want  string
+	Got   string
+}
+
+/*
+* Replay re-sends every recorded Predict call to the server and compares the
+* outcome (status code, and the full response on success) with what was
+* recorded. Records for other methods are skipped.
+ */
+
+func Replay(client pb.InferenceClient, records []recording.Record) ([]ReplayDiff, error) {
+	var diffs []ReplayDiff
+	for i, rec := range records {
+		if rec.Method != pb.Inference_Predict_FullMethodName {
+			continue
+		}
+
+		req := &pb.PredictRequest{}
+		if err := rec.DecodeRequest(req); err != nil {
+			return diffs, fmt.Errorf("record %d: decoding request: %w", i, err)
+		}
+		want := &pb.PredictResponse{}
+		if _, err := rec.DecodeResponse(want); err != nil {
+			return diffs, fmt.Errorf("record %d: decoding response: %w", i, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		got, err := client.Predict(ctx, req)
+		cancel()
+
+		wantCode, gotCode := rec.StatusCode(), status.Code(err)
+		switch {
+		case wantCode != gotCode:
+			diffs = append(diffs, ReplayDiff{
+				Index: i,
+				Want:  fmt.Sprintf("code %s", wantCode),
+				Got:   fmt.Sprintf("code %s: %s", gotCode, status.Convert(err).Message()),
+			})
+		case err == nil && !proto.Equal(want, got):
+			diffs = append(diffs, ReplayDiff{
+				Index: i,
+				Want:  protojson.Format(want),
+				Got:   protojson.Format(got),
+			})
+		}
+	}
+	return diffs, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arhantsg07/ml-inference-system/internal/recording"
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func mustRecord(t *testing.T, req *pb.PredictRequest, resp *pb.PredictResponse, err error) recording.Record {
+	t.Helper()
+	rec, recErr := recording.NewRecord(pb.Inference_Predict_FullMethodName, req, resp, err)
+	if recErr != nil {
+		t.Fatalf("NewRecord returned error: %v", recErr)
+	}
+	return rec
+}
+
+func TestReplay_ReportsDiffs(t *testing.T) {
+	// Arrange: three recorded calls; the server now answers the second one
+	// differently and fails the third one that used to succeed.
+	same := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}
+	changed := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[2]`)}
+	broken := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[3]`)}
+	records := []recording.Record{
+		mustRecord(t, same, &pb.PredictResponse{OutputData: []byte(`[1]`), Status: "ok"}, nil),
+		mustRecord(t, changed, &pb.PredictResponse{OutputData: []byte(`[2]`), Status: "ok"}, nil),
+		mustRecord(t, broken, &pb.PredictResponse{OutputData: []byte(`[3]`), Status: "ok"}, nil),
+		{Method: "/inference.Inference/Other", Request: []byte(`{}`), Code: "OK"},
+	}
+
+	calls := 0
+	mockClient := &MockInferenceClient{
+		PredictFunc: func(ctx context.Context, in *pb.PredictRequest, opts ...grpc.CallOption) (*pb.PredictResponse, error) {
+			calls++
+			switch string(in.InputData) {
+			case "[1]":
+				return &pb.PredictResponse{OutputData: []byte(`[1]`), Status: "ok"}, nil
+			case "[2]":
+				return &pb.PredictResponse{OutputData: []byte(`[2.5]`), Status: "ok"}, nil
+			default:
+				return nil, status.Error(codes.Unavailable, "backend down")
+			}
+		},
+	}
+
+	// Act
+	diffs, err := Replay(mockClient, records)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Replay returned error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected only Predict records to be replayed (3), got %d", calls)
+	}
+	if len(diffs) != 2 {
+		t.Fatalf("Expected 2 diffs, got %d: %+v", len(diffs), diffs)
+	}
+	if diffs[0].Index != 1 || diffs[1].Index != 2 {
+		t.Errorf("Expected diffs for records 1 and 2, got %d and %d", diffs[0].Index, diffs[1].Index)
+	}
+	if diffs[1].Want != "code OK" {
+		t.Errorf("Expected status diff for record 2, got %+v", diffs[1])
+	}
+}
+
+func TestReplay_MatchingErrorIsNotADiff(t *testing.T) {
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[]`)}
+	records := []recording.Record{
+		mustRecord(t, req, nil, status.Error(codes.InvalidArgument, "input data cannot be empty")),
+	}
+	mockClient := &MockInferenceClient{
+		PredictFunc: func(ctx context.Context, in *pb.PredictRequest, opts ...grpc.CallOption) (*pb.PredictResponse, error) {
+			return nil, status.Error(codes.InvalidArgument, "input data cannot be empty")
+		},
+	}
+
+	diffs, err := Replay(mockClient, records)
+	if err != nil {
+		t.Fatalf("Replay returned error: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no diffs, got %+v", diffs)
+	}
+}
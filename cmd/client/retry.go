@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errRetryBudgetExhausted marks a call that was not retried because the
+// shared retry budget ran out.
+var errRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// retryBudget caps the retries shared by all the calls of a bulk run, so a
+// few failing items can't spend the time meant for the rest. A nil
+// retryBudget is unlimited.
+type retryBudget struct {
+	remaining atomic.Int64
+}
+
+// newRetryBudget returns a budget of n retries, or nil when n is negative.
+func newRetryBudget(n int) *retryBudget {
+	if n < 0 {
+		return nil
+	}
+	b := &retryBudget{}
+	b.remaining.Store(int64(n))
+	return b
+}
+
+// take spends one retry and reports whether one was left.
+func (b *retryBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	return b.remaining.Add(-1) >= 0
+}
+
+// predictWithRetry calls Predict, retrying up to retries times while the
+// server answers ResourceExhausted. It waits for the delay in the server's
+// google.rpc.RetryInfo detail when there is one, and for backoff (doubling
+// each attempt) otherwise. A retry that would outlast ctx's deadline is not
+// attempted. Each retry is taken from budget; once it is spent, the error
+// is wrapped with errRetryBudgetExhausted.
+func predictWithRetry(ctx context.Context, client pb.InferenceClient, req *pb.PredictRequest, retries int, backoff time.Duration, budget *retryBudget) (*pb.PredictResponse, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Predict(ctx, req)
+		if status.Code(err) != codes.ResourceExhausted || attempt >= retries {
+			return resp, err
+		}
+		if !budget.take() {
+			return nil, fmt.Errorf("%w: %w", errRetryBudgetExhausted, err)
+		}
+
+		delay, ok := retryAfter(err)
+		if !ok {
+			delay = backoff << attempt
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			log.Printf("Not retrying: server asked to wait %v, past the deadline", delay)
+			return nil, err
+		}
+		log.Printf("Server is overloaded, retrying in %v (attempt %d/%d)", delay, attempt+1, retries)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryAfter returns the delay from err's RetryInfo detail, if it has one.
+func retryAfter(err error) (time.Duration, bool) {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
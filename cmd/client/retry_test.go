@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// overloaded returns a ResourceExhausted error asking the client to retry
+// after delay.
+func overloaded(t *testing.T, delay time.Duration) error {
+	t.Helper()
+	st, err := status.New(codes.ResourceExhausted, "backend rate limited").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	if err != nil {
+		t.Fatalf("failed to attach RetryInfo: %v", err)
+	}
+	return st.Err()
+}
+
+func TestPredictWithRetry_HonorsRetryAfter(t *testing.T) {
+	// Arrange: the first call is rejected with a 50ms Retry-After.
+	var calls []time.Time
+	mockClient := &MockInferenceClient{
+		PredictFunc: func(ctx context.Context, in *pb.PredictRequest, opts ...grpc.CallOption) (*pb.PredictResponse, error) {
+			calls = append(calls, time.Now())
+			if len(calls) == 1 {
+				return nil, overloaded(t, 50*time.Millisecond)
+			}
+			return &pb.PredictResponse{Status: "ok"}, nil
+		},
+	}
+
+	// Act: the fallback backoff is much shorter than the server's delay.
+	resp, err := predictWithRetry(context.Background(), mockClient, &pb.PredictRequest{ModelName: "m"}, 3, time.Millisecond, nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if resp.GetStatus() != "ok" || len(calls) != 2 {
+		t.Fatalf("Expected success on the second call, got %d calls", len(calls))
+	}
+	if waited := calls[1].Sub(calls[0]); waited < 50*time.Millisecond {
+		t.Errorf("Expected to wait the server's Retry-After of 50ms, retried after %v", waited)
+	}
+}
+
+func TestPredictWithRetry_GivesUp(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       func(t *testing.T) error
+		retries   int
+		timeout   time.Duration
+		wantCalls int
+	}{
+		{"retries exhausted", func(t *testing.T) error { return overloaded(t, time.Millisecond) }, 2, time.Second, 3},
+		{"delay past deadline", func(t *testing.T) error { return overloaded(t, time.Minute) }, 3, time.Second, 1},
+		{"not retryable", func(t *testing.T) error { return status.Error(codes.InvalidArgument, "bad input") }, 3, time.Second, 1},
+		{"no detail uses backoff", func(t *testing.T) error { return status.Error(codes.ResourceExhausted, "queue full") }, 1, time.Second, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			mockClient := &MockInferenceClient{
+				PredictFunc: func(ctx context.Context, in *pb.PredictRequest, opts ...grpc.CallOption) (*pb.PredictResponse, error) {
+					calls++
+					return nil, tt.err(t)
+				},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			_, err := predictWithRetry(ctx, mockClient, &pb.PredictRequest{ModelName: "m"}, tt.retries, time.Millisecond, nil)
+
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestRunCSV_SharedRetryBudget(t *testing.T) {
+	// Arrange: every row but the second is always rejected as overloaded.
+	oldBackoff := *backoff
+	*backoff = time.Millisecond
+	defer func() { *backoff = oldBackoff }()
+	requests, err := ReadCSVRequests(strings.NewReader("1\n2\n3\n4\n"), CSVOptions{Model: "m"})
+	if err != nil {
+		t.Fatalf("ReadCSVRequests returned error: %v", err)
+	}
+	calls := make(map[string]int)
+	mockClient := &MockInferenceClient{
+		PredictFunc: func(ctx context.Context, in *pb.PredictRequest, opts ...grpc.CallOption) (*pb.PredictResponse, error) {
+			calls[string(in.InputData)]++
+			if string(in.InputData) == "[2]" {
+				return &pb.PredictResponse{OutputData: []byte(`[1]`), Status: "ok"}, nil
+			}
+			return nil, status.Error(codes.ResourceExhausted, "overloaded")
+		},
+	}
+	var out bytes.Buffer
+
+	// Act: -retries allows 3 retries per row, but the run only has 2.
+	failed, err := RunCSV(mockClient, requests, &out, newRetryBudget(2))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RunCSV returned error: %v", err)
+	}
+	total := 0
+	for _, n := range calls {
+		total += n
+	}
+	if retried := total - len(requests); retried != 2 {
+		t.Errorf("Expected the budget to cap retries at 2, got %d (calls %v)", retried, calls)
+	}
+	if failed != 3 {
+		t.Errorf("Expected 3 failed rows, got %d", failed)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	wantStatus := []string{"RetryBudgetExhausted", "ok", "RetryBudgetExhausted", "RetryBudgetExhausted"}
+	for i, want := range wantStatus {
+		if got := rows[i+1][2]; got != want {
+			t.Errorf("row %d: expected status %s, got %s", i+1, want, got)
+		}
+	}
+}
+
+func TestRetryBudget_Unlimited(t *testing.T) {
+	b := newRetryBudget(-1)
+	for range 1000 {
+		if !b.take() {
+			t.Fatal("Expected a negative budget to be unlimited")
+		}
+	}
+}
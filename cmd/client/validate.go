@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+/*
+* ValidateInput checks that data is what the server expects in InputData: a
+* non-empty JSON array of finite numbers. Running it before the RPC turns a
+* round trip ending in InvalidArgument into an immediate local error.
+ */
+
+func ValidateInput(data []byte) error {
+	var values []float64
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("input must be a JSON array of numbers: %w", err)
+	}
+	if len(values) == 0 {
+		return errors.New("input must not be empty")
+	}
+	for i, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("input element %d is not a finite number", i)
+		}
+	}
+	return nil
+}
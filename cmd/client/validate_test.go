@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestValidateInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"numbers", `[32.0, 54.1, 12.5]`, false},
+		{"integers and negatives", `[1, -2, 0]`, false},
+		{"single element", `[0.5]`, false},
+		{"empty array", `[]`, true},
+		{"not json", "\x01\x02\x03", true},
+		{"object", `{"input": [1, 2]}`, true},
+		{"strings", `["1", "2"]`, true},
+		{"nested", `[[1, 2], [3, 4]]`, true},
+		{"null element", `[1, null]`, false}, // null decodes as 0, as on the server
+		{"out of range", `[1e400]`, true},
+		{"NaN literal", `[NaN]`, true},
+		{"null", `null`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateInput([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateInput(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// requireAdmin wraps an admin endpoint so that it only accepts POST requests
+// carrying "Authorization: Bearer <admin token>". Admin endpoints are
+// disabled entirely when no token is configured.
+func (s *server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return s.requireAdminMethod(http.MethodPost, next)
+}
+
+// requireAdminMethod is requireAdmin for endpoints served on another
+// method, such as read-only GET endpoints.
+func (s *server) requireAdminMethod(method string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if s.adminToken == "" {
+			http.Error(w, "admin endpoints are disabled; set -admin-token", http.StatusForbidden)
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// drainHandler stops the server from accepting new Predict calls while
+// letting in-flight ones finish.
+func (s *server) drainHandler(w http.ResponseWriter, r *http.Request) {
+	s.draining.Store(true)
+	log.Printf("Server draining: rejecting new requests")
+	w.Write([]byte("draining"))
+}
+
+// undrainHandler resumes accepting Predict calls.
+func (s *server) undrainHandler(w http.ResponseWriter, r *http.Request) {
+	s.draining.Store(false)
+	log.Printf("Server undrained: accepting new requests")
+	w.Write([]byte("serving"))
+}
+
+// logLevelHandler switches body logging on or off without a restart. The
+// level query parameter is "verbose" or "quiet".
+func (s *server) logLevelHandler(w http.ResponseWriter, r *http.Request) {
+	level := r.URL.Query().Get("level")
+	switch level {
+	case "verbose":
+		s.verbose.Store(true)
+	case "quiet":
+		s.verbose.Store(false)
+	default:
+		http.Error(w, `level must be "verbose" or "quiet"`, http.StatusBadRequest)
+		return
+	}
+	log.Printf("Log level set to %s", level)
+	w.Write([]byte(level))
+}
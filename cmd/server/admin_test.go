@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminRequest sends a request to an admin handler and returns the status.
+func adminRequest(h http.HandlerFunc, method, token string) int {
+	req := httptest.NewRequest(method, "/", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	return rec.Code
+}
+
+func TestDrainAndUndrain(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":[1],"status":"ok"}`))
+	s.adminToken = "secret"
+	s.serving.Store(true)
+	drain := s.requireAdmin(s.drainHandler)
+	undrain := s.requireAdmin(s.undrainHandler)
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}
+
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		s.readyHandler(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return rec.Code
+	}
+
+	// Act: drain
+	if code := adminRequest(drain, http.MethodPost, "secret"); code != http.StatusOK {
+		t.Fatalf("Expected drain to succeed, got %d", code)
+	}
+
+	// Assert: not ready and new requests rejected
+	if code := ready(); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected readiness to fail while drained, got %d", code)
+	}
+	if _, err := s.Predict(context.Background(), req); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable while drained, got %v", err)
+	}
+
+	// Act: undrain
+	if code := adminRequest(undrain, http.MethodPost, "secret"); code != http.StatusOK {
+		t.Fatalf("Expected undrain to succeed, got %d", code)
+	}
+
+	// Assert: ready again
+	if code := ready(); code != http.StatusOK {
+		t.Errorf("Expected readiness to recover after undrain, got %d", code)
+	}
+	if _, err := s.Predict(context.Background(), req); err != nil {
+		t.Errorf("Expected Predict to succeed after undrain, got %v", err)
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	s := &server{adminToken: "secret"}
+	h := s.requireAdmin(s.drainHandler)
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		want   int
+	}{
+		{"valid token", http.MethodPost, "secret", http.StatusOK},
+		{"missing token", http.MethodPost, "", http.StatusUnauthorized},
+		{"wrong token", http.MethodPost, "guess", http.StatusUnauthorized},
+		{"wrong method", http.MethodGet, "secret", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.draining.Store(false)
+			if got := adminRequest(h, tt.method, tt.token); got != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, got)
+			}
+			if drained := s.draining.Load(); drained != (tt.want == http.StatusOK) {
+				t.Errorf("Unexpected draining state %v", drained)
+			}
+		})
+	}
+
+	disabled := &server{}
+	if got := adminRequest(disabled.requireAdmin(disabled.drainHandler), http.MethodPost, ""); got != http.StatusForbidden {
+		t.Errorf("Expected admin endpoints to be disabled without a token, got %d", got)
+	}
+}
+
+func TestLogLevelHandler_TogglesBodyLogging(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":[42],"status":"ok"}`))
+	s.adminToken = "secret"
+	h := s.requireAdmin(s.logLevelHandler)
+	setLevel := func(level string) int {
+		req := httptest.NewRequest(http.MethodPost, "/loglevel?level="+level, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		h(rec, req)
+		return rec.Code
+	}
+	predictLogs := func() string {
+		var buf bytes.Buffer
+		interceptor := loggingInterceptor(&buf, 0)
+		handler := func(ctx context.Context, req any) (any, error) {
+			return s.Predict(ctx, req.(*pb.PredictRequest))
+		}
+		if _, err := interceptor(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[7]`)}, &grpc.UnaryServerInfo{}, handler); err != nil {
+			t.Fatalf("Predict returned error: %v", err)
+		}
+		return buf.String()
+	}
+
+	for _, tt := range []struct {
+		level      string
+		wantBodies bool
+	}{
+		{"verbose", true},
+		{"quiet", false},
+		{"verbose", true},
+	} {
+		// Act
+		if code := setLevel(tt.level); code != http.StatusOK {
+			t.Fatalf("Expected level %q to be accepted, got %d", tt.level, code)
+		}
+		logs := predictLogs()
+
+		// Assert
+		if got := strings.Contains(logs, "Request body:"); got != tt.wantBodies {
+			t.Errorf("level=%s: expected body dump logged=%v, logs:\n%s", tt.level, tt.wantBodies, logs)
+		}
+	}
+
+	if code := setLevel("debug"); code != http.StatusBadRequest {
+		t.Errorf("Expected unknown level to be rejected, got %d", code)
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// minAlertCalls is the number of backend calls a window needs before
+	// its error rate can alert, so one failure at low traffic doesn't.
+	minAlertCalls = 10
+	// alertWebhookTimeout bounds each webhook call.
+	alertWebhookTimeout = 10 * time.Second
+)
+
+var (
+	backendErrorRate = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "inference_backend_error_rate",
+			Help: "Share of backend calls that failed over the last -alert-window",
+		},
+	)
+	errorRateAlerts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_error_rate_alerts_total",
+			Help: "Sustained backend error rate alerts, by webhook result (sent, failed, skipped)",
+		},
+		[]string{"result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(backendErrorRate, errorRateAlerts)
+}
+
+// errorRateAlert tracks the outcome of backend calls over a sliding window
+// and fires when the error rate stays above threshold for sustain, posting
+// an alertPayload to webhook at most once per cooldown. A nil errorRateAlert
+// tracks nothing.
+type errorRateAlert struct {
+	threshold float64
+	sustain   time.Duration
+	cooldown  time.Duration
+	webhook   string
+	client    *http.Client
+	now       func() time.Time
+
+	// tasks tracks webhook calls so shutdown can wait for them.
+	tasks *asyncTasks
+
+	mu      sync.Mutex
+	buckets []rateBucket // one per second of the window, indexed by second
+
+	// breachSince and lastFired are only touched by the evaluating
+	// goroutine. sending is set while a webhook call is in flight.
+	breachSince time.Time
+	lastFired   time.Time
+	sending     atomic.Bool
+}
+
+// rateBucket counts the backend calls that finished in one second.
+type rateBucket struct {
+	second   int64
+	calls    int
+	failures int
+}
+
+// alertPayload is the JSON body posted to -alert-webhook.
+type alertPayload struct {
+	Alert         string    `json:"alert"`
+	ErrorRate     float64   `json:"error_rate"`
+	Threshold     float64   `json:"threshold"`
+	Calls         int       `json:"calls"`
+	WindowSeconds float64   `json:"window_seconds"`
+	Since         time.Time `json:"since"`
+	FiredAt       time.Time `json:"fired_at"`
+}
+
+// newErrorRateAlert returns an evaluator for the backend error rate over
+// window, or nil when threshold is 0. An empty webhook only logs and counts
+// alerts.
+func newErrorRateAlert(threshold float64, window, sustain, cooldown time.Duration, webhook string) (*errorRateAlert, error) {
+	if threshold == 0 {
+		return nil, nil
+	}
+	if threshold < 0 || threshold >= 1 {
+		return nil, fmt.Errorf("-alert-error-rate must be above 0 and below 1, got %v", threshold)
+	}
+	if window < time.Second {
+		return nil, fmt.Errorf("-alert-window must be at least 1s, got %v", window)
+	}
+	if sustain < 0 || cooldown < 0 {
+		return nil, fmt.Errorf("-alert-for and -alert-cooldown must not be negative")
+	}
+	return &errorRateAlert{
+		threshold: threshold,
+		sustain:   sustain,
+		cooldown:  cooldown,
+		webhook:   webhook,
+		client:    &http.Client{Timeout: alertWebhookTimeout},
+		now:       time.Now,
+		buckets:   make([]rateBucket, int((window+time.Second-1)/time.Second)),
+	}, nil
+}
+
+// isBackendFailure reports whether a backend call's error counts toward the
+// error rate. Rejected inputs, calls canceled by the client and calls the
+// limiter turned away before they reached the backend don't.
+func isBackendFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.OK, codes.InvalidArgument, codes.Canceled:
+		return false
+	}
+	return !isQueueRejection(err)
+}
+
+// record counts a finished backend call.
+func (a *errorRateAlert) record(failed bool) {
+	if a == nil {
+		return
+	}
+	second := a.now().Unix()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b := &a.buckets[second%int64(len(a.buckets))]
+	if b.second != second {
+		*b = rateBucket{second: second}
+	}
+	b.calls++
+	if failed {
+		b.failures++
+	}
+}
+
+// rate returns the error rate and number of calls over the window.
+func (a *errorRateAlert) rate() (float64, int) {
+	oldest := a.now().Unix() - int64(len(a.buckets)) + 1
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var calls, failures int
+	for _, b := range a.buckets {
+		if b.second >= oldest {
+			calls += b.calls
+			failures += b.failures
+		}
+	}
+	if calls == 0 {
+		return 0, 0
+	}
+	return float64(failures) / float64(calls), calls
+}
+
+// run evaluates the error rate every interval until ctx is done.
+func (a *errorRateAlert) run(ctx context.Context, interval time.Duration) {
+	if a == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.evaluate()
+		}
+	}
+}
+
+// evaluate exports the current error rate and fires the alert once it has
+// been above the threshold for the sustain period and the cooldown since the
+// last alert has passed.
+func (a *errorRateAlert) evaluate() {
+	rate, calls := a.rate()
+	backendErrorRate.Set(rate)
+	now := a.now()
+	if rate <= a.threshold || calls < minAlertCalls {
+		if !a.breachSince.IsZero() {
+			log.Printf("Backend error rate back to %.1f%%, at or below the %.1f%% alert threshold", rate*100, a.threshold*100)
+		}
+		a.breachSince = time.Time{}
+		return
+	}
+	if a.breachSince.IsZero() {
+		a.breachSince = now
+	}
+	if now.Sub(a.breachSince) < a.sustain {
+		return
+	}
+	if !a.lastFired.IsZero() && now.Sub(a.lastFired) < a.cooldown {
+		return
+	}
+	a.lastFired = now
+	log.Printf("ALERT: backend error rate %.1f%% over %d calls has exceeded %.1f%% since %s",
+		rate*100, calls, a.threshold*100, a.breachSince.Format(time.RFC3339))
+	a.fire(alertPayload{
+		Alert:         "backend_error_rate",
+		ErrorRate:     rate,
+		Threshold:     a.threshold,
+		Calls:         calls,
+		WindowSeconds: float64(len(a.buckets)),
+		Since:         a.breachSince,
+		FiredAt:       now,
+	})
+}
+
+// fire posts payload to the webhook in the background, so a slow webhook
+// never delays evaluation. An alert raised while the previous webhook call is
+// still in flight is skipped.
+func (a *errorRateAlert) fire(payload alertPayload) {
+	if a.webhook == "" || !a.sending.CompareAndSwap(false, true) {
+		errorRateAlerts.WithLabelValues("skipped").Inc()
+		return
+	}
+	a.tasks.Go(func() {
+		defer a.sending.Store(false)
+		if err := a.post(payload); err != nil {
+			log.Printf("Alert webhook failed: %v", err)
+			errorRateAlerts.WithLabelValues("failed").Inc()
+			return
+		}
+		errorRateAlerts.WithLabelValues("sent").Inc()
+	})
+}
+
+func (a *errorRateAlert) post(payload alertPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := a.client.Post(a.webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
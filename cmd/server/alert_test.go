@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newAlertWebhook returns the URL of a webhook that delivers each payload it
+// receives on the returned channel.
+func newAlertWebhook(t *testing.T) (string, <-chan alertPayload) {
+	t.Helper()
+	payloads := make(chan alertPayload, 10)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p alertPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("webhook got an undecodable body: %v", err)
+		}
+		payloads <- p
+	}))
+	t.Cleanup(webhook.Close)
+	return webhook.URL, payloads
+}
+
+func TestPredict_HighErrorRateFiresWebhook(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model crashed", http.StatusInternalServerError)
+	})
+	url, payloads := newAlertWebhook(t)
+	alert, err := newErrorRateAlert(0.5, time.Minute, 0, time.Hour, url)
+	if err != nil {
+		t.Fatalf("newErrorRateAlert returned error: %v", err)
+	}
+	alert.tasks = &s.async
+	s.errorAlert = alert
+	before := testutil.ToFloat64(errorRateAlerts.WithLabelValues("sent"))
+
+	// Act
+	for range minAlertCalls {
+		s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+	}
+	alert.evaluate()
+	s.async.wg.Wait()
+
+	// Assert
+	select {
+	case p := <-payloads:
+		if p.Alert != "backend_error_rate" || p.ErrorRate != 1 || p.Calls != minAlertCalls || p.Threshold != 0.5 {
+			t.Errorf("Unexpected payload %+v", p)
+		}
+	default:
+		t.Fatal("Expected the webhook to be called")
+	}
+	if got := testutil.ToFloat64(backendErrorRate); got != 1 {
+		t.Errorf("Expected an error rate of 1, got %v", got)
+	}
+	if got := testutil.ToFloat64(errorRateAlerts.WithLabelValues("sent")) - before; got != 1 {
+		t.Errorf("Expected 1 alert sent, got %v", got)
+	}
+}
+
+func TestErrorRateAlert_SustainAndCooldown(t *testing.T) {
+	// Arrange
+	url, payloads := newAlertWebhook(t)
+	alert, err := newErrorRateAlert(0.5, 10*time.Second, 5*time.Second, time.Minute, url)
+	if err != nil {
+		t.Fatalf("newErrorRateAlert returned error: %v", err)
+	}
+	var tasks asyncTasks
+	alert.tasks = &tasks
+	now := time.Unix(1000, 0)
+	alert.now = func() time.Time { return now }
+	// tick records a second of calls, 3 in 4 of them failed, and evaluates.
+	tick := func() {
+		for i := range 4 * minAlertCalls {
+			alert.record(i%4 != 0)
+		}
+		alert.evaluate()
+		tasks.wg.Wait()
+		now = now.Add(time.Second)
+	}
+
+	// Act & Assert
+	for range 5 {
+		tick()
+	}
+	if len(payloads) != 0 {
+		t.Fatalf("Expected no alert before the error rate was sustained for 5s, got %d", len(payloads))
+	}
+	tick()
+	if len(payloads) != 1 {
+		t.Fatalf("Expected an alert once sustained for 5s, got %d", len(payloads))
+	}
+	if p := <-payloads; p.ErrorRate != 0.75 || !p.Since.Equal(time.Unix(1000, 0)) {
+		t.Errorf("Unexpected payload %+v", p)
+	}
+	for range 10 {
+		tick()
+	}
+	if len(payloads) != 0 {
+		t.Errorf("Expected no repeat alert within the cooldown, got %d", len(payloads))
+	}
+}
+
+func TestErrorRateAlert_IgnoresLowRatesAndTraffic(t *testing.T) {
+	tests := []struct {
+		name     string
+		calls    int
+		failures int
+	}{
+		{"rate below threshold", 20, 5},
+		{"too few calls", minAlertCalls - 1, minAlertCalls - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			url, payloads := newAlertWebhook(t)
+			alert, err := newErrorRateAlert(0.5, time.Minute, 0, 0, url)
+			if err != nil {
+				t.Fatalf("newErrorRateAlert returned error: %v", err)
+			}
+
+			// Act
+			for i := range tt.calls {
+				alert.record(i < tt.failures)
+			}
+			alert.evaluate()
+
+			// Assert
+			if !alert.breachSince.IsZero() || len(payloads) != 0 {
+				t.Errorf("Expected no alert, got %d", len(payloads))
+			}
+		})
+	}
+}
+
+func TestErrorRateAlert_WindowSlides(t *testing.T) {
+	alert, _ := newErrorRateAlert(0.5, 3*time.Second, 0, 0, "")
+	now := time.Unix(1000, 0)
+	alert.now = func() time.Time { return now }
+
+	alert.record(true)
+	now = now.Add(2 * time.Second)
+	alert.record(false)
+	if rate, calls := alert.rate(); rate != 0.5 || calls != 2 {
+		t.Errorf("Expected rate 0.5 over 2 calls, got %v over %d", rate, calls)
+	}
+	now = now.Add(time.Second)
+	if rate, calls := alert.rate(); rate != 0 || calls != 1 {
+		t.Errorf("Expected the failure to leave the window, got %v over %d", rate, calls)
+	}
+}
+
+func TestNewErrorRateAlert(t *testing.T) {
+	if a, err := newErrorRateAlert(0, time.Minute, 0, 0, ""); a != nil || err != nil {
+		t.Errorf("Expected no evaluator for a zero threshold, got %v, %v", a, err)
+	}
+	for _, threshold := range []float64{-0.1, 1, 1.5} {
+		if _, err := newErrorRateAlert(threshold, time.Minute, 0, 0, ""); err == nil {
+			t.Errorf("Expected an error for threshold %v", threshold)
+		}
+	}
+	if _, err := newErrorRateAlert(0.5, time.Millisecond, 0, 0, ""); err == nil {
+		t.Error("Expected an error for a window under 1s")
+	}
+}
+
+func TestIsBackendFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{status.Error(codes.InvalidArgument, "bad input"), false},
+		{status.Error(codes.Canceled, "canceled"), false},
+		{status.Error(codes.Unavailable, "down"), true},
+		{status.Error(codes.DeadlineExceeded, "slow"), true},
+		{status.Error(codes.Internal, "crashed"), true},
+		{rateLimitedError("", nil), true},
+		{queueRejection(status.New(codes.ResourceExhausted, "request queue is full")), false},
+		{queueRejection(status.New(codes.DeadlineExceeded, "deadline exceeded")), false},
+	}
+	for _, tt := range tests {
+		if got := isBackendFailure(tt.err); got != tt.want {
+			t.Errorf("isBackendFailure(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
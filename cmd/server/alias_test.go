@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+func TestPredict_ModelAliases(t *testing.T) {
+	tests := []struct {
+		name        string
+		model       string
+		wantBackend string
+	}{
+		{"aliased", "sentiment", "sentiment-v3"},
+		{"not aliased", "sentiment-v2", "sentiment-v2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var sent InputData
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&sent)
+				staticBackend(`{"status":"ok","output":[1,2]}`)(w, r)
+			})
+			cfg, err := loadConfig(writeConfig(t, `
+aliases:
+  sentiment: sentiment-v3
+models:
+  sentiment-v3:
+    output_length: 2
+`))
+			if err != nil {
+				t.Fatalf("loadConfig failed: %v", err)
+			}
+			s.config = cfg
+
+			// Act
+			resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: tt.model, InputData: []byte(`[1]`)})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict failed: %v", err)
+			}
+			if sent.ModelName != tt.wantBackend {
+				t.Errorf("Expected the backend to get model %q, got %q", tt.wantBackend, sent.ModelName)
+			}
+			if resp.GetModelName() != tt.model {
+				t.Errorf("Expected the response to name the requested model %q, got %q", tt.model, resp.GetModelName())
+			}
+		})
+	}
+}
+
+func TestPredict_AliasUsesResolvedModelConfig(t *testing.T) {
+	// Arrange: the resolved model's output_length rejects this response.
+	s := newTestServer(t, staticBackend(`{"status":"ok","output":[1,2,3]}`))
+	s.config = Config{
+		Aliases: map[string]string{"sentiment": "sentiment-v3"},
+		Models:  map[string]ModelConfig{"sentiment-v3": {OutputLength: 2}},
+	}
+
+	// Act
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "sentiment", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err == nil {
+		t.Error("Expected the resolved model's output_length to apply")
+	}
+}
+
+func TestEnsemblePredict_ResolvesMemberAliases(t *testing.T) {
+	// Arrange: only the resolved model is known to the backend, and its
+	// output_length rejects anything but two values.
+	s := newTestServer(t, ensembleBackend(map[string][]float64{"sentiment-v3": {1, 2}, "b": {3, 4}}))
+	s.config = Config{
+		Aliases: map[string]string{"sentiment": "sentiment-v3"},
+		Models:  map[string]ModelConfig{"sentiment-v3": {OutputLength: 2}},
+	}
+
+	// Act
+	resp, err := s.EnsemblePredict(context.Background(), &pb.EnsembleRequest{ModelNames: []string{"sentiment", "b"}, InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("EnsemblePredict failed: %v", err)
+	}
+	for _, member := range resp.GetMembers() {
+		if member.GetErrorCode() != 0 {
+			t.Errorf("Expected member %s to succeed, got %q", member.GetModelName(), member.GetError())
+		}
+	}
+	if got := resp.GetMembers()[0].GetModelName(); got != "sentiment" {
+		t.Errorf("Expected the member to name the requested model, got %q", got)
+	}
+	if got := string(resp.GetOutputData()); got != "[2,3]" {
+		t.Errorf("Expected the mean of both members, got %s", got)
+	}
+}
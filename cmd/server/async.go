@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// asyncTasks tracks goroutines that outlive the request that started them,
+// such as detached coalesced backend calls and sink flushes, so shutdown can
+// wait for them instead of losing their writes. A nil asyncTasks tracks
+// nothing.
+type asyncTasks struct {
+	wg      sync.WaitGroup
+	pending atomic.Int64
+}
+
+// track registers a task started elsewhere; call the returned function
+// when it finishes.
+func (a *asyncTasks) track() (done func()) {
+	if a == nil {
+		return func() {}
+	}
+	a.wg.Add(1)
+	a.pending.Add(1)
+	return func() {
+		a.pending.Add(-1)
+		a.wg.Done()
+	}
+}
+
+// Go runs f in a tracked goroutine.
+func (a *asyncTasks) Go(f func()) {
+	done := a.track()
+	go func() {
+		defer done()
+		f()
+	}()
+}
+
+// wait blocks until every tracked task has finished or ctx is done, and
+// returns the number of tasks still running.
+func (a *asyncTasks) wait(ctx context.Context) int64 {
+	if a == nil {
+		return 0
+	}
+	finished := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return 0
+	case <-ctx.Done():
+		return a.pending.Load()
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAsyncTasks_WaitsForSlowTask(t *testing.T) {
+	// Arrange
+	var tasks asyncTasks
+	var finished atomic.Bool
+	tasks.Go(func() {
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(true)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Act
+	pending := tasks.wait(ctx)
+
+	// Assert
+	if pending != 0 || !finished.Load() {
+		t.Errorf("Expected wait to return after the task finished, got %d pending, finished=%v", pending, finished.Load())
+	}
+}
+
+func TestAsyncTasks_WaitIsBounded(t *testing.T) {
+	// Arrange
+	var tasks asyncTasks
+	release := make(chan struct{})
+	defer close(release)
+	tasks.Go(func() { <-release })
+	tasks.Go(func() {})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Act
+	start := time.Now()
+	pending := tasks.wait(ctx)
+
+	// Assert
+	if pending != 1 {
+		t.Errorf("Expected 1 task still pending, got %d", pending)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected wait to give up at the deadline, took %v", elapsed)
+	}
+}
+
+func TestRequestCoalescer_TracksDetachedCalls(t *testing.T) {
+	// Arrange: the only caller gives up while the shared call keeps running.
+	var tasks asyncTasks
+	c := &requestCoalescer{tasks: &tasks}
+	var finished atomic.Bool
+	ctx, cancelCaller := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	go func() {
+		<-started
+		cancelCaller()
+	}()
+	c.do(ctx, "key", func(ctx context.Context) (*APIResponse, error) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(true)
+		return &APIResponse{}, nil
+	})
+
+	// Act
+	waitCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	pending := tasks.wait(waitCtx)
+
+	// Assert
+	if pending != 0 || !finished.Load() {
+		t.Errorf("Expected shutdown to wait for the detached call, got %d pending, finished=%v", pending, finished.Load())
+	}
+}
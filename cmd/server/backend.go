@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultBackendURL = "http://localhost:8080"
+
+// backendTarget is where a backend call is sent and the credentials it
+// carries.
+type backendTarget struct {
+	// URL is the backend base URL; "/predict" is appended per call (see
+	// backendEndpoint).
+	URL string
+	// Token, when non-empty, is sent as "Authorization: Bearer <token>".
+	Token string
+}
+
+// backendProvider resolves the backend target for each call, so URLs and
+// tokens can rotate without a restart. Implementations must be safe for
+// concurrent use; a secrets manager can be plugged in by setting
+// server.backend.
+type backendProvider interface {
+	backend(ctx context.Context) (backendTarget, error)
+}
+
+// envBackend reads the target from MODEL_SERVER_URL and the token from
+// MODEL_SERVER_TOKEN, or from the file named by MODEL_SERVER_TOKEN_FILE.
+// Resolved targets are cached for ttl so the file isn't read on every call.
+type envBackend struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	cached  backendTarget
+	expires time.Time
+}
+
+func newEnvBackend(ttl time.Duration) *envBackend {
+	return &envBackend{ttl: ttl}
+}
+
+func (e *envBackend) backend(ctx context.Context) (backendTarget, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if time.Now().Before(e.expires) {
+		return e.cached, nil
+	}
+
+	target, err := backendFromEnv()
+	if err != nil {
+		return backendTarget{}, err
+	}
+	e.cached = target
+	e.expires = time.Now().Add(e.ttl)
+	return target, nil
+}
+
+// backendFromEnv resolves the backend target from the environment without
+// caching.
+func backendFromEnv() (backendTarget, error) {
+	target := backendTarget{
+		URL:   os.Getenv("MODEL_SERVER_URL"),
+		Token: os.Getenv("MODEL_SERVER_TOKEN"),
+	}
+	if target.URL == "" {
+		target.URL = defaultBackendURL
+	}
+	if path := os.Getenv("MODEL_SERVER_TOKEN_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return backendTarget{}, fmt.Errorf("reading backend token: %w", err)
+		}
+		target.Token = strings.TrimSpace(string(data))
+	}
+	return target, nil
+}
+
+// resolveBackend returns the target for the next backend call, reading the
+// environment directly when no provider is configured.
+func (s *server) resolveBackend(ctx context.Context) (backendTarget, error) {
+	if s.backend == nil {
+		return backendFromEnv()
+	}
+	return s.backend.backend(ctx)
+}
+
+// timeoutFor returns the timeout for a backend call to model: its config
+// override if it has one, -backend-timeout otherwise.
+func (s *server) timeoutFor(model string) time.Duration {
+	if timeout, ok := s.config.modelTimeout(model); ok {
+		return timeout
+	}
+	return s.backendTimeout
+}
+
+// backendEndpoint joins path onto the backend base URL. The base may carry a
+// path prefix and a query, and its host may be a name, an IPv4 address or a
+// bracketed IPv6 literal, each with an optional port. A base without a
+// scheme, such as "[::1]:8080", is taken as http.
+func backendEndpoint(base, path string) (string, error) {
+	if !strings.Contains(base, "://") {
+		base = "http://" + base
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		// Drop url.Error's copy of the URL, which may hold a password.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", err
+	}
+	// An unbracketed IPv6 literal can't be told apart from a port.
+	if strings.Count(u.Host, ":") > 1 && !strings.HasPrefix(u.Host, "[") {
+		return "", fmt.Errorf("host %q: IPv6 addresses must be in brackets, e.g. http://[::1]:8080", u.Host)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported scheme %q (want http or https)", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", errors.New("missing host")
+	}
+	return u.JoinPath(path).String(), nil
+}
+
+// parseBackendMethod validates a -backend-method value and returns it in
+// upper case. Only methods that carry a request body are accepted, since the
+// input is sent as a JSON body.
+func parseBackendMethod(method string) (string, error) {
+	switch m := strings.ToUpper(method); m {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return m, nil
+	default:
+		return "", fmt.Errorf("backend method %q is not one of %s, %s, %s", method, http.MethodPost, http.MethodPut, http.MethodPatch)
+	}
+}
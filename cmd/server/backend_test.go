@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+// rotatingBackend hands out a new token on every call.
+type rotatingBackend struct {
+	url    string
+	mu     sync.Mutex
+	tokens []string
+	next   int
+}
+
+func (r *rotatingBackend) backend(ctx context.Context) (backendTarget, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token := r.tokens[r.next%len(r.tokens)]
+	r.next++
+	return backendTarget{URL: r.url, Token: token}, nil
+}
+
+func TestPredict_UsesRotatedToken(t *testing.T) {
+	// Arrange
+	var seen []string
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+	})
+	s.backend = &rotatingBackend{url: os.Getenv("MODEL_SERVER_URL"), tokens: []string{"old", "new"}}
+
+	// Act
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}
+	for range 2 {
+		if _, err := s.Predict(context.Background(), req); err != nil {
+			t.Fatalf("Predict returned error: %v", err)
+		}
+	}
+
+	// Assert
+	want := []string{"Bearer old", "Bearer new"}
+	if len(seen) != len(want) || seen[0] != want[0] || seen[1] != want[1] {
+		t.Errorf("Expected Authorization headers %q, got %q", want, seen)
+	}
+}
+
+func TestEnvBackend_CachesTokenFile(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MODEL_SERVER_URL", "")
+	t.Setenv("MODEL_SERVER_TOKEN_FILE", path)
+	e := newEnvBackend(20 * time.Millisecond)
+
+	target, err := e.backend(context.Background())
+	if err != nil {
+		t.Fatalf("backend returned error: %v", err)
+	}
+	if target.URL != defaultBackendURL || target.Token != "first" {
+		t.Errorf("Expected default URL and token %q, got %+v", "first", target)
+	}
+
+	// Act: rotate the token on disk.
+	if err := os.WriteFile(path, []byte("second\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert: the cached token is served until the TTL expires.
+	if target, _ := e.backend(context.Background()); target.Token != "first" {
+		t.Errorf("Expected cached token before TTL, got %q", target.Token)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if target, _ := e.backend(context.Background()); target.Token != "second" {
+		t.Errorf("Expected rotated token after TTL, got %q", target.Token)
+	}
+}
+
+func TestEnvBackend_MissingTokenFile(t *testing.T) {
+	t.Setenv("MODEL_SERVER_TOKEN_FILE", filepath.Join(t.TempDir(), "missing"))
+	if _, err := newEnvBackend(time.Minute).backend(context.Background()); err == nil {
+		t.Error("Expected error for missing token file")
+	}
+}
+
+func TestBackendEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		want    string
+		wantErr bool
+	}{
+		{"hostname with port", "http://model-server:8080", "http://model-server:8080/predict", false},
+		{"trailing slash", "http://model-server:8080/", "http://model-server:8080/predict", false},
+		{"ipv4", "http://10.0.0.5:8080", "http://10.0.0.5:8080/predict", false},
+		{"ipv6 literal", "http://[::1]:8080", "http://[::1]:8080/predict", false},
+		{"ipv6 zone", "http://[fe80::1%25eth0]:8080", "http://[fe80::1%25eth0]:8080/predict", false},
+		{"path prefix", "https://gateway.example.com/models/v1/", "https://gateway.example.com/models/v1/predict", false},
+		{"query kept", "http://model-server/api?key=abc", "http://model-server/api/predict?key=abc", false},
+		{"no scheme", "[::1]:8080", "http://[::1]:8080/predict", false},
+		{"unbracketed ipv6", "http://::1:8080", "", true},
+		{"bad scheme", "ftp://model-server", "", true},
+		{"no host", "http:///predict", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := backendEndpoint(tt.base, "predict")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("backendEndpoint failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPredict_BackendURLForms(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  string
+		addr     string
+		url      func(addr string) string
+		wantPath string
+	}{
+		{"ipv6 literal", "tcp6", "[::1]:0", func(addr string) string { return "http://" + addr }, "/predict"},
+		{"hostname with port", "tcp4", "127.0.0.1:0", func(addr string) string {
+			_, port, _ := net.SplitHostPort(addr)
+			return "http://localhost:" + port
+		}, "/predict"},
+		{"path prefix", "tcp4", "127.0.0.1:0", func(addr string) string { return "http://" + addr + "/models/v1/" }, "/models/v1/predict"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			lis, err := net.Listen(tt.network, tt.addr)
+			if err != nil {
+				t.Skipf("cannot listen on %s: %v", tt.addr, err)
+			}
+			var gotPath string
+			backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				staticBackend(`{"status":"ok","output":[1]}`)(w, r)
+			}))
+			backend.Listener.Close()
+			backend.Listener = lis
+			backend.Start()
+			defer backend.Close()
+			t.Setenv("MODEL_SERVER_URL", tt.url(lis.Addr().String()))
+			s := &server{httpClient: backend.Client()}
+
+			// Act
+			_, err = s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict failed: %v", err)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("Expected the backend to get %s, got %s", tt.wantPath, gotPath)
+			}
+		})
+	}
+}
+
+func TestPredict_BackendMethod(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		want   string
+	}{
+		{"default", "", http.MethodPost},
+		{"put", http.MethodPut, http.MethodPut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var gotMethod, gotBody string
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotMethod, gotBody = r.Method, string(body)
+				staticBackend(`{"output":[1],"status":"ok"}`)(w, r)
+			})
+			s.backendMethod = tt.method
+
+			// Act
+			_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict returned error: %v", err)
+			}
+			if gotMethod != tt.want {
+				t.Errorf("Expected a %s to the backend, got %s", tt.want, gotMethod)
+			}
+			if !strings.Contains(gotBody, `"input":[1]`) {
+				t.Errorf("Expected the input in the request body, got %q", gotBody)
+			}
+		})
+	}
+}
+
+func TestParseBackendMethod(t *testing.T) {
+	tests := []struct {
+		method  string
+		want    string
+		wantErr bool
+	}{
+		{"POST", http.MethodPost, false},
+		{"put", http.MethodPut, false},
+		{"Patch", http.MethodPatch, false},
+		{"GET", "", true},
+		{"DELETE", "", true},
+		{"", "", true},
+		{"POST /predict", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			got, err := parseBackendMethod(tt.method)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Expected %q (error %v), got %q (%v)", tt.want, tt.wantErr, got, err)
+			}
+		})
+	}
+}
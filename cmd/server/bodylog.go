@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"unicode/utf8"
+)
+
+type bodyLoggingKey struct{}
+
+// shouldLogBodies decides whether a request gets its bodies logged: always
+// in verbose mode, otherwise for a logSampleRate fraction of requests.
+func (s *server) shouldLogBodies() bool {
+	if s.verbose.Load() {
+		return true
+	}
+	return s.logSampleRate > 0 && rand.Float64() < s.logSampleRate
+}
+
+// withBodyLogging records the body logging decision for a request so every
+// call site in the request path agrees on it.
+func withBodyLogging(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, bodyLoggingKey{}, enabled)
+}
+
+// bodyLoggingEnabled reports whether request and response bodies should be
+// logged for the request in ctx.
+func bodyLoggingEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(bodyLoggingKey{}).(bool)
+	return enabled
+}
+
+// bodyForLog returns body as it should appear in the logs: whole when it
+// fits in logBodyMaxBytes, otherwise cut at that size, back to a UTF-8
+// character boundary, and marked with the full size.
+func (s *server) bodyForLog(body []byte) string {
+	if s.logBodyMaxBytes == 0 || len(body) <= s.logBodyMaxBytes {
+		return string(body)
+	}
+	n := s.logBodyMaxBytes
+	for n > 0 && !utf8.RuneStart(body[n]) {
+		n--
+	}
+	return fmt.Sprintf("%s... (truncated, %d bytes total)", body[:n], len(body))
+}
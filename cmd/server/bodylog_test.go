@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+)
+
+func TestShouldLogBodies_SampleRate(t *testing.T) {
+	s := &server{logSampleRate: 0.1}
+
+	const calls = 10000
+	logged := 0
+	for range calls {
+		if s.shouldLogBodies() {
+			logged++
+		}
+	}
+
+	if frac := float64(logged) / calls; frac < 0.08 || frac > 0.12 {
+		t.Errorf("Expected about 10%% of requests to be sampled, got %.3f", frac)
+	}
+}
+
+func TestShouldLogBodies_VerboseAndDisabled(t *testing.T) {
+	verbose := &server{}
+	verbose.verbose.Store(true)
+	quiet := &server{}
+	for range 100 {
+		if !verbose.shouldLogBodies() {
+			t.Fatal("Expected verbose mode to log every request")
+		}
+		if quiet.shouldLogBodies() {
+			t.Fatal("Expected no body logging with verbose off and no sampling")
+		}
+	}
+}
+
+func TestPredict_BodyLogging(t *testing.T) {
+	for _, verbose := range []bool{true, false} {
+		s := newTestServer(t, staticBackend(`{"model_name":"m","output":[42],"status":"ok"}`))
+		s.verbose.Store(verbose)
+
+		var buf bytes.Buffer
+		interceptor := loggingInterceptor(&buf, 0)
+		handler := func(ctx context.Context, req any) (any, error) {
+			return s.Predict(ctx, req.(*pb.PredictRequest))
+		}
+		if _, err := interceptor(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[7]`)}, &grpc.UnaryServerInfo{}, handler); err != nil {
+			t.Fatalf("Predict returned error: %v", err)
+		}
+
+		logs := buf.String()
+		for _, dump := range []string{"Request body:", "API Response Body:", "Parsed input array:", "Output: [42]"} {
+			if strings.Contains(logs, dump) != verbose {
+				t.Errorf("verbose=%v: expected %q logged=%v, logs:\n%s", verbose, dump, verbose, logs)
+			}
+		}
+	}
+}
+
+func TestBodyForLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxBytes int
+		body     string
+		want     string
+	}{
+		{"below the limit", 8, `[1,2,3]`, `[1,2,3]`},
+		{"at the limit", 7, `[1,2,3]`, `[1,2,3]`},
+		{"above the limit", 4, `[1,2,3]`, `[1,2... (truncated, 7 bytes total)`},
+		{"unlimited", 0, `[1,2,3]`, `[1,2,3]`},
+		{"cut inside a character", 3, `["é"]`, `["... (truncated, 6 bytes total)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &server{logBodyMaxBytes: tt.maxBytes}
+
+			got := s.bodyForLog([]byte(tt.body))
+
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPredict_TruncatesLoggedBodies(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":[42,43,44],"status":"ok"}`))
+	s.verbose.Store(true)
+	s.logBodyMaxBytes = 20
+	var buf bytes.Buffer
+	interceptor := loggingInterceptor(&buf, 0)
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.Predict(ctx, req.(*pb.PredictRequest))
+	}
+
+	// Act
+	_, err := interceptor(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[7]`)}, &grpc.UnaryServerInfo{}, handler)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	logs := buf.String()
+	for _, want := range []string{
+		`Request body: {"model_name":"m","i... (truncated, 30 bytes total)`,
+		`API Response Body: {"model_name":"m","o... (truncated, `,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("Expected the logs to contain %q, logs:\n%s", want, logs)
+		}
+	}
+}
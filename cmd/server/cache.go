@@ -0,0 +1,253 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds each Redis operation so an unavailable cache adds
+// little latency before the request falls through to the backend.
+const redisTimeout = 100 * time.Millisecond
+
+var cacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "inference_cache_requests_total",
+		Help: "Prediction cache lookups by result (hit, miss, error)",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(cacheRequests)
+}
+
+// Cache stores encoded backend responses. Implementations must be safe for
+// concurrent use. Get reports a miss with ok == false and a nil error.
+type Cache interface {
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// newCache returns the Cache selected by -cache-backend, or nil when caching
+// is disabled.
+func newCache(backend string, size int, redisAddr string) (Cache, error) {
+	switch backend {
+	case "":
+		return nil, nil
+	case "memory":
+		if size <= 0 {
+			return nil, fmt.Errorf("-cache-size must be positive for the memory cache")
+		}
+		return newMemoryCache(size), nil
+	case "redis":
+		return newRedisCache(redisAddr), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q (want memory or redis)", backend)
+	}
+}
+
+// memoryCache is an LRU cache holding at most size entries.
+type memoryCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+func newMemoryCache(size int) *memoryCache {
+	return &memoryCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = &memoryEntry{key: key, value: value, expires: expires}
+		c.order.MoveToFront(elem)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, value: value, expires: expires})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// redisCache shares cached responses between server replicas.
+type redisCache struct {
+	client *redis.Client
+}
+
+func newRedisCache(addr string) *redisCache {
+	return &redisCache{client: redis.NewClient(&redis.Options{
+		Addr:         addr,
+		DialTimeout:  redisTimeout,
+		ReadTimeout:  redisTimeout,
+		WriteTimeout: redisTimeout,
+	})}
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// predictionCache caches backend responses by model, pinned version and
+// input. Cache failures are logged and treated as misses so an unavailable
+// cache never fails a request.
+type predictionCache struct {
+	store Cache
+	ttl   time.Duration
+}
+
+// newPredictionCache returns a cache over store, or nil when store is nil.
+func newPredictionCache(store Cache, ttl time.Duration) *predictionCache {
+	if store == nil {
+		return nil
+	}
+	return &predictionCache{store: store, ttl: ttl}
+}
+
+// get returns the cached response, or nil on a miss. A nil predictionCache
+// always misses.
+func (c *predictionCache) get(ctx context.Context, key string) *APIResponse {
+	if c == nil {
+		return nil
+	}
+	value, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		cacheRequests.WithLabelValues("error").Inc()
+		loggerFromContext(ctx).Printf("Cache lookup failed, calling backend: %v", err)
+		return nil
+	}
+	if !ok {
+		cacheRequests.WithLabelValues("miss").Inc()
+		return nil
+	}
+	var resp APIResponse
+	if err := json.Unmarshal(value, &resp); err != nil {
+		cacheRequests.WithLabelValues("error").Inc()
+		loggerFromContext(ctx).Printf("Ignoring undecodable cache entry: %v", err)
+		return nil
+	}
+	cacheRequests.WithLabelValues("hit").Inc()
+	return &resp
+}
+
+// set stores resp. A nil predictionCache does nothing.
+func (c *predictionCache) set(ctx context.Context, key string, resp *APIResponse) {
+	if c == nil {
+		return
+	}
+	value, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	if err := c.store.Set(ctx, key, value, c.ttl); err != nil {
+		loggerFromContext(ctx).Printf("Cache store failed: %v", err)
+	}
+}
+
+// requestHash hashes the model, pinned version, model parameters, input
+// shape and input values into the hex SHA-256 digest returned as
+// PredictResponse.RequestHash. Parameters are compared as sent, so the same
+// object with its keys in a different order gets a different hash. The scheme
+// is documented in the README for clients that compute it themselves;
+// changing it breaks them, which is why an unset shape adds nothing.
+func requestHash(model, version string, input []float64, shape []int64, params []byte) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(version))
+	h.Write([]byte{0})
+	h.Write(params)
+	h.Write([]byte{0})
+	var buf [8]byte
+	if len(shape) > 0 {
+		// The dimension count keeps shapes apart from each other, and the
+		// trailing zero byte from inputs without a shape.
+		binary.LittleEndian.PutUint64(buf[:], uint64(len(shape)))
+		h.Write(buf[:])
+		for _, dim := range shape {
+			binary.LittleEndian.PutUint64(buf[:], uint64(dim))
+			h.Write(buf[:])
+		}
+		h.Write([]byte{0})
+	}
+	for _, v := range input {
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+		h.Write(buf[:])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheKey returns the cache and coalescing key for a request hash, the
+// Accept header sent to the backend and the backend variant. A binary
+// backend response holds float32 values, so it gets its own key rather than
+// being served to, or shared with, requests that expect the full JSON
+// precision. Canary responses are kept apart from stable ones likewise.
+func cacheKey(hash, accept, variant string) string {
+	prefix := "inference:"
+	if variant == variantCanary {
+		prefix += "canary:"
+	}
+	if accept == acceptBinary {
+		prefix += "binary:"
+	}
+	return prefix + hash
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+// fakeCache is a map-backed Cache that can be switched to fail every call,
+// standing in for an unavailable Redis.
+type fakeCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]time.Duration
+	down   bool
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (f *fakeCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return nil, false, errors.New("connection refused")
+	}
+	v, ok := f.values[key]
+	return v, ok, nil
+}
+
+func (f *fakeCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return errors.New("connection refused")
+	}
+	f.values[key] = value
+	f.ttls[key] = ttl
+	return nil
+}
+
+func TestPredict_ServesFromCache(t *testing.T) {
+	// Arrange
+	backendCalls := 0
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		backendCalls++
+		w.Write([]byte(`{"model_name":"m","output":[0.25],"status":"ok"}`))
+	})
+	store := newFakeCache()
+	s.cache = newPredictionCache(store, time.Minute)
+
+	// Act
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 2]`)}
+	var responses []*pb.PredictResponse
+	for range 2 {
+		resp, err := s.Predict(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Predict returned error: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	other := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 3]`)}
+	if _, err := s.Predict(context.Background(), other); err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+
+	// Assert
+	if backendCalls != 2 {
+		t.Errorf("Expected the repeated input to be served from cache (2 backend calls), got %d", backendCalls)
+	}
+	if got := string(responses[1].GetOutputData()); got != "[0.25]" {
+		t.Errorf("Expected cached output [0.25], got %s", got)
+	}
+	for key, ttl := range store.ttls {
+		if ttl != time.Minute {
+			t.Errorf("Expected entry %s stored with the configured TTL, got %v", key, ttl)
+		}
+	}
+}
+
+func TestPredict_RequestHash(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"output":[0.25],"status":"ok"}`))
+	store := newFakeCache()
+	s.cache = newPredictionCache(store, time.Minute)
+	params := []byte(`{"temperature":0.7}`)
+	// The documented scheme, computed independently of requestHash.
+	h := sha256.New()
+	h.Write([]byte("m\x00\x00"))
+	h.Write(params)
+	h.Write([]byte{0})
+	for _, v := range []float64{1, 2.5} {
+		h.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(v)))
+	}
+	want := hex.EncodeToString(h.Sum(nil))
+
+	// Act
+	var hashes []string
+	for _, input := range []string{`[1, 2.5]`, `[1.0,2.50]`} {
+		resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(input), Params: params})
+		if err != nil {
+			t.Fatalf("Predict returned error: %v", err)
+		}
+		hashes = append(hashes, resp.GetRequestHash())
+	}
+
+	// Assert
+	for i, got := range hashes {
+		if got != want {
+			t.Errorf("response %d: expected request hash %s, got %s", i, want, got)
+		}
+	}
+	if _, ok := store.values[cacheKey(want, contentTypeJSON, variantStable)]; !ok {
+		t.Errorf("Expected the response cached under the request hash, got keys %v", store.values)
+	}
+}
+
+func TestPredict_CacheUnavailableFallsThrough(t *testing.T) {
+	backendCalls := 0
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		backendCalls++
+		w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+	})
+	store := newFakeCache()
+	store.down = true
+	s.cache = newPredictionCache(store, time.Minute)
+
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}
+	for range 2 {
+		if _, err := s.Predict(context.Background(), req); err != nil {
+			t.Fatalf("Expected an unavailable cache not to fail requests, got %v", err)
+		}
+	}
+	if backendCalls != 2 {
+		t.Errorf("Expected every request to reach the backend, got %d calls", backendCalls)
+	}
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := newMemoryCache(2)
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+	c.Get(ctx, "a") // a is now more recent than b
+	c.Set(ctx, "c", []byte("3"), 0)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("Expected least recently used entry b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := c.Get(ctx, key); !ok {
+			t.Errorf("Expected %s to still be cached", key)
+		}
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	c := newMemoryCache(10)
+	c.Set(ctx, "k", []byte("v"), 10*time.Millisecond)
+	if _, ok, _ := c.Get(ctx, "k"); !ok {
+		t.Fatal("Expected entry before its TTL")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok, _ := c.Get(ctx, "k"); ok {
+		t.Error("Expected entry to expire after its TTL")
+	}
+}
+
+func TestNewCache(t *testing.T) {
+	tests := []struct {
+		backend string
+		size    int
+		wantErr bool
+		wantNil bool
+	}{
+		{"", 10, false, true},
+		{"memory", 10, false, false},
+		{"memory", 0, true, true},
+		{"redis", 0, false, false},
+		{"memcached", 10, true, true},
+	}
+	for _, tt := range tests {
+		c, err := newCache(tt.backend, tt.size, "localhost:6379")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("newCache(%q): expected error %v, got %v", tt.backend, tt.wantErr, err)
+		}
+		if (c == nil) != tt.wantNil {
+			t.Errorf("newCache(%q): expected nil cache %v, got %v", tt.backend, tt.wantNil, c)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Backend variants a request can be routed to.
+const (
+	variantStable = "stable"
+	variantCanary = "canary"
+)
+
+var (
+	backendRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_backend_requests_total",
+			Help: "Backend calls by model, variant (stable or canary) and gRPC status code, after retries",
+		},
+		[]string{"model", "variant", "code"},
+	)
+	backendDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "inference_backend_duration_seconds",
+			Help:    "Backend call latency by model and variant, including retries (seconds)",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"model", "variant"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(backendRequests, backendDuration)
+}
+
+// canaryRouter decides per request whether a model with a canary config is
+// served by its canary or its stable backend.
+type canaryRouter struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// newCanaryRouter returns a router drawing from an RNG seeded with seed, so
+// a run's routing decisions can be reproduced. A zero seed picks a random
+// one.
+func newCanaryRouter(seed uint64) *canaryRouter {
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return &canaryRouter{rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// route returns the variant for a request to model and, for the canary, the
+// URL to send it to. Models without a canary, and all models when the router
+// is nil, are always stable.
+func (r *canaryRouter) route(cfg *Config, model string) (variant, url string) {
+	canary := cfg.Models[model].Canary
+	if r == nil || canary == nil || canary.Percent <= 0 {
+		return variantStable, ""
+	}
+	r.mu.Lock()
+	draw := r.rng.Float64() * 100
+	r.mu.Unlock()
+	if draw < canary.Percent {
+		return variantCanary, canary.URL
+	}
+	return variantStable, ""
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCanaryRouter_Split(t *testing.T) {
+	// Arrange
+	r := newCanaryRouter(42)
+	cfg := &Config{Models: map[string]ModelConfig{
+		"m": {Canary: &CanaryConfig{URL: "http://canary", Percent: 20}},
+	}}
+
+	// Act
+	const requests = 10000
+	canary := 0
+	for range requests {
+		variant, url := r.route(cfg, "m")
+		if variant == variantCanary {
+			canary++
+			if url != "http://canary" {
+				t.Fatalf("Expected canary URL, got %q", url)
+			}
+		}
+	}
+
+	// Assert
+	if frac := float64(canary) / requests; frac < 0.18 || frac > 0.22 {
+		t.Errorf("Expected about 20%% of requests on the canary, got %.3f", frac)
+	}
+}
+
+func TestCanaryRouter_SeedIsReproducible(t *testing.T) {
+	cfg := &Config{Models: map[string]ModelConfig{
+		"m": {Canary: &CanaryConfig{URL: "http://canary", Percent: 50}},
+	}}
+	a, b := newCanaryRouter(7), newCanaryRouter(7)
+	for i := range 100 {
+		va, _ := a.route(cfg, "m")
+		vb, _ := b.route(cfg, "m")
+		if va != vb {
+			t.Fatalf("Request %d: routers with the same seed disagree (%s vs %s)", i, va, vb)
+		}
+	}
+}
+
+func TestCanaryRouter_StableWithoutCanary(t *testing.T) {
+	cfg := &Config{Models: map[string]ModelConfig{
+		"off": {Canary: &CanaryConfig{URL: "http://canary", Percent: 0}},
+	}}
+	routers := map[string]*canaryRouter{"router": newCanaryRouter(1), "nil router": nil}
+	for name, r := range routers {
+		for _, model := range []string{"off", "unconfigured"} {
+			if variant, url := r.route(cfg, model); variant != variantStable || url != "" {
+				t.Errorf("%s, model %s: expected stable, got %s %q", name, model, variant, url)
+			}
+		}
+	}
+}
+
+func TestPredict_CanaryRouting(t *testing.T) {
+	// Arrange: every request goes to the canary.
+	var canaryCalls int
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		canaryCalls++
+		w.Write([]byte(`{"model_name":"m","output":[2],"status":"ok"}`))
+	}))
+	t.Cleanup(canary.Close)
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no calls to the stable backend")
+	})
+	s.canary = newCanaryRouter(1)
+	s.config.Models = map[string]ModelConfig{
+		"canaried": {Canary: &CanaryConfig{URL: canary.URL, Percent: 100}},
+	}
+	calls := backendRequests.WithLabelValues("canaried", variantCanary, "OK")
+	before := testutil.ToFloat64(calls)
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "canaried", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if string(resp.GetOutputData()) != "[2]" || canaryCalls != 1 {
+		t.Errorf("Expected one call answered by the canary, got %d calls and output %s", canaryCalls, resp.GetOutputData())
+	}
+	if got := testutil.ToFloat64(calls) - before; got != 1 {
+		t.Errorf("Expected the call counted under the canary variant, got %v", got)
+	}
+}
+
+func TestPredict_CanaryCachedSeparately(t *testing.T) {
+	// Arrange: half of the requests go to the canary, and responses are
+	// cached.
+	var stableCalls, canaryCalls int
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		canaryCalls++
+		w.Write([]byte(`{"model_name":"m","output":[2],"status":"ok"}`))
+	}))
+	t.Cleanup(canary.Close)
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		stableCalls++
+		w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+	})
+	s.canary = newCanaryRouter(1)
+	s.config.Models = map[string]ModelConfig{
+		"canaried": {Canary: &CanaryConfig{URL: canary.URL, Percent: 50}},
+	}
+	s.cache = newPredictionCache(newFakeCache(), time.Minute)
+
+	// Act
+	outputs := map[string]int{}
+	for range 20 {
+		resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "canaried", InputData: []byte(`[1]`)})
+		if err != nil {
+			t.Fatalf("Predict returned error: %v", err)
+		}
+		outputs[string(resp.GetOutputData())]++
+	}
+
+	// Assert: each variant is called once and then served from its own
+	// cache entry.
+	if stableCalls != 1 || canaryCalls != 1 {
+		t.Errorf("Expected one call to each variant, got %d stable and %d canary", stableCalls, canaryCalls)
+	}
+	if outputs["[1]"] == 0 || outputs["[2]"] == 0 {
+		t.Errorf("Expected responses from both variants, got %v", outputs)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Faults that -chaos can inject.
+const (
+	faultUnavailable = "unavailable"
+	faultLatency     = "latency"
+	faultTruncate    = "truncate"
+)
+
+var chaosFaults = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "inference_chaos_faults_total",
+		Help: "Synthetic failures injected into Predict by -chaos, by fault",
+	},
+	[]string{"fault"},
+)
+
+func init() {
+	prometheus.MustRegister(chaosFaults)
+}
+
+// chaosInjector injects synthetic failures into a share of Predict calls so
+// client retries and timeouts can be exercised in staging:
+//
+//   - unavailable fails the call with UNAVAILABLE before the backend call.
+//   - latency delays the call by a fixed amount before the backend call.
+//   - truncate drops the second half of the output, or of each output head.
+//
+// A nil chaosInjector injects nothing.
+type chaosInjector struct {
+	rate    float64
+	faults  []string
+	latency time.Duration
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// newChaosInjector parses a comma-separated list of faults and returns an
+// injector that picks one of them at random for a rate share of calls. It
+// returns nil when faults is empty. A zero seed picks a random one.
+func newChaosInjector(faults string, rate float64, latency time.Duration, seed uint64) (*chaosInjector, error) {
+	if faults == "" {
+		return nil, nil
+	}
+	c := &chaosInjector{rate: rate, latency: latency}
+	for fault := range strings.SplitSeq(faults, ",") {
+		fault = strings.TrimSpace(fault)
+		switch fault {
+		case faultUnavailable, faultLatency, faultTruncate:
+		default:
+			return nil, fmt.Errorf("unknown -chaos fault %q (want %s, %s or %s)", fault, faultUnavailable, faultLatency, faultTruncate)
+		}
+		if !slices.Contains(c.faults, fault) {
+			c.faults = append(c.faults, fault)
+		}
+	}
+	if rate <= 0 || rate > 1 {
+		return nil, fmt.Errorf("-chaos-rate must be above 0 and at most 1, got %v", rate)
+	}
+	if slices.Contains(c.faults, faultLatency) && latency <= 0 {
+		return nil, fmt.Errorf("-chaos-latency must be positive, got %v", latency)
+	}
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	c.rng = rand.New(rand.NewPCG(seed, seed))
+	return c, nil
+}
+
+// pick returns the fault to inject into the next call, or "" for none.
+func (c *chaosInjector) pick() string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rng.Float64() >= c.rate {
+		return ""
+	}
+	return c.faults[c.rng.IntN(len(c.faults))]
+}
+
+// inject picks a fault for a Predict call and applies the ones that act
+// before the backend call: it returns an error for unavailable and sleeps
+// for latency. The picked fault is returned so the caller can apply
+// truncate to the output.
+func (c *chaosInjector) inject(ctx context.Context) (string, error) {
+	fault := c.pick()
+	if fault == "" {
+		return "", nil
+	}
+	chaosFaults.WithLabelValues(fault).Inc()
+	loggerFromContext(ctx).Printf("CHAOS: injecting %s fault", fault)
+	switch fault {
+	case faultUnavailable:
+		return fault, status.Errorf(codes.Unavailable, "injected failure (-chaos)")
+	case faultLatency:
+		if err := sleepContext(ctx, c.latency); err != nil {
+			return fault, status.FromContextError(err).Err()
+		}
+	}
+	return fault, nil
+}
+
+// truncateOutputs returns a copy of heads with the second half of each head
+// dropped, leaving heads itself untouched since it may be a cached response.
+func truncateOutputs(heads map[string][]float64) map[string][]float64 {
+	out := make(map[string][]float64, len(heads))
+	for name, head := range heads {
+		out[name] = head[:len(head)/2]
+	}
+	return out
+}
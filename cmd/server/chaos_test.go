@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChaosInjector_FailureRate(t *testing.T) {
+	// Arrange
+	c, err := newChaosInjector("unavailable,truncate", 0.2, 0, 42)
+	if err != nil {
+		t.Fatalf("newChaosInjector failed: %v", err)
+	}
+	const calls = 20000
+
+	// Act
+	counts := make(map[string]int)
+	for range calls {
+		counts[c.pick()]++
+	}
+
+	// Assert
+	if rate := float64(calls-counts[""]) / calls; math.Abs(rate-0.2) > 0.01 {
+		t.Errorf("Expected a fault rate of about 0.2, got %v", rate)
+	}
+	for _, fault := range []string{faultUnavailable, faultTruncate} {
+		if share := float64(counts[fault]) / calls; math.Abs(share-0.1) > 0.01 {
+			t.Errorf("Expected about 10%% %s faults, got %v", fault, share)
+		}
+	}
+}
+
+func TestChaosInjector_DisabledByDefault(t *testing.T) {
+	c, err := newChaosInjector("", 0.1, time.Second, 0)
+	if err != nil || c != nil {
+		t.Fatalf("Expected no injector without -chaos, got %v, %v", c, err)
+	}
+	if fault, err := c.inject(context.Background()); fault != "" || err != nil {
+		t.Errorf("Expected a nil injector to inject nothing, got %q, %v", fault, err)
+	}
+}
+
+func TestNewChaosInjector_RejectsInvalidSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		faults  string
+		rate    float64
+		latency time.Duration
+	}{
+		{"unknown fault", "explode", 0.1, time.Second},
+		{"zero rate", "unavailable", 0, time.Second},
+		{"rate above one", "unavailable", 1.5, time.Second},
+		{"latency without delay", "latency", 0.1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newChaosInjector(tt.faults, tt.rate, tt.latency, 1); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestPredict_ChaosFaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		fault      string
+		wantCode   codes.Code
+		wantOutput string
+		wantCalled bool
+		minElapsed time.Duration
+	}{
+		{"unavailable", faultUnavailable, codes.Unavailable, "", false, 0},
+		{"latency", faultLatency, codes.OK, "[1,2,3,4]", true, 50 * time.Millisecond},
+		{"truncate", faultTruncate, codes.OK, "[1,2]", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			called := false
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				staticBackend(`{"status":"ok","output":[1,2,3,4]}`)(w, r)
+			})
+			var err error
+			s.chaos, err = newChaosInjector(tt.fault, 1, 50*time.Millisecond, 1)
+			if err != nil {
+				t.Fatalf("newChaosInjector failed: %v", err)
+			}
+
+			// Act
+			start := time.Now()
+			resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if string(resp.GetOutputData()) != tt.wantOutput {
+				t.Errorf("Expected output %q, got %q", tt.wantOutput, resp.GetOutputData())
+			}
+			if called != tt.wantCalled {
+				t.Errorf("Expected backend called=%v, got %v", tt.wantCalled, called)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("Expected at least %v of added latency, took %v", tt.minElapsed, elapsed)
+			}
+		})
+	}
+}
+
+func TestPredict_ChaosTruncatesEachHead(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(multiTensorBody))
+	var err error
+	s.chaos, err = newChaosInjector(faultTruncate, 1, 0, 1)
+	if err != nil {
+		t.Fatalf("newChaosInjector failed: %v", err)
+	}
+	s.cache = newPredictionCache(newFakeCache(), time.Minute)
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}
+
+	// Act
+	resp, err := s.Predict(context.Background(), req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if got := string(resp.GetOutputs()["logits"]); got != "[1,2,3]" {
+		t.Errorf("Expected the logits head to be truncated to [1,2,3], got %s", got)
+	}
+	if got := string(resp.GetOutputs()["count"]); got != "[]" {
+		t.Errorf("Expected the count head to be truncated to [], got %s", got)
+	}
+
+	// The cached response keeps the full heads.
+	s.chaos = nil
+	resp, err = s.Predict(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if got := string(resp.GetOutputs()["logits"]); got != "[1,2,3,4,5,6]" {
+		t.Errorf("Expected the cached logits to be untouched, got %s", got)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc/status"
+)
+
+var coalescedRequests = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "inference_coalesced_requests_total",
+		Help: "Requests whose backend call was shared with identical requests in flight at the same time",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(coalescedRequests)
+}
+
+// requestCoalescer shares one backend call between identical requests that
+// are in flight at the same time. Callers must treat the shared
+// *APIResponse as read-only.
+type requestCoalescer struct {
+	group singleflight.Group
+
+	// tasks tracks shared calls so shutdown waits for those whose callers
+	// have all gone away.
+	tasks *asyncTasks
+}
+
+// do runs call once per key among concurrent callers. The shared call is
+// detached from any single caller's cancellation, so one client giving up
+// doesn't fail the others; each caller still stops waiting when its own ctx
+// is done. A nil coalescer runs call directly.
+func (c *requestCoalescer) do(ctx context.Context, key string, call func(ctx context.Context) (*APIResponse, error)) (*APIResponse, error) {
+	if c == nil {
+		return call(ctx)
+	}
+	results := c.group.DoChan(key, func() (any, error) {
+		defer c.tasks.track()()
+		return call(context.WithoutCancel(ctx))
+	})
+	select {
+	case res := <-results:
+		if res.Shared {
+			coalescedRequests.Inc()
+		}
+		resp, _ := res.Val.(*APIResponse)
+		return resp, res.Err
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
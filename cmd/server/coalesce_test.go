@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// blockingBackend counts calls and holds each one until release is closed.
+func blockingBackend(calls *atomic.Int32, release <-chan struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Write([]byte(`{"model_name":"m","output":[0.5],"status":"ok"}`))
+	}
+}
+
+// waitForCalls polls until calls reaches want.
+func waitForCalls(t *testing.T, calls *atomic.Int32, want int32) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d backend calls, got %d", want, calls.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPredict_CoalescesIdenticalRequests(t *testing.T) {
+	// Arrange
+	var calls atomic.Int32
+	release := make(chan struct{})
+	s := newTestServer(t, blockingBackend(&calls, release))
+	s.coalescer = &requestCoalescer{}
+
+	// Act: fire identical requests while the first is still in flight.
+	const n = 10
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for range n {
+		wg.Go(func() {
+			resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 2]`)})
+			if err == nil && string(resp.GetOutputData()) != "[0.5]" {
+				t.Errorf("Expected shared output [0.5], got %s", resp.GetOutputData())
+			}
+			errs <- err
+		})
+	}
+	waitForCalls(t, &calls, 1)
+	time.Sleep(50 * time.Millisecond) // let the rest join the in-flight call
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	// Assert
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Predict returned error: %v", err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected 1 backend call for %d identical requests, got %d", n, got)
+	}
+}
+
+func TestPredict_DoesNotCoalesceDifferentInputs(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	s := newTestServer(t, blockingBackend(&calls, release))
+	s.coalescer = &requestCoalescer{}
+
+	var wg sync.WaitGroup
+	for _, input := range []string{`[1]`, `[2]`} {
+		wg.Go(func() {
+			if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(input)}); err != nil {
+				t.Errorf("Predict returned error: %v", err)
+			}
+		})
+	}
+	waitForCalls(t, &calls, 2)
+	close(release)
+	wg.Wait()
+}
+
+func TestRequestCoalescer_CallerCancellationDoesNotFailOthers(t *testing.T) {
+	// Arrange
+	c := &requestCoalescer{}
+	release := make(chan struct{})
+	call := func(ctx context.Context) (*APIResponse, error) {
+		<-release
+		return &APIResponse{Output: []float64{1}}, ctx.Err()
+	}
+
+	impatient, cancel := context.WithCancel(context.Background())
+	impatientErr := make(chan error, 1)
+	go func() {
+		_, err := c.do(impatient, "k", call)
+		impatientErr <- err
+	}()
+	patientResp := make(chan *APIResponse, 1)
+	go func() {
+		resp, err := c.do(context.Background(), "k", call)
+		if err != nil {
+			t.Errorf("Expected the patient caller to succeed, got %v", err)
+		}
+		patientResp <- resp
+	}()
+
+	// Act: the caller that started the shared call gives up.
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	// Assert
+	if err := <-impatientErr; status.Code(err) != codes.Canceled {
+		t.Errorf("Expected Canceled for the impatient caller, got %v", err)
+	}
+	close(release)
+	if resp := <-patientResp; resp == nil || resp.Output[0] != 1 {
+		t.Errorf("Expected the shared response, got %v", resp)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/protobuf/proto"
+)
+
+// compressionInterceptor compresses unary responses of at least minBytes
+// with the named compressor when the client accepts it, and sends smaller
+// ones uncompressed, where compression costs more CPU than it saves. This
+// also overrides gRPC's default of answering in the request's compression.
+func compressionInterceptor(name string, minBytes int) (grpc.UnaryServerInterceptor, error) {
+	if name != gzip.Name {
+		return nil, fmt.Errorf("unsupported -grpc-compression %q (want %s)", name, gzip.Name)
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		compressor := encoding.Identity
+		if m, ok := resp.(proto.Message); ok && proto.Size(m) >= minBytes {
+			if accepted, _ := grpc.ClientSupportedCompressors(ctx); slices.Contains(accepted, name) {
+				compressor = name
+			}
+		}
+		grpc.SetSendCompressor(ctx, compressor)
+		return resp, nil
+	}, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/stats"
+)
+
+// responseEncoding records the grpc-encoding of each response a client
+// receives.
+type responseEncoding struct {
+	mu        sync.Mutex
+	encodings []string
+}
+
+func (r *responseEncoding) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+func (r *responseEncoding) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if h, ok := s.(*stats.InHeader); ok && h.Client {
+		r.mu.Lock()
+		r.encodings = append(r.encodings, h.Compression)
+		r.mu.Unlock()
+	}
+}
+func (r *responseEncoding) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+func (r *responseEncoding) HandleConn(context.Context, stats.ConnStats) {}
+
+func TestCompressionInterceptor_Threshold(t *testing.T) {
+	large := "[" + strings.Repeat("0.123456789,", 999) + "0.123456789]"
+	tests := []struct {
+		name         string
+		output       string
+		gzipRequest  bool
+		wantEncoding string
+	}{
+		{"small output", "[1,2,3]", false, "identity"},
+		{"small output of a compressed request", "[1,2,3]", true, "identity"},
+		{"large output", large, false, gzip.Name},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, staticBackend(fmt.Sprintf(`{"status":"ok","output":%s}`, tt.output)))
+			compress, err := compressionInterceptor(gzip.Name, 1024)
+			if err != nil {
+				t.Fatalf("compressionInterceptor failed: %v", err)
+			}
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("failed to listen: %v", err)
+			}
+			grpcServer := grpc.NewServer(grpc.UnaryInterceptor(compress))
+			pb.RegisterInferenceServer(grpcServer, s)
+			go grpcServer.Serve(lis)
+			defer grpcServer.Stop()
+			seen := &responseEncoding{}
+			conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithStatsHandler(seen))
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer conn.Close()
+			var opts []grpc.CallOption
+			if tt.gzipRequest {
+				opts = append(opts, grpc.UseCompressor(gzip.Name))
+			}
+
+			// Act
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			resp, err := pb.NewInferenceClient(conn).Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}, opts...)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict failed: %v", err)
+			}
+			if len(resp.GetOutputData()) != len(tt.output) {
+				t.Errorf("Expected %d output bytes, got %d", len(tt.output), len(resp.GetOutputData()))
+			}
+			if len(seen.encodings) != 1 || seen.encodings[0] != tt.wantEncoding {
+				t.Errorf("Expected response encoding %q, got %q", tt.wantEncoding, seen.encodings)
+			}
+		})
+	}
+}
+
+func TestCompressionInterceptor_RejectsUnknownCompressor(t *testing.T) {
+	if _, err := compressionInterceptor("brotli", 1024); err == nil {
+		t.Error("Expected an error for an unsupported compressor")
+	}
+}
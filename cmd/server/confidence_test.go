@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPredict_Confidence(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		want     []float64
+		wantCode codes.Code
+	}{
+		{"array", `{"status":"ok","output":[0.2,0.8],"confidence":[0.9,0.7]}`, []float64{0.9, 0.7}, codes.OK},
+		{"scalar", `{"status":"ok","output":[0.2,0.8],"confidence":0.95}`, []float64{0.95}, codes.OK},
+		{"absent", `{"status":"ok","output":[0.2,0.8]}`, nil, codes.OK},
+		{"null", `{"status":"ok","output":[0.2,0.8],"confidence":null}`, nil, codes.OK},
+		{"invalid", `{"status":"ok","output":[0.2,0.8],"confidence":"high"}`, nil, codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, staticBackend(tt.body))
+
+			// Act
+			resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if !slices.Equal(resp.GetConfidence(), tt.want) {
+				t.Errorf("Expected confidence %v, got %v", tt.want, resp.GetConfidence())
+			}
+		})
+	}
+}
+
+func TestAPIResponse_ConfidenceSurvivesCaching(t *testing.T) {
+	// Cached responses are stored as JSON and must keep their confidence.
+	original := &APIResponse{Output: []float64{1}, Status: "ok", Confidence: []float64{0.5}}
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded APIResponse
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !slices.Equal(decoded.Confidence, original.Confidence) {
+		t.Errorf("Expected confidence %v after a round trip, got %v", original.Confidence, decoded.Confidence)
+	}
+}
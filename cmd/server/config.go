@@ -0,0 +1,445 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"go.yaml.in/yaml/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Feature types accepted in ModelConfig.FeatureTypes.
+const (
+	featureFloat = "float"
+	featureInt   = "int"
+	featureBool  = "bool"
+)
+
+// Canonical status values that backend statuses can be normalized to.
+const (
+	statusSuccess = "SUCCESS"
+	statusPartial = "PARTIAL"
+	statusError   = "ERROR"
+)
+
+// Config holds the settings loaded from the optional YAML file passed via
+// -config. Scalar settings are flags; the file carries the structured ones.
+type Config struct {
+	// StatusMap translates backend status strings into the canonical set
+	// (SUCCESS, PARTIAL, ERROR). Unmapped statuses pass through unchanged.
+	StatusMap map[string]string `yaml:"status_map"`
+
+	// SuccessCheck, when set, inspects the body of 2xx backend responses to
+	// tell successes from logical errors. Without it every 2xx is a success.
+	SuccessCheck *SuccessCheck `yaml:"success_check"`
+
+	// Aliases maps client-facing model names to the backend model names
+	// they resolve to, e.g. "sentiment" to "sentiment-v3", so clients keep
+	// their code when a model is upgraded. Per-model settings apply to the
+	// resolved name.
+	Aliases map[string]string `yaml:"aliases"`
+
+	// Models holds per-model overrides keyed by model name.
+	Models map[string]ModelConfig `yaml:"models"`
+}
+
+// ModelConfig holds the settings that can be overridden for a single model.
+type ModelConfig struct {
+	// MaxConcurrency caps concurrent backend calls for the model. Zero falls
+	// back to -max-concurrency.
+	MaxConcurrency int `yaml:"max_concurrency"`
+
+	// OutputLength, when positive, is the number of values the backend must
+	// return for the model. Responses of any other length are rejected.
+	OutputLength int `yaml:"output_length"`
+
+	// Timeout overrides -backend-timeout for the model, e.g. "30s".
+	Timeout time.Duration `yaml:"timeout"`
+
+	// LatencyObjective, when positive, is the model's latency SLO, e.g.
+	// "200ms". Each Predict call is counted as meeting it or not in
+	// inference_slo_requests_total.
+	LatencyObjective time.Duration `yaml:"latency_objective"`
+
+	// FeatureNames, when set, names the positional input values. The backend
+	// then receives the input as a JSON object keyed by these names instead
+	// of an array, and inputs of any other length are rejected.
+	FeatureNames []string `yaml:"feature_names"`
+
+	// FeatureTypes, when set, gives the type of each positional input value:
+	// "float" (any number), "int" (a whole number) or "bool" (0 or 1).
+	// Inputs with a value of the wrong type or of a different length are
+	// rejected before reaching the backend.
+	FeatureTypes []string `yaml:"feature_types"`
+
+	// ForwardFields lists extra top-level fields of the backend response
+	// that are passed to the client verbatim in PredictResponse.Metadata.
+	ForwardFields []string `yaml:"forward_fields"`
+
+	// Canary sends a share of the model's traffic to a second backend.
+	Canary *CanaryConfig `yaml:"canary"`
+
+	// Preprocess is a pipeline of transforms applied in order to the input
+	// after it has been validated and before it is sent to the backend.
+	Preprocess []PreprocessStep `yaml:"preprocess"`
+
+	// InputSchema and OutputSchema are paths to JSON Schema documents the
+	// model's input and backend output must match. Relative paths are
+	// resolved against the config file's directory.
+	InputSchema  string `yaml:"input_schema"`
+	OutputSchema string `yaml:"output_schema"`
+
+	// inputSchema and outputSchema are compiled by loadConfig.
+	inputSchema  *jsonschema.Schema
+	outputSchema *jsonschema.Schema
+}
+
+// CanaryConfig routes Percent of a model's requests to the backend at URL
+// and the rest to the stable backend.
+type CanaryConfig struct {
+	URL     string  `yaml:"url"`
+	Percent float64 `yaml:"percent"`
+}
+
+// loadConfig reads and validates the YAML config at path. Environment
+// variables are expanded first (see expandEnv). Unknown keys are rejected so
+// typos don't silently disable a setting.
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("reading config: %w", err)
+	}
+	expanded, err := expandEnv(string(data))
+	if err != nil {
+		return cfg, fmt.Errorf("expanding config %s: %w", path, err)
+	}
+	if err := yaml.UnmarshalStrict([]byte(expanded), &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := cfg.compileSchemas(filepath.Dir(path)); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// expandEnv replaces $VAR and ${VAR} with the variable's value, and
+// ${VAR:-default} with default when VAR is unset or empty. "$$" is a literal
+// "$". Referencing an unset variable without a default is an error.
+func expandEnv(s string) (string, error) {
+	var missing []string
+	expanded := os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		name, def, hasDefault := strings.Cut(name, ":-")
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+		if hasDefault {
+			return def
+		}
+		if _, ok := os.LookupEnv(name); !ok && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+		return ""
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("unset environment variables without a default: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+func (c *Config) validate() error {
+	for from, to := range c.StatusMap {
+		switch to {
+		case statusSuccess, statusPartial, statusError:
+		default:
+			return fmt.Errorf("status_map[%q]: %q is not one of %s, %s, %s", from, to, statusSuccess, statusPartial, statusError)
+		}
+	}
+	if c.SuccessCheck != nil {
+		if err := c.SuccessCheck.validate(); err != nil {
+			return err
+		}
+	}
+	for alias, model := range c.Aliases {
+		if model == "" {
+			return fmt.Errorf("aliases[%q] must name a model", alias)
+		}
+		if _, ok := c.Aliases[model]; ok {
+			return fmt.Errorf("aliases[%q]: %q is itself an alias", alias, model)
+		}
+	}
+	for name, m := range c.Models {
+		if m.MaxConcurrency < 0 {
+			return fmt.Errorf("models[%q].max_concurrency must not be negative", name)
+		}
+		if m.Timeout < 0 {
+			return fmt.Errorf("models[%q].timeout must be positive", name)
+		}
+		if m.LatencyObjective < 0 {
+			return fmt.Errorf("models[%q].latency_objective must be positive", name)
+		}
+		if m.OutputLength < 0 {
+			return fmt.Errorf("models[%q].output_length must not be negative", name)
+		}
+		if c := m.Canary; c != nil {
+			if c.URL == "" {
+				return fmt.Errorf("models[%q].canary.url is required", name)
+			}
+			if _, err := backendEndpoint(c.URL, "predict"); err != nil {
+				return fmt.Errorf("models[%q].canary.url: %w", name, err)
+			}
+			if c.Percent < 0 || c.Percent > 100 {
+				return fmt.Errorf("models[%q].canary.percent must be between 0 and 100", name)
+			}
+		}
+		for i, typ := range m.FeatureTypes {
+			switch typ {
+			case featureFloat, featureInt, featureBool:
+			default:
+				return fmt.Errorf("models[%q].feature_types[%d]: %q is not one of %s, %s, %s", name, i, typ, featureFloat, featureInt, featureBool)
+			}
+		}
+		if len(m.FeatureNames) > 0 && len(m.FeatureTypes) > 0 && len(m.FeatureNames) != len(m.FeatureTypes) {
+			return fmt.Errorf("models[%q] has %d feature_names but %d feature_types", name, len(m.FeatureNames), len(m.FeatureTypes))
+		}
+		seen := make(map[string]bool, len(m.FeatureNames))
+		for _, feature := range m.FeatureNames {
+			if feature == "" {
+				return fmt.Errorf("models[%q].feature_names must not contain empty names", name)
+			}
+			if seen[feature] {
+				return fmt.Errorf("models[%q].feature_names: duplicate name %q", name, feature)
+			}
+			seen[feature] = true
+		}
+		for _, field := range m.ForwardFields {
+			if field == "" {
+				return fmt.Errorf("models[%q].forward_fields must not contain empty names", name)
+			}
+		}
+		for i, step := range m.Preprocess {
+			if err := step.validate(); err != nil {
+				return fmt.Errorf("models[%q].preprocess[%d]: %w", name, i, err)
+			}
+			if step.changesLength() && len(m.FeatureNames) > 0 {
+				return fmt.Errorf("models[%q].preprocess[%d]: %s changes the number of values, which feature_names fixes", name, i, step.Op)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveAlias returns the model an alias resolves to, or model itself when
+// it is not an alias.
+func (c *Config) resolveAlias(model string) string {
+	if resolved, ok := c.Aliases[model]; ok {
+		return resolved
+	}
+	return model
+}
+
+// concurrencyLimits returns the per-model concurrency overrides.
+func (c *Config) concurrencyLimits() map[string]int {
+	limits := make(map[string]int)
+	for name, m := range c.Models {
+		if m.MaxConcurrency > 0 {
+			limits[name] = m.MaxConcurrency
+		}
+	}
+	return limits
+}
+
+// modelTimeout returns the backend timeout configured for model, or ok ==
+// false when it has no override.
+func (c *Config) modelTimeout(model string) (timeout time.Duration, ok bool) {
+	timeout = c.Models[model].Timeout
+	return timeout, timeout > 0
+}
+
+// checkOutputLength returns an Internal error when model declares an output
+// length and output doesn't match it.
+func (c *Config) checkOutputLength(model string, output []float64) error {
+	want := c.Models[model].OutputLength
+	if want > 0 && len(output) != want {
+		return status.Errorf(codes.Internal, "backend returned %d output values for model %s, expected %d", len(output), model, want)
+	}
+	return nil
+}
+
+// checkFeatureCount returns an InvalidArgument error when model has feature
+// names and the input doesn't have exactly one value per name.
+func (c *Config) checkFeatureCount(model string, n int) error {
+	names := c.Models[model].FeatureNames
+	if len(names) > 0 && n != len(names) {
+		return status.Errorf(codes.InvalidArgument, "model %s expects %d input values (%s), got %d", model, len(names), strings.Join(names, ", "), n)
+	}
+	return nil
+}
+
+// checkInputShape returns an InvalidArgument error unless shape describes n
+// input values: every dimension is positive and their product is n. Models
+// whose inputs are named features or whose preprocessing changes the input
+// length can't take a shape, since the backend would not receive the values
+// it describes.
+func (c *Config) checkInputShape(model string, shape []int64, n int) error {
+	m := c.Models[model]
+	if len(m.FeatureNames) > 0 {
+		return status.Errorf(codes.InvalidArgument, "model %s takes named features, not a shaped input", model)
+	}
+	if slices.ContainsFunc(m.Preprocess, func(p PreprocessStep) bool { return p.changesLength() }) {
+		return status.Errorf(codes.InvalidArgument, "model %s preprocessing changes the input length, so it can't take a shaped input", model)
+	}
+	mismatch := func() error {
+		return status.Errorf(codes.InvalidArgument, "input_shape %v does not match the %d input values", shape, n)
+	}
+	size := int64(1)
+	for i, dim := range shape {
+		if dim <= 0 {
+			return status.Errorf(codes.InvalidArgument, "input_shape[%d] must be positive, got %d", i, dim)
+		}
+		// Checked before multiplying so a huge dimension can't overflow.
+		if dim > int64(n)/size {
+			return mismatch()
+		}
+		size *= dim
+	}
+	if size != int64(n) {
+		return mismatch()
+	}
+	return nil
+}
+
+// checkFeatureTypes returns an InvalidArgument error naming the first input
+// value that doesn't match the type configured for its position. Models
+// without feature types accept any input.
+func (c *Config) checkFeatureTypes(model string, input []float64) error {
+	m := c.Models[model]
+	if len(m.FeatureTypes) == 0 {
+		return nil
+	}
+	if len(input) != len(m.FeatureTypes) {
+		return status.Errorf(codes.InvalidArgument, "model %s expects %d input values, got %d", model, len(m.FeatureTypes), len(input))
+	}
+	for i, typ := range m.FeatureTypes {
+		v := input[i]
+		var ok bool
+		switch typ {
+		case featureInt:
+			ok = v == math.Trunc(v) && !math.IsInf(v, 0)
+		case featureBool:
+			ok = v == 0 || v == 1
+		default:
+			ok = true
+		}
+		if ok {
+			continue
+		}
+		return featureTypeError(model, i, m.FeatureNames, typ, v)
+	}
+	return nil
+}
+
+// featureTypeError reports input[index] having the wrong type as
+// InvalidArgument with a google.rpc.BadRequest field violation, so clients
+// can locate the feature without parsing the message.
+func featureTypeError(model string, index int, names []string, typ string, value float64) error {
+	field := fmt.Sprintf("input[%d]", index)
+	feature := field
+	if index < len(names) {
+		feature += fmt.Sprintf(" (%s)", names[index])
+	}
+	st := status.Newf(codes.InvalidArgument, "%s must be %s for model %s, got %v", feature, typeDescription(typ), model, value)
+	withDetails, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       field,
+			Description: fmt.Sprintf("expected %s, got %v", typ, value),
+		}},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// typeDescription describes a feature type for error messages.
+func typeDescription(typ string) string {
+	switch typ {
+	case featureInt:
+		return "an int (a whole number)"
+	case featureBool:
+		return "a bool (0 or 1)"
+	default:
+		return "a float"
+	}
+}
+
+// namedInput zips input with the feature names configured for model. It
+// returns nil when the model has no feature names.
+func (c *Config) namedInput(model string, input []float64) (map[string]float64, error) {
+	names := c.Models[model].FeatureNames
+	if len(names) == 0 {
+		return nil, nil
+	}
+	if err := c.checkFeatureCount(model, len(input)); err != nil {
+		return nil, err
+	}
+	named := make(map[string]float64, len(names))
+	for i, name := range names {
+		named[name] = input[i]
+	}
+	return named, nil
+}
+
+// normalizeStatus maps a backend status through StatusMap, returning it
+// unchanged when there is no mapping.
+func (c *Config) normalizeStatus(backendStatus string) string {
+	if mapped, ok := c.StatusMap[backendStatus]; ok {
+		return mapped
+	}
+	return backendStatus
+}
+
+// isSuccess reports whether a backend status means success: the backend's
+// own "ok", or any status mapped to SUCCESS.
+func (c *Config) isSuccess(backendStatus string) bool {
+	return backendStatus == "ok" || c.normalizeStatus(backendStatus) == statusSuccess
+}
+
+// forwardedFields picks the fields listed in the model's forward_fields out
+// of a JSON backend response body. It returns nil when none are configured
+// or the backend sent none of them.
+func (c *Config) forwardedFields(model string, body []byte) (map[string]json.RawMessage, error) {
+	fields := c.Models[model].ForwardFields
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(body, &all); err != nil {
+		return nil, err
+	}
+	var picked map[string]json.RawMessage
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			if picked == nil {
+				picked = make(map[string]json.RawMessage, len(fields))
+			}
+			picked[field] = value
+		}
+	}
+	return picked, nil
+}
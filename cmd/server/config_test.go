@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// writeConfig writes contents to a temporary YAML file and returns its path.
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig_StatusMap(t *testing.T) {
+	path := writeConfig(t, `
+status_map:
+  success: SUCCESS
+  ok: SUCCESS
+  "200": SUCCESS
+  failed: ERROR
+`)
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig returned error: %v", err)
+	}
+	if got := cfg.StatusMap["200"]; got != statusSuccess {
+		t.Errorf("Expected \"200\" to map to SUCCESS, got %q", got)
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"non-canonical status", "status_map:\n  ok: GREAT\n"},
+		{"unknown key", "status_mapping:\n  ok: SUCCESS\n"},
+		{"malformed yaml", "status_map: [\n"},
+		{"negative output length", "models:\n  m:\n    output_length: -1\n"},
+		{"negative timeout", "models:\n  m:\n    timeout: -5s\n"},
+		{"negative latency objective", "models:\n  m:\n    latency_objective: -1s\n"},
+		{"malformed timeout", "models:\n  m:\n    timeout: soon\n"},
+		{"empty alias target", "aliases:\n  sentiment: \"\"\n"},
+		{"chained alias", "aliases:\n  sentiment: latest\n  latest: sentiment-v3\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadConfig(writeConfig(t, tt.contents)); err == nil {
+				t.Errorf("Expected error loading %q", tt.contents)
+			}
+		})
+	}
+
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected error for missing config file")
+	}
+}
+
+func TestNormalizeStatus(t *testing.T) {
+	cfg := Config{StatusMap: map[string]string{"success": statusSuccess, "200": statusSuccess}}
+
+	tests := []struct {
+		in, want string
+	}{
+		{"success", statusSuccess},
+		{"200", statusSuccess},
+		{"degraded", "degraded"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := cfg.normalizeStatus(tt.in); got != tt.want {
+			t.Errorf("normalizeStatus(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	var empty Config
+	if got := empty.normalizeStatus("ok"); got != "ok" {
+		t.Errorf("Expected empty config to pass status through, got %q", got)
+	}
+}
+
+func TestPredict_NormalizesStatus(t *testing.T) {
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":[1],"status":"success"}`))
+	s.config.StatusMap = map[string]string{"success": statusSuccess}
+
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if resp.Status != statusSuccess {
+		t.Errorf("Expected normalized status SUCCESS, got %q", resp.Status)
+	}
+}
+
+func TestLoadConfig_ModelConcurrency(t *testing.T) {
+	cfg, err := loadConfig(writeConfig(t, `
+models:
+  heavy:
+    max_concurrency: 2
+  light: {}
+`))
+	if err != nil {
+		t.Fatalf("loadConfig returned error: %v", err)
+	}
+	limits := cfg.concurrencyLimits()
+	if len(limits) != 1 || limits["heavy"] != 2 {
+		t.Errorf("Expected only heavy to be limited to 2, got %v", limits)
+	}
+
+	if _, err := loadConfig(writeConfig(t, "models:\n  heavy:\n    max_concurrency: -1\n")); err == nil {
+		t.Error("Expected error for negative max_concurrency")
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("SUCCESS_STATUS", "SUCCESS")
+	t.Setenv("EMPTY_VAR", "")
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"set", "ok: ${SUCCESS_STATUS}", "ok: SUCCESS", ""},
+		{"set without braces", "ok: $SUCCESS_STATUS", "ok: SUCCESS", ""},
+		{"defaulted", "limit: ${HEAVY_LIMIT_UNSET:-4}", "limit: 4", ""},
+		{"set overrides default", "ok: ${SUCCESS_STATUS:-ERROR}", "ok: SUCCESS", ""},
+		{"empty uses default", "ok: ${EMPTY_VAR:-ERROR}", "ok: ERROR", ""},
+		{"empty without default", "ok: '${EMPTY_VAR}'", "ok: ''", ""},
+		{"escaped dollar", "price: $$5", "price: $5", ""},
+		{"unset", "a: ${UNSET_ONE}\nb: ${UNSET_TWO}\nc: ${UNSET_ONE}", "", "UNSET_ONE, UNSET_TWO"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandEnv(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error naming %s, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandEnv returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_ExpandsEnv(t *testing.T) {
+	t.Setenv("BACKEND_OK", "SUCCESS")
+
+	cfg, err := loadConfig(writeConfig(t, `
+status_map:
+  ok: ${BACKEND_OK}
+models:
+  heavy:
+    max_concurrency: ${HEAVY_CONCURRENCY:-3}
+`))
+	if err != nil {
+		t.Fatalf("loadConfig returned error: %v", err)
+	}
+	if got := cfg.StatusMap["ok"]; got != statusSuccess {
+		t.Errorf("Expected status_map[ok] expanded to SUCCESS, got %q", got)
+	}
+	if got := cfg.Models["heavy"].MaxConcurrency; got != 3 {
+		t.Errorf("Expected defaulted max_concurrency 3, got %d", got)
+	}
+
+	if _, err := loadConfig(writeConfig(t, "status_map:\n  ok: ${MISSING_STATUS}\n")); err == nil || !strings.Contains(err.Error(), "MISSING_STATUS") {
+		t.Errorf("Expected error naming the unset variable, got %v", err)
+	}
+}
+
+func TestPredict_OutputLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    string
+		wantCode codes.Code
+	}{
+		{"matching length", "classifier", codes.OK},
+		{"mismatched length", "regressor", codes.Internal},
+		{"unconfigured model", "other", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange: the backend always returns three values.
+			s := newTestServer(t, staticBackend(`{"model_name":"m","output":[1,2,3],"status":"ok"}`))
+			s.config.Models = map[string]ModelConfig{
+				"classifier": {OutputLength: 3},
+				"regressor":  {OutputLength: 1},
+			}
+
+			// Act
+			_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: tt.model, InputData: []byte(`[1]`)})
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantCode == codes.Internal && !strings.Contains(err.Error(), "backend returned 3 output values for model regressor, expected 1") {
+				t.Errorf("Expected expected vs actual lengths in error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_ModelTimeout(t *testing.T) {
+	cfg, err := loadConfig(writeConfig(t, `
+models:
+  slow:
+    timeout: 30s
+`))
+	if err != nil {
+		t.Fatalf("loadConfig returned error: %v", err)
+	}
+	if got, ok := cfg.modelTimeout("slow"); !ok || got != 30*time.Second {
+		t.Errorf("Expected 30s timeout for slow, got %v (ok=%v)", got, ok)
+	}
+	if _, ok := cfg.modelTimeout("other"); ok {
+		t.Error("Expected no timeout override for an unconfigured model")
+	}
+}
+
+func TestTimeoutFor(t *testing.T) {
+	s := &server{
+		backendTimeout: 10 * time.Second,
+		config: Config{Models: map[string]ModelConfig{
+			"fast": {Timeout: 100 * time.Millisecond},
+			"slow": {Timeout: time.Minute},
+			"tiny": {MaxConcurrency: 1},
+		}},
+	}
+	tests := []struct {
+		model string
+		want  time.Duration
+	}{
+		{"fast", 100 * time.Millisecond},
+		{"slow", time.Minute},
+		{"tiny", 10 * time.Second},
+		{"unknown", 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := s.timeoutFor(tt.model); got != tt.want {
+			t.Errorf("timeoutFor(%q) = %v, expected %v", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestPredict_PerModelTimeout(t *testing.T) {
+	// Arrange: every backend call takes 50ms.
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+	})
+	s.backendTimeout = time.Second
+	s.config.Models = map[string]ModelConfig{"fast": {Timeout: 10 * time.Millisecond}}
+
+	// Act & Assert: the fast model gives up, the default timeout is ample.
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "fast", InputData: []byte(`[1]`)}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected the fast model to time out, got %v", err)
+	}
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "other", InputData: []byte(`[1]`)}); err != nil {
+		t.Errorf("Expected the default timeout to allow the call, got %v", err)
+	}
+}
+
+func TestPredict_FeatureNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    string
+		input    string
+		wantCode codes.Code
+		wantBody string
+	}{
+		{"zips names with input", "housing", `[3, 1200.5, 1]`, codes.OK, `{"model_name":"housing","input":{"bedrooms":3,"has_garage":1,"sqft":1200.5}}`},
+		{"too few values", "housing", `[3, 1200.5]`, codes.InvalidArgument, ""},
+		{"too many values", "housing", `[3, 1200.5, 1, 7]`, codes.InvalidArgument, ""},
+		{"unconfigured model", "other", `[1, 2]`, codes.OK, `{"model_name":"other","input":[1,2]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var body []byte
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+			})
+			s.config.Models = map[string]ModelConfig{
+				"housing": {FeatureNames: []string{"bedrooms", "sqft", "has_garage"}},
+			}
+
+			// Act
+			_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: tt.model, InputData: []byte(tt.input)})
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantCode == codes.InvalidArgument {
+				if body != nil {
+					t.Errorf("Expected no backend call on a length mismatch, got body %s", body)
+				}
+				if !strings.Contains(err.Error(), "model housing expects 3 input values (bedrooms, sqft, has_garage)") {
+					t.Errorf("Expected the feature names in the error, got %v", err)
+				}
+				return
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("Expected backend body %s, got %s", tt.wantBody, body)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_RejectsBadFeatureNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   string
+		wantErr string
+	}{
+		{"empty name", `["a", ""]`, "must not contain empty names"},
+		{"duplicate name", `["a", "b", "a"]`, `duplicate name "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfig(writeConfig(t, "models:\n  m:\n    feature_names: "+tt.names+"\n"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPredict_FeatureTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantCode  codes.Code
+		wantMsg   string
+		wantField string
+	}{
+		{"matching types", `[3, 0.5, 1]`, codes.OK, "", ""},
+		{"whole float for int", `[3.0, 2, 0]`, codes.OK, "", ""},
+		{"fraction for int", `[2.5, 0.5, 1]`, codes.InvalidArgument, "input[0] (rooms) must be an int (a whole number) for model housing, got 2.5", "input[0]"},
+		{"non-binary bool", `[3, 0.5, 2]`, codes.InvalidArgument, "input[2] (garage) must be a bool (0 or 1) for model housing, got 2", "input[2]"},
+		{"wrong length", `[3, 0.5]`, codes.InvalidArgument, "expects 3 input values", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			called := false
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+			})
+			s.config.Models = map[string]ModelConfig{
+				"housing": {
+					FeatureNames: []string{"rooms", "ratio", "garage"},
+					FeatureTypes: []string{featureInt, featureFloat, featureBool},
+				},
+			}
+
+			// Act
+			_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "housing", InputData: []byte(tt.input)})
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK {
+				return
+			}
+			if called {
+				t.Error("Expected the input to be rejected before reaching the backend")
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantMsg, err)
+			}
+			if tt.wantField == "" {
+				return
+			}
+			var field string
+			for _, detail := range status.Convert(err).Details() {
+				if br, ok := detail.(*errdetails.BadRequest); ok && len(br.GetFieldViolations()) == 1 {
+					field = br.GetFieldViolations()[0].GetField()
+				}
+			}
+			if field != tt.wantField {
+				t.Errorf("Expected a BadRequest violation for %s, got %q", tt.wantField, field)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_RejectsBadFeatureTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   string
+		wantErr string
+	}{
+		{"unknown type", "feature_types: [int, string]", `feature_types[1]: "string" is not one of`},
+		{"length mismatch", "feature_names: [a, b]\n    feature_types: [int]", "has 2 feature_names but 1 feature_types"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfig(writeConfig(t, "models:\n  m:\n    "+tt.model+"\n"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// correlationIDHeader is the gRPC metadata key clients can use instead
+	// of the CorrelationId request field.
+	correlationIDHeader = "x-correlation-id"
+	// backendCorrelationIDHeader forwards the correlation ID to the backend.
+	backendCorrelationIDHeader = "X-Correlation-Id"
+	// maxCorrelationIDLen bounds the ID, which is copied into every log line
+	// and backend request for the call.
+	maxCorrelationIDLen = 128
+)
+
+// requestCorrelationID returns the correlation ID supplied with req: its
+// CorrelationId field when set, the x-correlation-id metadata otherwise, or
+// "" when there is none. It returns INVALID_ARGUMENT for an ID that is too
+// long or is not printable ASCII, since the ID ends up in an HTTP header.
+func requestCorrelationID(ctx context.Context, req any) (string, error) {
+	var id string
+	if r, ok := req.(interface{ GetCorrelationId() string }); ok {
+		id = r.GetCorrelationId()
+	}
+	if id == "" {
+		md, _ := metadata.FromIncomingContext(ctx)
+		id = firstValue(md, correlationIDHeader)
+	}
+	if err := checkClientID("correlation ID", id, maxCorrelationIDLen); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// checkClientID returns INVALID_ARGUMENT when an ID the client supplied,
+// described by name, is longer than maxLen or is not printable ASCII.
+func checkClientID(name, id string, maxLen int) error {
+	if len(id) > maxLen {
+		return status.Errorf(codes.InvalidArgument, "%s is %d bytes, the limit is %d", name, len(id), maxLen)
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < ' ' || id[i] > '~' {
+			return status.Errorf(codes.InvalidArgument, "%s must be printable ASCII", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestPredict_CorrelationIDFlowsThrough(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		md    string
+		want  string
+	}{
+		{"request field", "order-42", "", "order-42"},
+		{"metadata", "", "order-43", "order-43"},
+		{"field wins over metadata", "order-44", "order-45", "order-44"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var backendID string
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				backendID = r.Header.Get(backendCorrelationIDHeader)
+				staticBackend(`{"model_name":"sample","output":[1],"status":"ok"}`)(w, r)
+			})
+			var buf bytes.Buffer
+			interceptor := loggingInterceptor(&buf, 0)
+			ctx := context.Background()
+			if tt.md != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(correlationIDHeader, tt.md))
+			}
+			req := &pb.PredictRequest{ModelName: "sample", InputData: []byte(`[1, 2]`), CorrelationId: tt.field}
+			handler := func(ctx context.Context, req any) (any, error) {
+				return s.Predict(ctx, req.(*pb.PredictRequest))
+			}
+
+			// Act
+			resp, err := interceptor(ctx, req, &grpc.UnaryServerInfo{}, handler)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict returned error: %v", err)
+			}
+			if got := resp.(*pb.PredictResponse).GetCorrelationId(); got != tt.want {
+				t.Errorf("Expected response correlation ID %q, got %q", tt.want, got)
+			}
+			if backendID != tt.want {
+				t.Errorf("Expected backend header %q, got %q", tt.want, backendID)
+			}
+			tag := `correlation_id="` + tt.want + `" `
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				if !strings.Contains(line, tag) {
+					t.Errorf("Expected line to be tagged with %s, got %q", tag, line)
+				}
+			}
+		})
+	}
+}
+
+func TestPredict_NoCorrelationID(t *testing.T) {
+	// Arrange
+	var sent bool
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, sent = r.Header[backendCorrelationIDHeader]
+		staticBackend(`{"output":[1],"status":"ok"}`)(w, r)
+	})
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if sent || resp.GetCorrelationId() != "" {
+		t.Errorf("Expected no correlation ID, got header=%v response=%q", sent, resp.GetCorrelationId())
+	}
+}
+
+func TestPredict_InvalidCorrelationID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"too long", strings.Repeat("x", maxCorrelationIDLen+1)},
+		{"newline", "order-1\r\nX-Admin: true"},
+		{"non-ASCII", "commande-é"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Error("backend should not be called")
+			})
+			var buf bytes.Buffer
+			interceptor := loggingInterceptor(&buf, 0)
+			req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), CorrelationId: tt.id}
+			handler := func(ctx context.Context, req any) (any, error) {
+				loggerFromContext(ctx).Printf("hello")
+				return s.Predict(ctx, req.(*pb.PredictRequest))
+			}
+
+			// Act
+			_, err := interceptor(context.Background(), req, &grpc.UnaryServerInfo{}, handler)
+
+			// Assert
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+			if strings.Contains(buf.String(), "correlation_id=") {
+				t.Errorf("Expected the invalid ID to be left out of the logs, got %q", buf.String())
+			}
+		})
+	}
+}
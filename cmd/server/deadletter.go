@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/arhantsg07/ml-inference-system/internal/recording"
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var deadLettersDropped = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "inference_dead_letters_dropped_total",
+		Help: "Failed requests not written to the dead-letter sink because its buffer was full",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(deadLettersDropped)
+}
+
+// deadLetterPostTimeout bounds each POST to -dead-letter-url, so a stuck
+// sink can't stall the writer and make every later dead letter drop.
+const deadLetterPostTimeout = 10 * time.Second
+
+// deadLetterSink keeps requests whose backend call failed after all retries,
+// in the recording format so they can be inspected or replayed with the
+// client's -replay flag. Writes never block the request.
+type deadLetterSink struct {
+	records *recording.Writer
+	out     io.Closer
+}
+
+// newDeadLetterSink returns a sink appending to the file at path or posting
+// to url with POSTs bounded by timeout, or nil when neither is set. The sink
+// has its own HTTP client, since the backend's may have no timeout and may
+// only speak h2c.
+func newDeadLetterSink(path, url string, buffer int, timeout time.Duration) (*deadLetterSink, error) {
+	switch {
+	case path != "" && url != "":
+		return nil, errors.New("set only one of -dead-letter-path and -dead-letter-url")
+	case path != "":
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("opening dead-letter file: %w", err)
+		}
+		return &deadLetterSink{records: recording.NewWriter(f, buffer), out: f}, nil
+	case url != "":
+		sink := &httpLineSink{client: &http.Client{Timeout: timeout}, url: url}
+		return &deadLetterSink{records: recording.NewWriter(sink, buffer), out: sink}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// isDeadLetter reports whether err, returned by the backend call of a
+// request whose context is ctx, is a backend failure worth keeping. Rejected
+// inputs, the server's own queue rejections and calls the client canceled or
+// let run out of time are not.
+func isDeadLetter(ctx context.Context, err error) bool {
+	return isBackendFailure(err) && ctx.Err() == nil
+}
+
+// write queues a failed Predict request. A nil sink does nothing.
+func (d *deadLetterSink) write(req *pb.PredictRequest, err error) {
+	if d == nil {
+		return
+	}
+	rec, recErr := recording.NewRecord(pb.Inference_Predict_FullMethodName, req, nil, err)
+	if recErr != nil {
+		log.Printf("failed to encode dead letter: %v", recErr)
+		return
+	}
+	if !d.records.Write(rec) {
+		deadLettersDropped.Inc()
+	}
+}
+
+// Close flushes pending dead letters and closes the underlying sink.
+func (d *deadLetterSink) Close() error {
+	if d == nil {
+		return nil
+	}
+	err := d.records.Close()
+	if closeErr := d.out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// httpLineSink POSTs complete JSON lines to url as application/x-ndjson.
+// Partial lines are held back until the rest arrives.
+type httpLineSink struct {
+	client  *http.Client
+	url     string
+	pending []byte
+}
+
+func (h *httpLineSink) Write(p []byte) (int, error) {
+	h.pending = append(h.pending, p...)
+	end := bytes.LastIndexByte(h.pending, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+	body := bytes.Clone(h.pending[:end+1])
+	h.pending = h.pending[end+1:]
+
+	resp, err := h.client.Post(h.url, "application/x-ndjson", bytes.NewReader(body))
+	if err != nil {
+		return len(p), fmt.Errorf("posting dead letters: %w", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return len(p), fmt.Errorf("posting dead letters: sink returned %s", resp.Status)
+	}
+	return len(p), nil
+}
+
+func (h *httpLineSink) Close() error {
+	return nil
+}
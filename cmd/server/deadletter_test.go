@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arhantsg07/ml-inference-system/internal/recording"
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+)
+
+// flakyModelBackend fails every call for the model "broken".
+func flakyModelBackend(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if bytes.Contains(body, []byte(`"broken"`)) {
+		http.Error(w, "model crashed", http.StatusInternalServerError)
+		return
+	}
+	w.Write([]byte(`{"model_name":"ok","output":[1],"status":"ok"}`))
+}
+
+func TestPredict_WritesFailuresToDeadLetterFile(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, flakyModelBackend)
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	sink, err := newDeadLetterSink(path, "", 10, time.Second)
+	if err != nil {
+		t.Fatalf("newDeadLetterSink failed: %v", err)
+	}
+	s.deadLetters = sink
+
+	// Act
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "ok", InputData: []byte(`[1]`)}); err != nil {
+		t.Fatalf("Predict for healthy model failed: %v", err)
+	}
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "broken", InputData: []byte(`[2, 3]`)}); err == nil {
+		t.Fatal("Expected Predict for broken model to fail")
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Assert: only the failed request was written.
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := recording.Read(f)
+	if err != nil {
+		t.Fatalf("reading dead letters: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(records))
+	}
+	var req pb.PredictRequest
+	if err := records[0].DecodeRequest(&req); err != nil {
+		t.Fatalf("decoding request: %v", err)
+	}
+	if req.GetModelName() != "broken" || string(req.GetInputData()) != "[2, 3]" {
+		t.Errorf("Expected the broken request, got %v", &req)
+	}
+	if records[0].StatusCode() != codes.Internal || records[0].Message == "" {
+		t.Errorf("Expected the Internal error to be recorded, got %s %q", records[0].Code, records[0].Message)
+	}
+}
+
+func TestPredict_PostsFailuresToDeadLetterURL(t *testing.T) {
+	// Arrange
+	var mu sync.Mutex
+	var posted bytes.Buffer
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("Expected ndjson content type, got %q", ct)
+		}
+		io.Copy(&posted, r.Body)
+	}))
+	defer collector.Close()
+
+	s := newTestServer(t, flakyModelBackend)
+	sink, err := newDeadLetterSink("", collector.URL, 10, time.Second)
+	if err != nil {
+		t.Fatalf("newDeadLetterSink failed: %v", err)
+	}
+	s.deadLetters = sink
+
+	// Act
+	s.Predict(context.Background(), &pb.PredictRequest{ModelName: "ok", InputData: []byte(`[1]`)})
+	s.Predict(context.Background(), &pb.PredictRequest{ModelName: "broken", InputData: []byte(`[1]`)})
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Assert
+	mu.Lock()
+	defer mu.Unlock()
+	records, err := recording.Read(&posted)
+	if err != nil {
+		t.Fatalf("reading posted dead letters: %v", err)
+	}
+	if len(records) != 1 || !bytes.Contains(records[0].Request, []byte(`"broken"`)) {
+		t.Errorf("Expected only the broken request to be posted, got %d records", len(records))
+	}
+}
+
+func TestPredict_HungDeadLetterURLTimesOut(t *testing.T) {
+	// Arrange: a sink that accepts the POST but never answers.
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer collector.Close()
+
+	s := newTestServer(t, flakyModelBackend)
+	sink, err := newDeadLetterSink("", collector.URL, 10, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("newDeadLetterSink failed: %v", err)
+	}
+	s.deadLetters = sink
+	s.Predict(context.Background(), &pb.PredictRequest{ModelName: "broken", InputData: []byte(`[1]`)})
+
+	// Act
+	closed := make(chan error, 1)
+	go func() { closed <- sink.Close() }()
+
+	// Assert
+	select {
+	case err := <-closed:
+		if err == nil {
+			t.Error("Expected Close to report the timed-out POST")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the POST to the hung sink to time out")
+	}
+}
+
+func TestPredict_SkipsLocalFailuresInDeadLetters(t *testing.T) {
+	tests := []struct {
+		name    string
+		arrange func(t *testing.T, s *server) context.Context
+	}{
+		{"queue rejection", func(t *testing.T, s *server) context.Context {
+			// The only slot is taken, so the request times out in the queue.
+			s.limiters = newModelLimiters(1, nil, 1, 10*time.Millisecond)
+			release, err := s.limiters.acquire(context.Background(), "broken")
+			if err != nil {
+				t.Fatalf("acquire failed: %v", err)
+			}
+			t.Cleanup(release)
+			return context.Background()
+		}},
+		{"client deadline", func(t *testing.T, s *server) context.Context {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			t.Cleanup(cancel)
+			return ctx
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body)
+				<-r.Context().Done()
+			})
+			path := filepath.Join(t.TempDir(), "dead.jsonl")
+			sink, err := newDeadLetterSink(path, "", 10, time.Second)
+			if err != nil {
+				t.Fatalf("newDeadLetterSink failed: %v", err)
+			}
+			s.deadLetters = sink
+			ctx := tt.arrange(t, s)
+
+			// Act
+			if _, err := s.Predict(ctx, &pb.PredictRequest{ModelName: "broken", InputData: []byte(`[1]`)}); err == nil {
+				t.Fatal("Expected Predict to fail")
+			}
+			if err := sink.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			// Assert
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(data) != 0 {
+				t.Errorf("Expected no dead letters, got %s", data)
+			}
+		})
+	}
+}
+
+func TestNewDeadLetterSink(t *testing.T) {
+	if sink, err := newDeadLetterSink("", "", 10, time.Second); sink != nil || err != nil {
+		t.Errorf("Expected no sink when unconfigured, got %v, %v", sink, err)
+	}
+	if _, err := newDeadLetterSink("dead.jsonl", "http://sink", 10, time.Second); err == nil {
+		t.Error("Expected an error when both a path and a URL are set")
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+var requestsWithoutDeadline = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "inference_requests_without_deadline_total",
+		Help: "Unary calls that arrived without a client deadline, which usually means a misconfigured client",
+	},
+	[]string{"method"},
+)
+
+func init() {
+	prometheus.MustRegister(requestsWithoutDeadline)
+}
+
+// deadlineInterceptor bounds unary calls that arrive without a deadline by
+// giving them one of def, so no request can run unbounded. Such calls are
+// counted per method. A non-positive def only counts them.
+func deadlineInterceptor(def time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := ctx.Deadline(); ok {
+			return handler(ctx, req)
+		}
+		requestsWithoutDeadline.WithLabelValues(info.FullMethod).Inc()
+		if def > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, def)
+			defer cancel()
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+)
+
+func TestDeadlineInterceptor(t *testing.T) {
+	clientDeadline := time.Now().Add(time.Hour)
+	tests := []struct {
+		name         string
+		ctx          func() (context.Context, context.CancelFunc)
+		def          time.Duration
+		wantDeadline func(got time.Time, ok bool) bool
+		wantCounted  float64
+	}{
+		{
+			name: "client deadline kept",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadline(context.Background(), clientDeadline)
+			},
+			def: time.Second,
+			wantDeadline: func(got time.Time, ok bool) bool {
+				return ok && got.Equal(clientDeadline)
+			},
+			wantCounted: 0,
+		},
+		{
+			name: "default applied",
+			ctx:  func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			def:  time.Second,
+			wantDeadline: func(got time.Time, ok bool) bool {
+				return ok && time.Until(got) > 0 && time.Until(got) <= time.Second
+			},
+			wantCounted: 1,
+		},
+		{
+			name: "no default",
+			ctx:  func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			def:  0,
+			wantDeadline: func(got time.Time, ok bool) bool {
+				return !ok
+			},
+			wantCounted: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			info := &grpc.UnaryServerInfo{FullMethod: pb.Inference_Predict_FullMethodName}
+			counter := requestsWithoutDeadline.WithLabelValues(info.FullMethod)
+			before := testutil.ToFloat64(counter)
+			var got time.Time
+			var ok bool
+			handler := func(ctx context.Context, req any) (any, error) {
+				got, ok = ctx.Deadline()
+				return nil, nil
+			}
+
+			// Act
+			deadlineInterceptor(tt.def)(ctx, &pb.PredictRequest{}, info, handler)
+
+			// Assert
+			if !tt.wantDeadline(got, ok) {
+				t.Errorf("Unexpected handler deadline %v (set=%v)", got, ok)
+			}
+			if counted := testutil.ToFloat64(counter) - before; counted != tt.wantCounted {
+				t.Errorf("Expected %v calls counted without a deadline, got %v", tt.wantCounted, counted)
+			}
+		})
+	}
+}
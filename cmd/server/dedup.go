@@ -0,0 +1,128 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxTrackedRequests bounds the memory used by the duplicate detector; the
+// oldest fingerprints are forgotten first when a burst exceeds it.
+const maxTrackedRequests = 100000
+
+var duplicateRequests = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "inference_duplicate_requests_total",
+		Help: "Predict requests identical to one received within -dedup-window",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(duplicateRequests)
+}
+
+// duplicateDetector remembers the fingerprints of recent Predict requests
+// to catch clients that resend the same request in a tight loop. The window
+// starts at the first request; repeats within it do not extend it. A nil
+// duplicateDetector tracks nothing.
+type duplicateDetector struct {
+	window time.Duration
+	reject bool
+	now    func() time.Time
+
+	mu    sync.Mutex
+	order *list.List // front is the oldest fingerprint
+	seen  map[[sha256.Size]byte]*list.Element
+}
+
+type seenRequest struct {
+	fingerprint [sha256.Size]byte
+	at          time.Time
+}
+
+// newDuplicateDetector returns a detector that counts requests repeated
+// within window and, when reject is set, fails them. It returns nil when
+// window is not positive.
+func newDuplicateDetector(window time.Duration, reject bool) *duplicateDetector {
+	if window <= 0 {
+		return nil
+	}
+	return &duplicateDetector{
+		window: window,
+		reject: reject,
+		now:    time.Now,
+		order:  list.New(),
+		seen:   make(map[[sha256.Size]byte]*list.Element),
+	}
+}
+
+// check records req, sent for the pinned model version, and reports whether
+// it repeats a request seen within the window. It returns ALREADY_EXISTS for
+// a repeat when rejecting is enabled.
+func (d *duplicateDetector) check(req *pb.PredictRequest, version string) error {
+	if d == nil {
+		return nil
+	}
+	fingerprint, err := requestFingerprint(req, version)
+	if err != nil {
+		// A request that cannot be fingerprinted is never a duplicate.
+		return nil
+	}
+
+	now := d.now()
+	d.mu.Lock()
+	d.expire(now)
+	_, duplicate := d.seen[fingerprint]
+	if !duplicate {
+		d.seen[fingerprint] = d.order.PushBack(seenRequest{fingerprint: fingerprint, at: now})
+		if d.order.Len() > maxTrackedRequests {
+			oldest := d.order.Remove(d.order.Front()).(seenRequest)
+			delete(d.seen, oldest.fingerprint)
+		}
+	}
+	d.mu.Unlock()
+
+	if !duplicate {
+		return nil
+	}
+	duplicateRequests.Inc()
+	if d.reject {
+		return status.Errorf(codes.AlreadyExists, "identical request received within the last %v", d.window)
+	}
+	return nil
+}
+
+// expire forgets fingerprints older than the window. d.mu must be held.
+func (d *duplicateDetector) expire(now time.Time) {
+	for e := d.order.Front(); e != nil; e = d.order.Front() {
+		r := e.Value.(seenRequest)
+		if now.Sub(r.at) < d.window {
+			return
+		}
+		d.order.Remove(e)
+		delete(d.seen, r.fingerprint)
+	}
+}
+
+// requestFingerprint hashes every field of req together with the pinned
+// version, so only exact resubmits share a fingerprint.
+func requestFingerprint(req *pb.PredictRequest, version string) ([sha256.Size]byte, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	h := sha256.New()
+	h.Write([]byte(version))
+	h.Write([]byte{0})
+	h.Write(b)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum, nil
+}
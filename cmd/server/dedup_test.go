@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPredict_Duplicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		reject    bool
+		wantCode  codes.Code
+		wantCalls int32
+	}{
+		{"counting only", false, codes.OK, 2},
+		{"rejecting", true, codes.AlreadyExists, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var calls atomic.Int32
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				staticBackend(`{"status":"ok","output":[1]}`)(w, r)
+			})
+			s.duplicates = newDuplicateDetector(time.Minute, tt.reject)
+			req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1,2]`)}
+			before := testutil.ToFloat64(duplicateRequests)
+
+			// Act
+			if _, err := s.Predict(context.Background(), req); err != nil {
+				t.Fatalf("first Predict failed: %v", err)
+			}
+			_, err := s.Predict(context.Background(), req)
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("Expected %v for the resubmit, got %v", tt.wantCode, err)
+			}
+			if got := testutil.ToFloat64(duplicateRequests) - before; got != 1 {
+				t.Errorf("Expected 1 duplicate counted, got %v", got)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Expected %d backend calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestDuplicateDetector_Window(t *testing.T) {
+	// Arrange
+	d := newDuplicateDetector(time.Second, true)
+	now := time.Unix(1000, 0)
+	d.now = func() time.Time { return now }
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}
+
+	// Act & Assert
+	if err := d.check(req, ""); err != nil {
+		t.Fatalf("Expected the first request to pass, got %v", err)
+	}
+	now = now.Add(500 * time.Millisecond)
+	if err := d.check(req, ""); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected a resubmit within the window to be rejected, got %v", err)
+	}
+	if err := d.check(&pb.PredictRequest{ModelName: "m", InputData: []byte(`[2]`)}, ""); err != nil {
+		t.Errorf("Expected a different input to pass, got %v", err)
+	}
+	if err := d.check(req, "v2"); err != nil {
+		t.Errorf("Expected a different pinned version to pass, got %v", err)
+	}
+	// Repeats do not extend the window, which started with the first request.
+	now = now.Add(500 * time.Millisecond)
+	if err := d.check(req, ""); err != nil {
+		t.Errorf("Expected a resubmit after the window to pass, got %v", err)
+	}
+}
+
+func TestDuplicateDetector_Disabled(t *testing.T) {
+	d := newDuplicateDetector(0, true)
+	if d != nil {
+		t.Fatalf("Expected no detector for a zero window, got %v", d)
+	}
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}
+	for range 2 {
+		if err := d.check(req, ""); err != nil {
+			t.Errorf("Expected a nil detector to pass everything, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/protobuf/proto"
+)
+
+// applyDefaults returns req with the default input and model filled in when
+// allowDefaultInput is set and they are missing. req itself is never
+// modified.
+func (s *server) applyDefaults(req *pb.PredictRequest) *pb.PredictRequest {
+	if !s.allowDefaultInput {
+		return req
+	}
+	emptyInput := isEmptyInput(req.GetInputData())
+	emptyModel := req.GetModelName() == "" && s.defaultModel != ""
+	if !emptyInput && !emptyModel {
+		return req
+	}
+
+	req = proto.Clone(req).(*pb.PredictRequest)
+	if emptyInput {
+		req.InputData = s.defaultInput
+	}
+	if emptyModel {
+		req.ModelName = s.defaultModel
+	}
+	return req
+}
+
+// isEmptyInput reports whether data is missing or an empty JSON array.
+func isEmptyInput(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) == 0 || bytes.Equal(bytes.Join(bytes.Fields(data), nil), []byte("[]"))
+}
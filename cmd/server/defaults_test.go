@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPredict_DefaultInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		allow     bool
+		req       *pb.PredictRequest
+		wantCode  codes.Code
+		wantModel string
+		wantInput []float64
+	}{
+		{"rejected when disabled", false, &pb.PredictRequest{ModelName: "m"}, codes.InvalidArgument, "", nil},
+		{"empty array rejected when disabled", false, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[]`)}, codes.InvalidArgument, "", nil},
+		{"missing input uses default", true, &pb.PredictRequest{ModelName: "m"}, codes.OK, "m", []float64{0.5, 1.5}},
+		{"empty array uses default", true, &pb.PredictRequest{ModelName: "m", InputData: []byte(` [ ] `)}, codes.OK, "m", []float64{0.5, 1.5}},
+		{"fully empty request uses default model", true, &pb.PredictRequest{}, codes.OK, "demo", []float64{0.5, 1.5}},
+		{"explicit input kept", true, &pb.PredictRequest{InputData: []byte(`[9]`)}, codes.OK, "demo", []float64{9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange: the backend echoes what it received.
+			var got InputData
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&got)
+				w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+			})
+			if tt.allow {
+				s.allowDefaultInput = true
+				s.defaultInput = []byte(`[0.5, 1.5]`)
+				s.defaultModel = "demo"
+			}
+
+			original := proto.Clone(tt.req)
+
+			// Act
+			_, err := s.Predict(context.Background(), tt.req)
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+			if got.ModelName != tt.wantModel || len(got.Input) != len(tt.wantInput) || got.Input[0] != tt.wantInput[0] {
+				t.Errorf("Expected backend to get %s %v, got %s %v", tt.wantModel, tt.wantInput, got.ModelName, got.Input)
+			}
+			if !proto.Equal(tt.req, original) {
+				t.Errorf("Expected the incoming request not to be modified, got %v", tt.req)
+			}
+		})
+	}
+}
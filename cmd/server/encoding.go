@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Supported PredictRequest output formats.
+const (
+	outputFormatJSON      = "json"
+	outputFormatNDJSON    = "ndjson"
+	outputFormatFloat32LE = "float32-le"
+	outputFormatFloat64LE = "float64-le"
+)
+
+// outputEncodingHeader is the metadata key a client can set to pick the
+// output format without changing the request; it wins over OutputFormat.
+const outputEncodingHeader = "x-output-encoding"
+
+// outputFormat returns the output format of a request, taken from the
+// x-output-encoding metadata when set and from OutputFormat otherwise. An
+// empty format means json. Quantized outputs have their own binary encoding,
+// so every other format is rejected alongside quantization.
+func outputFormat(ctx context.Context, req *pb.PredictRequest) (string, error) {
+	format, source := req.GetOutputFormat(), "output_format"
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := firstValue(md, outputEncodingHeader); v != "" {
+			format, source = v, outputEncodingHeader
+		}
+	}
+	switch format {
+	case "", outputFormatJSON:
+		return outputFormatJSON, nil
+	case outputFormatNDJSON, outputFormatFloat32LE, outputFormatFloat64LE:
+		if req.GetQuantization() != "" {
+			return "", status.Errorf(codes.InvalidArgument, "%s %s cannot be combined with quantization", source, format)
+		}
+		return format, nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unsupported %s %q (want %s, %s, %s or %s)",
+			source, format, outputFormatJSON, outputFormatNDJSON, outputFormatFloat32LE, outputFormatFloat64LE)
+	}
+}
+
+// outputEncoder returns the function that encodes an output in format.
+func outputEncoder(format string) func([]float64) ([]byte, error) {
+	switch format {
+	case outputFormatNDJSON:
+		return encodeNDJSON
+	case outputFormatFloat32LE:
+		return encodeFloat32LE
+	case outputFormatFloat64LE:
+		return encodeFloat64LE
+	}
+	return func(output []float64) ([]byte, error) { return json.Marshal(output) }
+}
+
+// encodeFloat32LE encodes output as little-endian float32 values, the same
+// layout binary backends send.
+func encodeFloat32LE(output []float64) ([]byte, error) {
+	data := make([]byte, 4*len(output))
+	for i, v := range output {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(float32(v)))
+	}
+	return data, nil
+}
+
+// encodeFloat64LE encodes output as little-endian float64 values, which
+// keeps every value exact.
+func encodeFloat64LE(output []float64) ([]byte, error) {
+	data := make([]byte, 8*len(output))
+	for i, v := range output {
+		binary.LittleEndian.PutUint64(data[i*8:], math.Float64bits(v))
+	}
+	return data, nil
+}
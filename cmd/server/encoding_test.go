@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestPredict_OutputEncoding(t *testing.T) {
+	tests := []struct {
+		name         string
+		field        string
+		header       string
+		wantEncoding string
+		wantData     []byte
+	}{
+		{"default", "", "", "", []byte(`[0.5,-2]`)},
+		{"field", outputFormatNDJSON, "", outputFormatNDJSON, []byte("0.5\n-2\n")},
+		{"header", "", outputFormatFloat32LE, outputFormatFloat32LE, []byte{0, 0, 0, 0x3f, 0, 0, 0, 0xc0}},
+		{"header wins over field", outputFormatNDJSON, outputFormatFloat64LE, outputFormatFloat64LE,
+			binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(nil, math.Float64bits(0.5)), math.Float64bits(-2))},
+		{"header asks for json", outputFormatNDJSON, outputFormatJSON, "", []byte(`[0.5,-2]`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, staticBackend(`{"status":"ok","output":[0.5,-2]}`))
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(outputEncodingHeader, tt.header))
+			}
+			req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), OutputFormat: tt.field}
+
+			// Act
+			resp, err := s.Predict(ctx, req)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict failed: %v", err)
+			}
+			if resp.GetOutputEncoding() != tt.wantEncoding {
+				t.Errorf("Expected output encoding %q, got %q", tt.wantEncoding, resp.GetOutputEncoding())
+			}
+			if string(resp.GetOutputData()) != string(tt.wantData) {
+				t.Errorf("Expected output %q, got %q", tt.wantData, resp.GetOutputData())
+			}
+		})
+	}
+}
+
+func TestPredict_RejectsInvalidOutputEncoding(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    *pb.PredictRequest
+		header string
+	}{
+		{"unknown header", &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}, "bfloat16"},
+		{"unknown header with valid field", &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), OutputFormat: outputFormatNDJSON}, "csv"},
+		{"header with quantization", &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Quantization: "int8"}, outputFormatFloat32LE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, staticBackend(`{"status":"ok","output":[1]}`))
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(outputEncodingHeader, tt.header))
+
+			_, err := s.Predict(ctx, tt.req)
+
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
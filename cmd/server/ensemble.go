@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Supported EnsembleRequest aggregations.
+const (
+	aggregateMean   = "mean"
+	aggregateSum    = "sum"
+	aggregateConcat = "concat"
+)
+
+// EnsemblePredict sends one input to several models concurrently and
+// aggregates their outputs. Members that fail are reported in the response
+// alongside the aggregate of the ones that succeeded; the call only fails
+// when every member does.
+func (s *server) EnsemblePredict(ctx context.Context, req *pb.EnsembleRequest) (*pb.EnsembleResponse, error) {
+	logger := loggerFromContext(ctx)
+	start := time.Now()
+	method := "EnsemblePredict"
+	statusLabel := "ok"
+	defer func() {
+		s.observeRequestDuration(ctx, method, time.Since(start))
+		requestCount.WithLabelValues(method, statusLabel).Inc()
+	}()
+
+	if s.draining.Load() {
+		statusLabel = "draining"
+		return nil, status.Errorf(codes.Unavailable, "server draining")
+	}
+
+	models := req.GetModelNames()
+	if len(models) == 0 {
+		statusLabel = "bad-input"
+		return nil, status.Errorf(codes.InvalidArgument, "model_names cannot be empty")
+	}
+	// Members resolve through the alias table as Predict does, but the
+	// response names each one as requested.
+	requested := models
+	models = make([]string, len(requested))
+	for i, model := range requested {
+		models[i] = s.config.resolveAlias(model)
+	}
+	aggregation := req.GetAggregation()
+	switch aggregation {
+	case "":
+		aggregation = aggregateMean
+	case aggregateMean, aggregateSum, aggregateConcat:
+	default:
+		statusLabel = "bad-input"
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported aggregation %q", aggregation)
+	}
+
+	if err := s.checkInputSize(int64(len(req.GetInputData()))); err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+
+	var inputArray []float64
+	if err := timeSerialization("unmarshal_input", func() error {
+		return json.Unmarshal(req.GetInputData(), &inputArray)
+	}); err != nil {
+		statusLabel = "bad-input"
+		return nil, status.Errorf(codes.InvalidArgument, "input_data must be a JSON array of numbers")
+	}
+	if len(inputArray) == 0 {
+		statusLabel = "empty-input"
+		return nil, status.Errorf(codes.InvalidArgument, "input data cannot be empty")
+	}
+	// Every member gets the same input, so it must suit each of them.
+	for _, model := range models {
+		if err := s.checkModelInput(model, req.GetInputData(), inputArray); err != nil {
+			statusLabel = "bad-input"
+			return nil, err
+		}
+	}
+
+	ctx = withBodyLogging(ctx, s.shouldLogBodies())
+	if err := s.shedder.admit(ctx); err != nil {
+		statusLabel = "shed"
+		return nil, err
+	}
+
+	outputs := make([][]float64, len(models))
+	members := make([]*pb.EnsembleMemberResult, len(models))
+	var wg sync.WaitGroup
+	for i, model := range models {
+		wg.Go(func() {
+			member := &pb.EnsembleMemberResult{ModelName: requested[i]}
+			members[i] = member
+
+			input, err := s.config.preprocess(model, inputArray)
+			var apiResponse *APIResponse
+			if err == nil {
+				apiResponse, err = s.sendDataToAPI(ctx, &InputData{ModelName: model, Input: input}, make(http.Header))
+			}
+			if err != nil {
+				logger.Printf("Ensemble member %s failed: %v", model, err)
+				member.ErrorCode = int32(status.Code(err))
+				member.Error = status.Convert(err).Message()
+				return
+			}
+			member.Status = s.config.normalizeStatus(apiResponse.Status)
+			if apiResponse.isMultiHead() {
+				member.ErrorCode = int32(codes.FailedPrecondition)
+				member.Error = "multi-head outputs cannot be aggregated"
+				return
+			}
+			if err := s.checkModelOutput(model, apiResponse); err != nil {
+				logger.Printf("Ensemble member %s returned a bad output: %v", model, err)
+				member.ErrorCode = int32(status.Code(err))
+				member.Error = status.Convert(err).Message()
+				return
+			}
+			member.OutputData, err = json.Marshal(apiResponse.Output)
+			if err != nil {
+				member.ErrorCode = int32(codes.Internal)
+				member.Error = "failed to marshal output: " + err.Error()
+				return
+			}
+			outputs[i] = apiResponse.Output
+		})
+	}
+	wg.Wait()
+
+	var succeeded [][]float64
+	for i, member := range members {
+		if member.ErrorCode == int32(codes.OK) {
+			succeeded = append(succeeded, outputs[i])
+		}
+	}
+	if len(succeeded) == 0 {
+		statusLabel = "api-error"
+		return nil, status.Errorf(codes.Unavailable, "all %d ensemble members failed", len(models))
+	}
+	if len(succeeded) < len(models) {
+		statusLabel = "partial"
+	}
+
+	aggregated, err := aggregateOutputs(aggregation, succeeded)
+	if err != nil {
+		statusLabel = "internal-error"
+		return nil, err
+	}
+	var outputBytes []byte
+	if err := timeSerialization("marshal_output", func() (err error) {
+		outputBytes, err = json.Marshal(aggregated)
+		return err
+	}); err != nil {
+		statusLabel = "internal-error"
+		return nil, status.Errorf(codes.Internal, "failed to marshal output: %v", err)
+	}
+
+	logger.Printf("Ensemble of %d models finished, %d succeeded", len(models), len(succeeded))
+	resp := &pb.EnsembleResponse{OutputData: outputBytes, Members: members}
+	if err := s.checkResponseSize(resp); err != nil {
+		statusLabel = "output-too-large"
+		return nil, err
+	}
+	return resp, nil
+}
+
+// checkModelInput runs the feature count, feature type and input schema
+// checks that Predict applies, for the RPCs that call the backend without
+// going through Predict.
+func (s *server) checkModelInput(model string, data []byte, input []float64) error {
+	if err := s.config.checkFeatureCount(model, len(input)); err != nil {
+		return err
+	}
+	if err := s.config.checkFeatureTypes(model, input); err != nil {
+		return err
+	}
+	return s.config.validateInput(model, data)
+}
+
+// checkModelOutput runs the output length and output schema checks that
+// Predict applies to a single-output response.
+func (s *server) checkModelOutput(model string, resp *APIResponse) error {
+	if err := s.config.checkOutputLength(model, resp.Output); err != nil {
+		return err
+	}
+	return s.config.validateOutput(model, resp)
+}
+
+// aggregateOutputs combines the member outputs with the named aggregation.
+// Mean and sum are element-wise and require equal-length outputs.
+func aggregateOutputs(aggregation string, outputs [][]float64) ([]float64, error) {
+	if aggregation == aggregateConcat {
+		var out []float64
+		for _, o := range outputs {
+			out = append(out, o...)
+		}
+		return out, nil
+	}
+
+	out := make([]float64, len(outputs[0]))
+	for _, o := range outputs {
+		if len(o) != len(out) {
+			return nil, status.Errorf(codes.Internal, "cannot %s ensemble outputs of different lengths (%d and %d)", aggregation, len(out), len(o))
+		}
+		for i, v := range o {
+			out[i] += v
+		}
+	}
+	if aggregation == aggregateMean {
+		for i := range out {
+			out[i] /= float64(len(outputs))
+		}
+	}
+	return out, nil
+}
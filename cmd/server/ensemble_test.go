@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ensembleBackend replies with the output configured for each model and
+// fails with a 500 for models not in outputs.
+func ensembleBackend(outputs map[string][]float64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in InputData
+		json.NewDecoder(r.Body).Decode(&in)
+		out, ok := outputs[in.ModelName]
+		if !ok {
+			http.Error(w, "model failed", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{ModelName: in.ModelName, Output: out, Status: "ok"})
+	}
+}
+
+func TestEnsemblePredict_Aggregations(t *testing.T) {
+	s := newTestServer(t, ensembleBackend(map[string][]float64{
+		"a": {1, 2},
+		"b": {3, 6},
+	}))
+
+	tests := []struct {
+		aggregation string
+		want        []float64
+	}{
+		{"", []float64{2, 4}},
+		{aggregateMean, []float64{2, 4}},
+		{aggregateSum, []float64{4, 8}},
+		{aggregateConcat, []float64{1, 2, 3, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.aggregation, func(t *testing.T) {
+			// Act
+			resp, err := s.EnsemblePredict(context.Background(), &pb.EnsembleRequest{
+				ModelNames:  []string{"a", "b"},
+				InputData:   []byte(`[1]`),
+				Aggregation: tt.aggregation,
+			})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("EnsemblePredict returned error: %v", err)
+			}
+			var got []float64
+			if err := json.Unmarshal(resp.GetOutputData(), &got); err != nil {
+				t.Fatalf("invalid output: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+			if len(resp.GetMembers()) != 2 {
+				t.Errorf("Expected 2 member results, got %d", len(resp.GetMembers()))
+			}
+		})
+	}
+}
+
+func TestEnsemblePredict_PartialFailure(t *testing.T) {
+	// Arrange: "broken" has no output configured, so the backend fails it.
+	s := newTestServer(t, ensembleBackend(map[string][]float64{
+		"a": {1, 2},
+		"b": {3, 4},
+	}))
+
+	// Act
+	resp, err := s.EnsemblePredict(context.Background(), &pb.EnsembleRequest{
+		ModelNames:  []string{"a", "broken", "b"},
+		InputData:   []byte(`[1]`),
+		Aggregation: aggregateSum,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected partial success, got %v", err)
+	}
+	var got []float64
+	json.Unmarshal(resp.GetOutputData(), &got)
+	if !slices.Equal(got, []float64{4, 6}) {
+		t.Errorf("Expected sum of successful members [4 6], got %v", got)
+	}
+
+	members := resp.GetMembers()
+	if len(members) != 3 {
+		t.Fatalf("Expected 3 member results, got %d", len(members))
+	}
+	broken := members[1]
+	if broken.GetModelName() != "broken" || codes.Code(broken.GetErrorCode()) != codes.Internal || broken.GetError() == "" {
+		t.Errorf("Expected broken member to report an Internal error, got %+v", broken)
+	}
+	if members[0].GetErrorCode() != 0 || string(members[0].GetOutputData()) != "[1,2]" {
+		t.Errorf("Expected member a to succeed with its own output, got %+v", members[0])
+	}
+}
+
+func TestEnsemblePredict_Errors(t *testing.T) {
+	s := newTestServer(t, ensembleBackend(map[string][]float64{
+		"short": {1},
+		"long":  {1, 2},
+	}))
+
+	tests := []struct {
+		name string
+		req  *pb.EnsembleRequest
+		want codes.Code
+	}{
+		{"no models", &pb.EnsembleRequest{InputData: []byte(`[1]`)}, codes.InvalidArgument},
+		{"bad aggregation", &pb.EnsembleRequest{ModelNames: []string{"short"}, InputData: []byte(`[1]`), Aggregation: "max"}, codes.InvalidArgument},
+		{"bad input", &pb.EnsembleRequest{ModelNames: []string{"short"}, InputData: []byte(`nope`)}, codes.InvalidArgument},
+		{"all failed", &pb.EnsembleRequest{ModelNames: []string{"x", "y"}, InputData: []byte(`[1]`)}, codes.Unavailable},
+		{"length mismatch", &pb.EnsembleRequest{ModelNames: []string{"short", "long"}, InputData: []byte(`[1]`)}, codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.EnsemblePredict(context.Background(), tt.req)
+			if status.Code(err) != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestEnsemblePredict_ValidatesEachMember(t *testing.T) {
+	// Arrange
+	backendCalls := 0
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		backendCalls++
+		ensembleBackend(map[string][]float64{"a": {1, 2}, "b": {3}})(w, r)
+	})
+	cfg, err := loadConfig(writeConfig(t, "models:\n  a:\n    output_length: 2\n  b:\n    output_length: 2\n  named:\n    feature_names: [x, y]\n"))
+	if err != nil {
+		t.Fatalf("loadConfig returned error: %v", err)
+	}
+	s.config = cfg
+	s.maxInputBytes = 16
+
+	tests := []struct {
+		name string
+		req  *pb.EnsembleRequest
+	}{
+		{"input too large", &pb.EnsembleRequest{ModelNames: []string{"a"}, InputData: []byte(`[1, 2, 3, 4, 5, 6]`)}},
+		{"wrong feature count", &pb.EnsembleRequest{ModelNames: []string{"a", "named"}, InputData: []byte(`[1]`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := s.EnsemblePredict(context.Background(), tt.req)
+
+			// Assert
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+	if backendCalls != 0 {
+		t.Errorf("Expected invalid inputs not to reach the backend, got %d calls", backendCalls)
+	}
+
+	// A member whose output has the wrong length fails on its own.
+	resp, err := s.EnsemblePredict(context.Background(), &pb.EnsembleRequest{ModelNames: []string{"a", "b"}, InputData: []byte(`[1]`)})
+	if err != nil {
+		t.Fatalf("EnsemblePredict returned error: %v", err)
+	}
+	if b := resp.GetMembers()[1]; codes.Code(b.GetErrorCode()) != codes.Internal {
+		t.Errorf("Expected member b to fail its output length check, got %+v", b)
+	}
+	if got := string(resp.GetOutputData()); got != "[1,2]" {
+		t.Errorf("Expected only member a to be aggregated, got %s", got)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"syscall"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// backendErrorDomain is the ErrorInfo domain of errors reported by the
+// model backend.
+const backendErrorDomain = "model-backend"
+
+// serverErrorDomain is the ErrorInfo domain of errors the server raises
+// itself without calling the backend.
+const serverErrorDomain = "inference-server"
+
+// backendErrorBody is the structured error a backend may return with a 4xx
+// status. Message is taken from "error", or from FastAPI's "detail".
+type backendErrorBody struct {
+	Error  string `json:"error"`
+	Detail string `json:"detail"`
+	Code   string `json:"code"`
+}
+
+// wrapStatus prefixes the message of a status error while keeping its code
+// and details intact, so information such as RetryInfo reaches the client.
+func wrapStatus(err error, prefix string) error {
+	p := status.Convert(err).Proto()
+	p.Message = prefix + ": " + p.Message
+	return status.ErrorProto(p)
+}
+
+// isConnectionClosed reports whether err means the peer closed or reset the
+// connection before sending the full response.
+func isConnectionClosed(err error) bool {
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// backendClientError converts a 4xx backend response into InvalidArgument.
+// Structured bodies yield just the backend's message plus an ErrorInfo
+// carrying its error code; anything else is returned verbatim.
+func backendClientError(httpStatus int, body []byte) error {
+	var parsed backendErrorBody
+	if err := json.Unmarshal(body, &parsed); err != nil || (parsed.Error == "" && parsed.Detail == "") {
+		return status.Errorf(codes.InvalidArgument, "API returned status %d: %s", httpStatus, string(body))
+	}
+
+	message := parsed.Error
+	if message == "" {
+		message = parsed.Detail
+	}
+	reason := parsed.Code
+	if reason == "" {
+		reason = "HTTP_" + strconv.Itoa(httpStatus)
+	}
+	st := status.New(codes.InvalidArgument, message)
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   backendErrorDomain,
+		Metadata: map[string]string{"http_status": strconv.Itoa(httpStatus)},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPredict_BackendClientErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		httpStatus  int
+		body        string
+		wantMessage string
+		wantReason  string // empty means no ErrorInfo detail
+	}{
+		{
+			name:        "structured error with code",
+			httpStatus:  http.StatusBadRequest,
+			body:        `{"error":"feature X out of range","code":"FEATURE_OUT_OF_RANGE"}`,
+			wantMessage: "failed to call external API: feature X out of range",
+			wantReason:  "FEATURE_OUT_OF_RANGE",
+		},
+		{
+			name:        "fastapi detail",
+			httpStatus:  http.StatusNotFound,
+			body:        `{"detail":"Model 'm' not found in models directory"}`,
+			wantMessage: "failed to call external API: Model 'm' not found in models directory",
+			wantReason:  "HTTP_404",
+		},
+		{
+			name:        "unstructured body",
+			httpStatus:  http.StatusBadRequest,
+			body:        "bad things happened",
+			wantMessage: "failed to call external API: API returned status 400: bad things happened",
+		},
+		{
+			name:        "json without a message",
+			httpStatus:  http.StatusUnprocessableEntity,
+			body:        `{"detail":[{"loc":["body"],"msg":"field required"}]}`,
+			wantMessage: `failed to call external API: API returned status 422: {"detail":[{"loc":["body"],"msg":"field required"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.httpStatus)
+				w.Write([]byte(tt.body))
+			})
+
+			// Act
+			_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+			// Assert
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("Expected InvalidArgument, got %v", err)
+			}
+			if st.Message() != tt.wantMessage {
+				t.Errorf("Expected message %q, got %q", tt.wantMessage, st.Message())
+			}
+
+			var info *errdetails.ErrorInfo
+			for _, d := range st.Details() {
+				if i, ok := d.(*errdetails.ErrorInfo); ok {
+					info = i
+				}
+			}
+			if tt.wantReason == "" {
+				if info != nil {
+					t.Errorf("Expected no ErrorInfo for an unstructured body, got %v", info)
+				}
+				return
+			}
+			if info == nil {
+				t.Fatal("Expected an ErrorInfo detail")
+			}
+			if info.GetReason() != tt.wantReason || info.GetDomain() != backendErrorDomain {
+				t.Errorf("Expected reason %s in domain %s, got %v", tt.wantReason, backendErrorDomain, info)
+			}
+			if got := info.GetMetadata()["http_status"]; got != strconv.Itoa(tt.httpStatus) {
+				t.Errorf("Expected http_status %d in ErrorInfo metadata, got %q", tt.httpStatus, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/metadata"
+)
+
+// traceparentHeader is the gRPC metadata key carrying the caller's W3C
+// trace context.
+const traceparentHeader = "traceparent"
+
+// sampledTraceID returns the trace ID from the request's traceparent
+// metadata when the caller sampled the trace, or "" when there is no valid,
+// sampled trace context.
+func sampledTraceID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	parts := strings.Split(firstValue(md, traceparentHeader), "-")
+	// Version 00 has exactly four fields; later versions may append more.
+	if len(parts) < 4 || (parts[0] == "00" && len(parts) != 4) || parts[0] == "ff" {
+		return ""
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isLowerHex(version, 2) || !isLowerHex(traceID, 32) || !isLowerHex(spanID, 16) || !isLowerHex(flags, 2) {
+		return ""
+	}
+	if traceID == strings.Repeat("0", 32) || spanID == strings.Repeat("0", 16) {
+		return ""
+	}
+	b, _ := hex.DecodeString(flags)
+	if b[0]&0x01 == 0 {
+		return ""
+	}
+	return traceID
+}
+
+// isLowerHex reports whether s is n lower-case hex digits.
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// observeRequestDuration records d in requestDuration for method. With
+// traceExemplars set, a request that is part of a sampled trace attaches its
+// trace ID as an exemplar, linking the latency bucket to the trace.
+func (s *server) observeRequestDuration(ctx context.Context, method string, d time.Duration) {
+	observer := requestDuration.WithLabelValues(method)
+	if s.traceExemplars {
+		if traceID := sampledTraceID(ctx); traceID != "" {
+			observer.(prometheus.ExemplarObserver).ObserveWithExemplar(d.Seconds(), prometheus.Labels{"trace_id": traceID})
+			return
+		}
+	}
+	observer.Observe(d.Seconds())
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc/metadata"
+)
+
+// hasExemplar reports whether any bucket of method's requestDuration
+// histogram carries an exemplar for traceID.
+func hasExemplar(t *testing.T, method, traceID string) bool {
+	t.Helper()
+	m := &dto.Metric{}
+	if err := requestDuration.WithLabelValues(method).(prometheus.Metric).Write(m); err != nil {
+		t.Fatalf("failed to read histogram: %v", err)
+	}
+	for _, b := range m.GetHistogram().GetBucket() {
+		for _, l := range b.GetExemplar().GetLabel() {
+			if l.GetName() == "trace_id" && l.GetValue() == traceID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestPredict_TraceExemplar(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		traceID  string
+		flags    string
+		wantSeen bool
+	}{
+		{"sampled", true, "4bf92f3577b34da6a3ce929d0e0e4736", "01", true},
+		{"not sampled", true, "5bf92f3577b34da6a3ce929d0e0e4736", "00", false},
+		{"disabled", false, "6bf92f3577b34da6a3ce929d0e0e4736", "01", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, staticBackend(`{"output":[1],"status":"ok"}`))
+			s.traceExemplars = tt.enabled
+			traceparent := "00-" + tt.traceID + "-00f067aa0ba902b7-" + tt.flags
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(traceparentHeader, traceparent))
+
+			// Act
+			_, err := s.Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict returned error: %v", err)
+			}
+			if got := hasExemplar(t, "Predict", tt.traceID); got != tt.wantSeen {
+				t.Errorf("Expected exemplar recorded = %v, got %v", tt.wantSeen, got)
+			}
+		})
+	}
+}
+
+func TestSampledTraceID(t *testing.T) {
+	tests := []struct {
+		traceparent string
+		want        string
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-03", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", ""},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", ""},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", ""},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", ""},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", ""},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ""},
+		{"00-4bf92f35-00f067aa0ba902b7-01", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.traceparent, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(traceparentHeader, tt.traceparent))
+			if got := sampledTraceID(ctx); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
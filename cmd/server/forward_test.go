@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+func TestPredict_ForwardsConfiguredFields(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"status":"ok","output":[1],`+
+		`"explanation":{"top_feature":"sqft","weight":0.42},"latency_ms":12.5,"internal_trace":"abc"}`))
+	cfg, err := loadConfig(writeConfig(t, `
+models:
+  housing:
+    forward_fields: [explanation, latency_ms, not_sent]
+`))
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+	s.config = cfg
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "housing", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	want := map[string]string{
+		"explanation": `{"top_feature":"sqft","weight":0.42}`,
+		"latency_ms":  `12.5`,
+	}
+	if len(resp.GetMetadata()) != len(want) {
+		t.Errorf("Expected only %d forwarded fields, got %v", len(want), resp.GetMetadata())
+	}
+	for field, value := range want {
+		if got := string(resp.GetMetadata()[field]); got != value {
+			t.Errorf("Expected %s = %s verbatim, got %s", field, value, got)
+		}
+	}
+}
+
+func TestPredict_ForwardsNothingByDefault(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"status":"ok","output":[1],"explanation":"x"}`))
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "housing", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	if len(resp.GetMetadata()) != 0 {
+		t.Errorf("Expected no metadata without forward_fields, got %v", resp.GetMetadata())
+	}
+}
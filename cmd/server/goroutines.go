@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"log"
+	"runtime"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var goroutineCount = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "inference_goroutines",
+		Help: "Number of goroutines at the last sample of the goroutine monitor",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(goroutineCount)
+}
+
+// goroutineMonitor samples the goroutine count so leaks and runaway fan-out
+// show up early. It does not cap the count; it exports it and warns once
+// each time it rises past threshold.
+type goroutineMonitor struct {
+	threshold int
+	count     func() int
+	logger    *log.Logger
+
+	// above is true while the last sample was over threshold; it's only
+	// touched by the sampling goroutine.
+	above bool
+}
+
+// newGoroutineMonitor returns a monitor warning above threshold goroutines.
+// A non-positive threshold only exports the gauge.
+func newGoroutineMonitor(threshold int, logger *log.Logger) *goroutineMonitor {
+	return &goroutineMonitor{threshold: threshold, count: runtime.NumGoroutine, logger: logger}
+}
+
+// run samples every interval until ctx is done. A non-positive interval
+// disables the monitor.
+func (m *goroutineMonitor) run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.sample()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample records the current goroutine count and logs when it crosses the
+// threshold in either direction.
+func (m *goroutineMonitor) sample() {
+	n := m.count()
+	goroutineCount.Set(float64(n))
+	if m.threshold <= 0 {
+		return
+	}
+	above := n > m.threshold
+	if above && !m.above {
+		m.logger.Printf("WARNING: %d goroutines exceed the threshold of %d; possible goroutine leak", n, m.threshold)
+	} else if !above && m.above {
+		m.logger.Printf("Goroutine count back to %d, below the threshold of %d", n, m.threshold)
+	}
+	m.above = above
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestGoroutineMonitor_GaugeAndWarning(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	m := newGoroutineMonitor(100, log.New(&buf, "", 0))
+	counts := []int{50, 150, 200, 80}
+	m.count = func() int {
+		n := counts[0]
+		counts = counts[1:]
+		return n
+	}
+
+	// Act & Assert: below the threshold only the gauge changes.
+	m.sample()
+	if got := testutil.ToFloat64(goroutineCount); got != 50 {
+		t.Errorf("Expected gauge 50, got %v", got)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no warning below the threshold, got %q", buf.String())
+	}
+
+	// Crossing the threshold warns once, not on every sample above it.
+	m.sample()
+	m.sample()
+	if got := testutil.ToFloat64(goroutineCount); got != 200 {
+		t.Errorf("Expected gauge 200, got %v", got)
+	}
+	if n := strings.Count(buf.String(), "WARNING: 150 goroutines exceed the threshold of 100"); n != 1 {
+		t.Errorf("Expected exactly one warning, logs:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "WARNING: 200") {
+		t.Errorf("Expected no repeated warning while above the threshold, logs:\n%s", buf.String())
+	}
+
+	// Dropping back below logs the recovery.
+	m.sample()
+	if !strings.Contains(buf.String(), "Goroutine count back to 80") {
+		t.Errorf("Expected a recovery log, logs:\n%s", buf.String())
+	}
+}
+
+func TestGoroutineMonitor_NoThresholdOnlyExports(t *testing.T) {
+	var buf bytes.Buffer
+	m := newGoroutineMonitor(0, log.New(&buf, "", 0))
+	m.count = func() int { return 1 << 20 }
+
+	m.sample()
+
+	if got := testutil.ToFloat64(goroutineCount); got != 1<<20 {
+		t.Errorf("Expected gauge %d, got %v", 1<<20, got)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no warning without a threshold, got %q", buf.String())
+	}
+}
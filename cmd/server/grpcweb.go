@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+)
+
+// grpcWebHandler serves gRPC-Web calls, as sent by browser clients, with
+// grpcServer and passes every other request to next. The calls go through
+// the same interceptors and handlers as native gRPC. origins lists the
+// origins allowed to make cross-origin calls; "*" allows any. The calls
+// are exempt from the HTTP server's write timeout: like native gRPC calls
+// they are bounded by their deadline, which may allow a slow backend more
+// time.
+func grpcWebHandler(grpcServer *grpc.Server, origins []string, next http.Handler) http.Handler {
+	wrapped := grpcweb.WrapServer(grpcServer, grpcweb.WithOriginFunc(func(origin string) bool {
+		return slices.Contains(origins, "*") || slices.Contains(origins, origin)
+	}))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wrapped.IsGrpcWebRequest(r) || wrapped.IsAcceptableGrpcCorsRequest(r) {
+			http.NewResponseController(w).SetWriteDeadline(time.Time{})
+			wrapped.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseOrigins splits a comma-separated -grpc-web-origins value.
+func parseOrigins(s string) []string {
+	var origins []string
+	for origin := range strings.SplitSeq(s, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// grpcWebFrame encodes m as a single gRPC-Web data frame.
+func grpcWebFrame(t *testing.T, m proto.Message) []byte {
+	t.Helper()
+	payload, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	frame := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+// newGRPCWebServer serves s over gRPC-Web alongside a plain HTTP handler.
+func newGRPCWebServer(t *testing.T, s *server, origins []string) *httptest.Server {
+	t.Helper()
+	grpcServer := grpc.NewServer()
+	pb.RegisterInferenceServer(grpcServer, s)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) })
+	web := httptest.NewServer(grpcWebHandler(grpcServer, origins, mux))
+	t.Cleanup(web.Close)
+	return web
+}
+
+func TestGRPCWeb_Predict(t *testing.T) {
+	// Arrange: a request shaped like one from grpc-web in a browser.
+	s := newTestServer(t, staticBackend(`{"status":"ok","output":[0.25,0.75]}`))
+	web := newGRPCWebServer(t, s, []string{"https://app.example.com"})
+	body := grpcWebFrame(t, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+	req, _ := http.NewRequest(http.MethodPost, web.URL+pb.Inference_Predict_FullMethodName, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	req.Header.Set("X-Grpc-Web", "1")
+	req.Header.Set("Origin", "https://app.example.com")
+
+	// Act
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+
+	// Assert
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Expected the origin to be allowed, got %q", got)
+	}
+	if len(raw) < 5 || raw[0] != 0 {
+		t.Fatalf("Expected a data frame, got %q", raw)
+	}
+	n := binary.BigEndian.Uint32(raw[1:5])
+	var out pb.PredictResponse
+	if err := proto.Unmarshal(raw[5:5+n], &out); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if string(out.GetOutputData()) != "[0.25,0.75]" {
+		t.Errorf("Expected the backend output, got %s", out.GetOutputData())
+	}
+	if trailer := string(raw[5+n:]); !strings.Contains(trailer, "grpc-status: 0") {
+		t.Errorf("Expected an OK status trailer, got %q", trailer)
+	}
+}
+
+func TestGRPCWeb_OutlastsHTTPWriteTimeout(t *testing.T) {
+	// Arrange: a backend slower than the HTTP server's write timeout.
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		staticBackend(`{"status":"ok","output":[1]}`)(w, r)
+	})
+	grpcServer := grpc.NewServer()
+	pb.RegisterInferenceServer(grpcServer, s)
+	web := httptest.NewUnstartedServer(grpcWebHandler(grpcServer, nil, http.NewServeMux()))
+	web.Config.WriteTimeout = 50 * time.Millisecond
+	web.Start()
+	t.Cleanup(web.Close)
+	body := grpcWebFrame(t, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+	req, _ := http.NewRequest(http.MethodPost, web.URL+pb.Inference_Predict_FullMethodName, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	req.Header.Set("X-Grpc-Web", "1")
+
+	// Act
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+
+	// Assert
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected a complete 200 response, got %d and %v", resp.StatusCode, err)
+	}
+	if !strings.Contains(string(raw), "grpc-status: 0") {
+		t.Errorf("Expected an OK status trailer, got %q", raw)
+	}
+}
+
+func TestGRPCWeb_CORSPreflight(t *testing.T) {
+	tests := []struct {
+		name      string
+		origins   []string
+		origin    string
+		wantAllow string
+	}{
+		{"listed origin", []string{"https://app.example.com"}, "https://app.example.com", "https://app.example.com"},
+		{"any origin", []string{"*"}, "https://other.example.com", "https://other.example.com"},
+		{"unlisted origin", []string{"https://app.example.com"}, "https://evil.example.com", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, staticBackend(`{"status":"ok","output":[1]}`))
+			web := newGRPCWebServer(t, s, tt.origins)
+			req, _ := http.NewRequest(http.MethodOptions, web.URL+pb.Inference_Predict_FullMethodName, nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web")
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+
+			if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tt.wantAllow {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.wantAllow, got)
+			}
+		})
+	}
+}
+
+func TestGRPCWeb_PassesOtherRequestsThrough(t *testing.T) {
+	s := newTestServer(t, staticBackend(`{"status":"ok","output":[1]}`))
+	web := newGRPCWebServer(t, s, nil)
+
+	resp, err := http.Get(web.URL + "/health")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Errorf("Expected the plain handler to serve /health, got %d %q", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gzipMagic is the header every gzip stream starts with.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// sniffGzipBody decompresses a backend response body that is gzip data
+// even though the response had no Content-Encoding, which misconfigured
+// backends send. Bodies that don't start with the gzip magic bytes are
+// returned unchanged. limit caps the decompressed size as -max-output-bytes
+// caps the raw body; 0 means unlimited.
+func sniffGzipBody(body []byte, limit int64) ([]byte, bool, error) {
+	if !bytes.HasPrefix(body, gzipMagic) {
+		return body, false, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, true, status.Errorf(codes.Internal, "backend response looks gzip-compressed but is not: %v", err)
+	}
+	var r io.Reader = zr
+	if limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, true, status.Errorf(codes.Internal, "failed to decompress gzip backend response: %v", err)
+	}
+	if limit > 0 && int64(len(decompressed)) > limit {
+		return nil, true, status.Errorf(codes.Internal, "decompressed backend response exceeds limit of %d bytes", limit)
+	}
+	return decompressed, true, nil
+}
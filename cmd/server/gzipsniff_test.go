@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gzipBackend answers with body gzip-compressed but no Content-Encoding.
+func gzipBackend(body string) http.HandlerFunc {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(body))
+	zw.Close()
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(buf.Bytes())
+	}
+}
+
+func TestPredict_GzipBodyWithoutContentEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		sniff    bool
+		wantCode codes.Code
+	}{
+		{"sniffing enabled", true, codes.OK},
+		{"sniffing disabled", false, codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, gzipBackend(`{"output":[1,2],"status":"ok"}`))
+			s.sniffGzip = tt.sniff
+
+			// Act
+			resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if err == nil && string(resp.GetOutputData()) != "[1,2]" {
+				t.Errorf("Expected the decompressed output [1,2], got %s", resp.GetOutputData())
+			}
+		})
+	}
+}
+
+func TestSniffGzipBody(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte("0123456789"))
+	zw.Close()
+
+	tests := []struct {
+		name        string
+		body        []byte
+		limit       int64
+		want        []byte
+		wantSniffed bool
+		wantErr     bool
+	}{
+		{"plain body", []byte(`{"output":[1]}`), 0, []byte(`{"output":[1]}`), false, false},
+		{"gzip body", compressed.Bytes(), 0, []byte("0123456789"), true, false},
+		{"gzip body within limit", compressed.Bytes(), 10, []byte("0123456789"), true, false},
+		{"gzip body over limit", compressed.Bytes(), 9, nil, true, true},
+		{"corrupt gzip", []byte{0x1f, 0x8b, 0, 0}, 0, nil, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, sniffed, err := sniffGzipBody(tt.body, tt.limit)
+
+			if (err != nil) != tt.wantErr || sniffed != tt.wantSniffed {
+				t.Fatalf("Expected sniffed=%v, error=%v; got sniffed=%v, error %v", tt.wantSniffed, tt.wantErr, sniffed, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected body %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
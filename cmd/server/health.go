@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// healthHandler reports whether the gRPC server is currently accepting RPCs,
+// so the check fails when the process is alive but can no longer serve.
+func (s *server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.serving.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("not serving"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// readyHandler reports whether the server should receive traffic: it must be
+// serving, not drained or stopping, and the backend must pass its background
+// probe.
+func (s *server) readyHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.serving.Load() || s.warming.Load() || s.draining.Load() || s.stopping.Load() || !s.prober.isHealthy() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("not ready"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ready"))
+}
+
+// preStopHandler is meant for a Kubernetes preStop hook. It marks the server
+// not ready and then holds the hook for preStopGrace, giving load balancers
+// time to deregister the pod before SIGTERM starts the shutdown. Requests
+// keep being served meanwhile.
+func (s *server) preStopHandler(w http.ResponseWriter, r *http.Request) {
+	s.stopping.Store(true)
+	log.Printf("preStop hook called: not ready, waiting %v for load balancers to deregister", s.preStopGrace)
+
+	// The grace period may exceed the metrics server's write timeout.
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	if err := sleepContext(r.Context(), s.preStopGrace); err != nil {
+		return
+	}
+	w.Write([]byte("stopping"))
+}
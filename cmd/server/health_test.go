@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthHandler(t *testing.T) {
+	s := &server{}
+
+	check := func(wantCode int) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.healthHandler(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		if rec.Code != wantCode {
+			t.Errorf("Expected status %d, got %d (%s)", wantCode, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Not serving until the gRPC server has started.
+	check(http.StatusServiceUnavailable)
+
+	s.serving.Store(true)
+	check(http.StatusOK)
+
+	// The listener died or shutdown began.
+	s.serving.Store(false)
+	check(http.StatusServiceUnavailable)
+}
+
+func TestPreStopHandler(t *testing.T) {
+	// Arrange
+	s := &server{preStopGrace: 50 * time.Millisecond}
+	s.serving.Store(true)
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		s.readyHandler(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return rec.Code
+	}
+	if code := ready(); code != http.StatusOK {
+		t.Fatalf("Expected ready before preStop, got %d", code)
+	}
+
+	// Act
+	done := make(chan int)
+	start := time.Now()
+	go func() {
+		rec := httptest.NewRecorder()
+		s.preStopHandler(rec, httptest.NewRequest(http.MethodGet, "/preStop", nil))
+		done <- rec.Code
+	}()
+
+	// Assert: readiness fails while the hook is still waiting...
+	deadline := time.Now().Add(time.Second)
+	for ready() != http.StatusServiceUnavailable {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected readiness to fail once preStop was called")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// ...and the hook returns 200 after the grace period, leaving the
+	// server not ready.
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("Expected 200 from preStop, got %d", code)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected preStop to block for the grace period, returned after %v", elapsed)
+	}
+	if code := ready(); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected to stay not ready after preStop, got %d", code)
+	}
+}
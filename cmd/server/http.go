@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// newHTTPServer returns the server for the metrics/admin endpoints. Every
+// timeout is set so a client trickling bytes (slow-loris) cannot hold a
+// connection open indefinitely: headers must arrive within readTimeout, the
+// whole request within readTimeout, and the response must be written within
+// writeTimeout.
+func newHTTPServer(addr string, handler http.Handler, readTimeout, writeTimeout time.Duration) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: readTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       2 * readTimeout,
+	}
+}
+
+// backendDialer returns the dialer for backend connections. TCP keepalive
+// probes are sent every keepAlive on idle connections so that NATs and load
+// balancers don't silently drop pooled connections, which would otherwise
+// surface as "connection reset" on the next request. A negative keepAlive
+// disables the probes; zero uses the Go default of 15s.
+func backendDialer(keepAlive time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: keepAlive,
+	}
+}
+
+// newBackendClient returns the client used for backend calls, dialing new
+// connections with dialer. Backend calls are bounded per call by
+// -backend-timeout instead of a client-wide timeout so models can override
+// it. disableKeepAlives gives every call a fresh connection, to reproduce
+// connection setup problems that pooling would hide. useHTTP2 speaks only
+// HTTP/2, over cleartext (h2c with prior knowledge) for http:// backends,
+// so concurrent calls are multiplexed over one connection. maxIdlePerHost,
+// when larger than Go's default of 2, raises the number of idle connections
+// kept per backend host, so a warm pool of that size isn't trimmed.
+func newBackendClient(dialer *net.Dialer, disableKeepAlives, useHTTP2 bool, maxIdlePerHost int) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.DisableKeepAlives = disableKeepAlives
+	if maxIdlePerHost > http.DefaultMaxIdleConnsPerHost {
+		transport.MaxIdleConnsPerHost = maxIdlePerHost
+		transport.MaxIdleConns = max(transport.MaxIdleConns, maxIdlePerHost)
+	}
+	if useHTTP2 {
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP2(true)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return &http.Client{Transport: transport}
+}
+
+// defaultBackendClient is used by a server constructed without an
+// httpClient, as when it is embedded in another program or built directly in
+// a test.
+var defaultBackendClient = sync.OnceValue(func() *http.Client {
+	return newBackendClient(backendDialer(0), false, false, 0)
+})
+
+// backendClient returns the client for backend calls, falling back to
+// defaultBackendClient when s.httpClient is nil.
+func (s *server) backendClient() *http.Client {
+	if s.httpClient != nil {
+		return s.httpClient
+	}
+	return defaultBackendClient()
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+func TestHTTPServer_ClosesSlowClients(t *testing.T) {
+	// Arrange
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	srv := newHTTPServer(lis.Addr().String(), handler, 50*time.Millisecond, time.Second)
+	go srv.Serve(lis)
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	// Act: send an incomplete request and then stall, as a slow-loris
+	// client would.
+	start := time.Now()
+	if _, err := conn.Write([]byte("GET /health HTTP/1.1\r\nHost: localhost\r\n")); err != nil {
+		t.Fatalf("failed to write partial request: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+
+	// Assert: the server hangs up once the read timeout elapses.
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		t.Fatal("Expected server to close the stalled connection, but it stayed open")
+	}
+	if err == nil {
+		t.Fatal("Expected connection to be closed without a response")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected connection to stay open for the read timeout, closed after %v", elapsed)
+	}
+}
+
+func TestBackendClient_DialsWithConfiguredDialer(t *testing.T) {
+	// Arrange
+	backend := httptest.NewServer(staticBackend(`{"status":"ok","output":[1]}`))
+	defer backend.Close()
+	dialer := backendDialer(time.Minute)
+	dials := 0
+	dialer.Control = func(network, address string, c syscall.RawConn) error {
+		dials++
+		return nil
+	}
+	client := newBackendClient(dialer, false, false, 0)
+
+	// Act
+	resp, err := client.Get(backend.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	// Assert
+	if dialer.KeepAlive != time.Minute {
+		t.Errorf("Expected a keepalive interval of 1m, got %v", dialer.KeepAlive)
+	}
+	if dials != 1 {
+		t.Errorf("Expected the transport to dial through the configured dialer once, got %d dials", dials)
+	}
+}
+
+func TestBackendClient_DisableKeepAlives(t *testing.T) {
+	tests := []struct {
+		name      string
+		disable   bool
+		wantDials int
+	}{
+		{"reuses connections by default", false, 1},
+		{"fresh connection per call", true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			backend := httptest.NewServer(staticBackend(`{"status":"ok","output":[1]}`))
+			defer backend.Close()
+			dialer := backendDialer(time.Minute)
+			var dials atomic.Int32
+			dialer.Control = func(network, address string, c syscall.RawConn) error {
+				dials.Add(1)
+				return nil
+			}
+			client := newBackendClient(dialer, tt.disable, false, 0)
+
+			// Act
+			for range 2 {
+				resp, err := client.Get(backend.URL)
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+
+			// Assert
+			if got := client.Transport.(*http.Transport).DisableKeepAlives; got != tt.disable {
+				t.Errorf("Expected DisableKeepAlives=%v, got %v", tt.disable, got)
+			}
+			if got := dials.Load(); got != int32(tt.wantDials) {
+				t.Errorf("Expected %d dials, got %d", tt.wantDials, got)
+			}
+		})
+	}
+}
+
+func TestBackendClient_HTTP2Multiplexes(t *testing.T) {
+	// Arrange: an h2c backend that holds every call until all have arrived,
+	// so they must be in flight at the same time.
+	const calls = 5
+	var arrived sync.WaitGroup
+	arrived.Add(calls)
+	var protos sync.Map
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protos.Store(r.Proto, true)
+		if r.URL.Path == "/" {
+			return
+		}
+		arrived.Done()
+		arrived.Wait()
+		staticBackend(`{"status":"ok","output":[1]}`)(w, r)
+	}))
+	backend.Config.Protocols = new(http.Protocols)
+	backend.Config.Protocols.SetHTTP1(true)
+	backend.Config.Protocols.SetUnencryptedHTTP2(true)
+	backend.Start()
+	defer backend.Close()
+
+	dialer := backendDialer(time.Minute)
+	var dials atomic.Int32
+	dialer.Control = func(network, address string, c syscall.RawConn) error {
+		dials.Add(1)
+		return nil
+	}
+	client := newBackendClient(dialer, false, true, 0)
+	// Open the connection first, as a warm pool would have.
+	resp, err := client.Get(backend.URL + "/")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	// Act
+	var wg sync.WaitGroup
+	for range calls {
+		wg.Go(func() {
+			resp, err := client.Post(backend.URL+"/predict", "application/json", strings.NewReader(`{}`))
+			if err != nil {
+				t.Errorf("request failed: %v", err)
+				return
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		})
+	}
+	wg.Wait()
+
+	// Assert
+	if _, ok := protos.Load("HTTP/2.0"); !ok {
+		t.Error("Expected the backend to be called over HTTP/2")
+	}
+	if _, ok := protos.Load("HTTP/1.1"); ok {
+		t.Error("Expected no HTTP/1.1 calls")
+	}
+	if got := dials.Load(); got != 1 {
+		t.Errorf("Expected %d concurrent calls to share one connection, got %d dials", calls, got)
+	}
+}
+
+func TestPredict_WithoutHTTPClient(t *testing.T) {
+	// Arrange
+	backend := httptest.NewServer(staticBackend(`{"output":[0.5],"status":"ok"}`))
+	t.Cleanup(backend.Close)
+	t.Setenv("MODEL_SERVER_URL", backend.URL)
+	s := &server{}
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if got := string(resp.GetOutputData()); got != "[0.5]" {
+		t.Errorf("Expected output [0.5], got %s", got)
+	}
+	if s.backendClient() != defaultBackendClient() {
+		t.Error("Expected the shared default client to be used")
+	}
+}
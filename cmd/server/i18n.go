@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const acceptLanguageHeader = "accept-language"
+
+// Message IDs of the errors that carry a localized message.
+const (
+	msgEmptyInput         = "empty_input"
+	msgMalformedInput     = "malformed_input"
+	msgBackendUnavailable = "backend_unavailable"
+)
+
+// supportedLocales lists the catalog's locales; the first is the fallback.
+var supportedLocales = []language.Tag{language.English, language.Spanish, language.French, language.German}
+
+var localeMatcher = language.NewMatcher(supportedLocales)
+
+// messageCatalog holds the localized text of each message ID by locale.
+var messageCatalog = map[string]map[string]string{
+	"en": {
+		msgEmptyInput:         "The input data cannot be empty.",
+		msgMalformedInput:     "The input data must be a JSON array of numbers.",
+		msgBackendUnavailable: "The model backend is temporarily unavailable. Please try again later.",
+	},
+	"es": {
+		msgEmptyInput:         "Los datos de entrada no pueden estar vacíos.",
+		msgMalformedInput:     "Los datos de entrada deben ser un arreglo JSON de números.",
+		msgBackendUnavailable: "El servicio del modelo no está disponible temporalmente. Inténtelo de nuevo más tarde.",
+	},
+	"fr": {
+		msgEmptyInput:         "Les données d'entrée ne peuvent pas être vides.",
+		msgMalformedInput:     "Les données d'entrée doivent être un tableau JSON de nombres.",
+		msgBackendUnavailable: "Le service du modèle est temporairement indisponible. Veuillez réessayer plus tard.",
+	},
+	"de": {
+		msgEmptyInput:         "Die Eingabedaten dürfen nicht leer sein.",
+		msgMalformedInput:     "Die Eingabedaten müssen ein JSON-Array von Zahlen sein.",
+		msgBackendUnavailable: "Das Modell-Backend ist vorübergehend nicht verfügbar. Bitte versuchen Sie es später erneut.",
+	},
+}
+
+// localize attaches a LocalizedMessage detail for msgID to err when the
+// client sent accept-language metadata. Unsupported locales fall back to
+// English; err is returned unchanged when no locale was requested.
+func localize(ctx context.Context, err error, msgID string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	accept := firstValue(md, acceptLanguageHeader)
+	if accept == "" {
+		return err
+	}
+
+	locale := "en"
+	if prefs, _, parseErr := language.ParseAcceptLanguage(accept); parseErr == nil {
+		tag, _, _ := localeMatcher.Match(prefs...)
+		base, _ := tag.Base()
+		locale = base.String()
+	}
+	text, ok := messageCatalog[locale][msgID]
+	if !ok {
+		locale = "en"
+		text = messageCatalog[locale][msgID]
+	}
+
+	withDetails, detailErr := status.Convert(err).WithDetails(&errdetails.LocalizedMessage{Locale: locale, Message: text})
+	if detailErr != nil {
+		return err
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// localizedMessage returns the LocalizedMessage detail of err, or nil.
+func localizedMessage(err error) *errdetails.LocalizedMessage {
+	for _, d := range status.Convert(err).Details() {
+		if m, ok := d.(*errdetails.LocalizedMessage); ok {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestPredict_LocalizedErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		accept     string
+		input      string
+		wantLocale string
+		wantText   string
+	}{
+		{"spanish empty input", "es", `[]`, "es", messageCatalog["es"][msgEmptyInput]},
+		{"french malformed input", "fr-CA,fr;q=0.9,en;q=0.8", `nope`, "fr", messageCatalog["fr"][msgMalformedInput]},
+		{"german preferred over english", "ja, de;q=0.8, en;q=0.5", `[]`, "de", messageCatalog["de"][msgEmptyInput]},
+		{"unsupported locale falls back to english", "ja", `[]`, "en", messageCatalog["en"][msgEmptyInput]},
+		{"unparseable header falls back to english", ";;;", `[]`, "en", messageCatalog["en"][msgEmptyInput]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := &server{}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(acceptLanguageHeader, tt.accept))
+
+			// Act
+			_, err := s.Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(tt.input)})
+
+			// Assert
+			msg := localizedMessage(err)
+			if msg == nil {
+				t.Fatalf("Expected a LocalizedMessage detail, got %v", err)
+			}
+			if msg.GetLocale() != tt.wantLocale || msg.GetMessage() != tt.wantText {
+				t.Errorf("Expected %s %q, got %s %q", tt.wantLocale, tt.wantText, msg.GetLocale(), msg.GetMessage())
+			}
+		})
+	}
+}
+
+func TestPredict_LocalizedBackendUnavailable(t *testing.T) {
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Hijack and close to simulate an unreachable backend.
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(acceptLanguageHeader, "es"))
+
+	_, err := s.Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	msg := localizedMessage(err)
+	if msg == nil || msg.GetLocale() != "es" || msg.GetMessage() != messageCatalog["es"][msgBackendUnavailable] {
+		t.Errorf("Expected a Spanish backend-unavailable message, got %v (%v)", msg, err)
+	}
+}
+
+func TestPredict_NoLocalizedMessageWithoutAcceptLanguage(t *testing.T) {
+	s := &server{}
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[]`)})
+	if msg := localizedMessage(err); msg != nil {
+		t.Errorf("Expected no LocalizedMessage without accept-language, got %v", msg)
+	}
+}
+
+func TestMessageCatalog_Complete(t *testing.T) {
+	for locale, messages := range messageCatalog {
+		for id := range messageCatalog["en"] {
+			if messages[id] == "" {
+				t.Errorf("Locale %s is missing message %s", locale, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// errBackendStalled is the cancellation cause of a backend call whose
+// response body stopped arriving for longer than -backend-idle-timeout.
+var errBackendStalled = errors.New("backend stalled")
+
+// idleReader wraps a backend response body and cancels the call when no
+// bytes arrive for timeout. Every read that returns data pushes the deadline
+// back, so a backend streaming a large output slowly is not cut off the way a
+// fixed timeout would.
+type idleReader struct {
+	r       io.Reader
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+// newIdleReader starts the idle timer; call stop once the body is read.
+func newIdleReader(r io.Reader, timeout time.Duration, cancel context.CancelCauseFunc) *idleReader {
+	return &idleReader{
+		r:       r,
+		timeout: timeout,
+		timer: time.AfterFunc(timeout, func() {
+			cancel(fmt.Errorf("%w: no data received for %v", errBackendStalled, timeout))
+		}),
+	}
+}
+
+func (ir *idleReader) Read(p []byte) (int, error) {
+	n, err := ir.r.Read(p)
+	if n > 0 {
+		ir.timer.Reset(ir.timeout)
+	}
+	return n, err
+}
+
+func (ir *idleReader) stop() {
+	ir.timer.Stop()
+}
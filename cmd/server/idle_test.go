@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tricklingBackend writes a prediction in chunks, waiting gap between them.
+// A negative gap sends the first chunk and then stalls until the request is
+// canceled.
+func tricklingBackend(chunks []string, gap time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		for i, chunk := range chunks {
+			if i > 0 {
+				if gap < 0 {
+					<-r.Context().Done()
+					return
+				}
+				time.Sleep(gap)
+			}
+			w.Write([]byte(chunk))
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+func TestCallBackend_IdleTimeout(t *testing.T) {
+	chunks := []string{`{"status":"ok","output":[`, `1,`, `2,`, `3,`, `4,`, `5,`, `6]}`}
+	tests := []struct {
+		name     string
+		gap      time.Duration
+		wantCode codes.Code
+	}{
+		// The whole response takes ~180ms, longer than -backend-timeout,
+		// but no gap reaches the idle timeout.
+		{"slow but progressing", 30 * time.Millisecond, codes.OK},
+		{"stalled", -1, codes.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, tricklingBackend(chunks, tt.gap))
+			s.backendTimeout = 100 * time.Millisecond
+			s.backendIdleTimeout = 80 * time.Millisecond
+			req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}
+
+			// Act
+			start := time.Now()
+			resp, err := s.Predict(context.Background(), req)
+
+			// Assert
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK && string(resp.GetOutputData()) != "[1,2,3,4,5,6]" {
+				t.Errorf("Expected the full output, got %s", resp.GetOutputData())
+			}
+			if tt.wantCode != codes.OK {
+				if !strings.Contains(err.Error(), "backend stalled") {
+					t.Errorf("Expected a stall error, got %v", err)
+				}
+				if elapsed := time.Since(start); elapsed > time.Second {
+					t.Errorf("Expected the stall to be detected after the idle timeout, took %v", elapsed)
+				}
+			}
+		})
+	}
+}
+
+func TestCallBackend_IdleTimeoutStillBoundsHeaders(t *testing.T) {
+	// Arrange: the backend never sends response headers.
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Reading the body lets the server notice the client going away.
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	})
+	s.backendTimeout = 50 * time.Millisecond
+	s.backendIdleTimeout = time.Minute
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}
+
+	// Act
+	_, err := s.Predict(context.Background(), req)
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "no response headers within 50ms") {
+		t.Errorf("Expected the header wait to time out, got %v", err)
+	}
+}
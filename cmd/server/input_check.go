@@ -0,0 +1,57 @@
+package main
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// inputKind classifies an input vector by how much information it carries.
+type inputKind int
+
+const (
+	inputVaried inputKind = iota
+	inputAllZero
+	inputConstant
+)
+
+func (k inputKind) String() string {
+	switch k {
+	case inputAllZero:
+		return "all-zero"
+	case inputConstant:
+		return "constant"
+	default:
+		return "varied"
+	}
+}
+
+var degenerateInputs = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "inference_degenerate_inputs_total",
+		Help: "Number of inputs that were all-zero or constant, usually a sign of a client bug",
+	},
+	[]string{"kind"},
+)
+
+func init() {
+	prometheus.MustRegister(degenerateInputs)
+}
+
+// classifyInput reports whether every element of the input is zero, every
+// element holds the same non-zero value, or neither. A single-element input
+// is only ever reported as all-zero or varied, since it is trivially constant.
+func classifyInput(input []float64) inputKind {
+	if len(input) == 0 {
+		return inputVaried
+	}
+	first := input[0]
+	for _, v := range input[1:] {
+		if v != first {
+			return inputVaried
+		}
+	}
+	if first == 0 {
+		return inputAllZero
+	}
+	if len(input) == 1 {
+		return inputVaried
+	}
+	return inputConstant
+}
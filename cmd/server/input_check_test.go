@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+func TestClassifyInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []float64
+		want  inputKind
+	}{
+		{"empty", nil, inputVaried},
+		{"single zero", []float64{0}, inputAllZero},
+		{"single value", []float64{3.5}, inputVaried},
+		{"all zero", []float64{0, 0, 0, 0}, inputAllZero},
+		{"negative zero", []float64{0, -0.0}, inputAllZero},
+		{"constant", []float64{1.5, 1.5, 1.5}, inputConstant},
+		{"varied", []float64{1, 2, 3}, inputVaried},
+		{"zero then value", []float64{0, 0, 1}, inputVaried},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyInput(tt.input); got != tt.want {
+				t.Errorf("classifyInput(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredict_ZeroInputShortCircuit(t *testing.T) {
+	// Arrange
+	backendCalls := 0
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		backendCalls++
+		w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+	})
+	s.zeroInputResponse = []float64{0.25, 0.75}
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{
+		ModelName: "m",
+		InputData: []byte(`[0, 0, 0]`),
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if backendCalls != 0 {
+		t.Errorf("Expected no backend calls, got %d", backendCalls)
+	}
+	var output []float64
+	if err := json.Unmarshal(resp.OutputData, &output); err != nil {
+		t.Fatalf("failed to decode output: %v", err)
+	}
+	if len(output) != 2 || output[0] != 0.25 || output[1] != 0.75 {
+		t.Errorf("Expected configured zero-input response, got %v", output)
+	}
+
+	// Non-zero inputs still reach the backend.
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{
+		ModelName: "m",
+		InputData: []byte(`[2, 2, 2]`),
+	}); err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if backendCalls != 1 {
+		t.Errorf("Expected constant input to reach the backend once, got %d calls", backendCalls)
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var queueDepth = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "inference_queue_depth",
+		Help: "Number of requests waiting for a backend concurrency slot",
+	},
+)
+
+var modelInFlight = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "inference_model_in_flight",
+		Help: "Number of backend calls currently in flight per model",
+	},
+	[]string{"model"},
+)
+
+var backendWaitDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "backend_wait_duration_seconds",
+		Help:    "Time spent waiting for a backend concurrency slot before the backend call starts",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 4, 10),
+	},
+	[]string{"model"},
+)
+
+func init() {
+	prometheus.MustRegister(queueDepth, modelInFlight, backendWaitDuration)
+}
+
+// queueRejectedReason is the ErrorInfo reason of requests the limiter turned
+// away, or that gave up while queued, before they reached the backend.
+const queueRejectedReason = "QUEUE_REJECTED"
+
+// queueRejection returns st as an error marked as a limiter rejection.
+func queueRejection(st *status.Status) error {
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: queueRejectedReason,
+		Domain: serverErrorDomain,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// isQueueRejection reports whether err was built by queueRejection.
+func isQueueRejection(err error) bool {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetDomain() == serverErrorDomain && info.GetReason() == queueRejectedReason
+		}
+	}
+	return false
+}
+
+// limiter bounds the number of concurrent backend calls. Requests beyond the
+// concurrency limit wait for a slot and are served by priority (see
+// requestPriority), in arrival order within a priority. When a queue is
+// configured, at most queueSize requests wait, each for at most queueTimeout,
+// and are rejected with codes.ResourceExhausted if the queue is full or the
+// wait times out. A request arriving at a full queue takes the place of the
+// newest waiter of a lower priority, if there is one, so low-priority
+// requests are shed first. Without a queue, requests wait for a slot until
+// their context is done.
+type limiter struct {
+	mu       sync.Mutex
+	capacity int
+	inUse    int
+	waiting  [numPriorities]list.List // of *waiter
+	queued   int
+
+	queueSize    int
+	queueTimeout time.Duration
+}
+
+// waiter is a request waiting in a limiter's queue.
+type waiter struct {
+	prio priority
+	elem *list.Element
+
+	// ready is closed once the waiter has been handed a slot, or shed for a
+	// higher-priority request, in which case err is set first.
+	ready chan struct{}
+	err   error
+}
+
+// newLimiter returns a limiter allowing maxConcurrency concurrent calls, or
+// nil when maxConcurrency is not positive. A queueSize of zero disables the
+// bounded queue.
+func newLimiter(maxConcurrency, queueSize int, queueTimeout time.Duration) *limiter {
+	if maxConcurrency <= 0 {
+		return nil
+	}
+	return &limiter{
+		capacity:     maxConcurrency,
+		queueSize:    queueSize,
+		queueTimeout: queueTimeout,
+	}
+}
+
+// acquire blocks until a concurrency slot is available and returns a function
+// releasing it. A nil limiter never blocks.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	if l.inUse < l.capacity && l.queued == 0 {
+		l.inUse++
+		l.mu.Unlock()
+		return l.release, nil
+	}
+	prio := requestPriority(ctx)
+	if l.queueSize > 0 && l.queued >= l.queueSize && !l.shedBelow(prio) {
+		l.mu.Unlock()
+		return nil, queueRejection(status.New(codes.ResourceExhausted, "request queue is full"))
+	}
+	w := &waiter{prio: prio, ready: make(chan struct{})}
+	w.elem = l.waiting[prio].PushBack(w)
+	l.queued++
+	queueDepth.Inc()
+	l.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if l.queueSize > 0 && l.queueTimeout > 0 {
+		timer := time.NewTimer(l.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-w.ready:
+		if w.err != nil {
+			return nil, w.err
+		}
+		return l.release, nil
+	case <-timeout:
+		err = queueRejection(status.Newf(codes.ResourceExhausted, "timed out after %v waiting in request queue", l.queueTimeout))
+	case <-ctx.Done():
+		err = queueRejection(status.FromContextError(ctx.Err()))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-w.ready:
+		// Handed a slot while giving up; pass it on.
+		if w.err == nil {
+			l.releaseLocked()
+		}
+	default:
+		l.remove(w)
+	}
+	return nil, err
+}
+
+// release frees a slot, handing it straight to the first waiter of the
+// highest priority if there is one.
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.releaseLocked()
+}
+
+func (l *limiter) releaseLocked() {
+	for p := range l.waiting {
+		if front := l.waiting[p].Front(); front != nil {
+			w := front.Value.(*waiter)
+			l.remove(w)
+			close(w.ready)
+			return
+		}
+	}
+	l.inUse--
+}
+
+// shedBelow rejects the newest waiter of the lowest priority below prio to
+// make room in the queue, and reports whether there was one.
+func (l *limiter) shedBelow(prio priority) bool {
+	for p := numPriorities - 1; p > prio; p-- {
+		if back := l.waiting[p].Back(); back != nil {
+			w := back.Value.(*waiter)
+			l.remove(w)
+			w.err = queueRejection(status.Newf(codes.ResourceExhausted, "%s priority request shed for a %s priority one", w.prio, prio))
+			close(w.ready)
+			return true
+		}
+	}
+	return false
+}
+
+func (l *limiter) remove(w *waiter) {
+	l.waiting[w.prio].Remove(w.elem)
+	l.queued--
+	queueDepth.Dec()
+}
+
+// waiters returns the number of requests waiting for a slot.
+func (l *limiter) waiters() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.queued
+}
+
+// modelLimiters keeps an independent limiter per model so that one saturated
+// model cannot starve the others. Models without an explicit limit share the
+// default limit but still get their own limiter.
+type modelLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*limiter
+
+	defaultLimit int
+	limits       map[string]int
+	queueSize    int
+	queueTimeout time.Duration
+}
+
+// newModelLimiters returns the per-model limiters, or nil when neither a
+// default nor any per-model limit is set.
+func newModelLimiters(defaultLimit int, limits map[string]int, queueSize int, queueTimeout time.Duration) *modelLimiters {
+	if defaultLimit <= 0 && len(limits) == 0 {
+		return nil
+	}
+	return &modelLimiters{
+		limiters:     make(map[string]*limiter),
+		defaultLimit: defaultLimit,
+		limits:       limits,
+		queueSize:    queueSize,
+		queueTimeout: queueTimeout,
+	}
+}
+
+// forModel returns the limiter for model, creating it on first use. It is
+// nil when the model is unlimited.
+func (m *modelLimiters) forModel(model string) *limiter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if l, ok := m.limiters[model]; ok {
+		return l
+	}
+	limit, ok := m.limits[model]
+	if !ok {
+		limit = m.defaultLimit
+	}
+	l := newLimiter(limit, m.queueSize, m.queueTimeout)
+	m.limiters[model] = l
+	return l
+}
+
+// acquire takes a concurrency slot for model and returns a function
+// releasing it. A nil modelLimiters never blocks but still tracks the
+// in-flight gauge and wait time.
+func (m *modelLimiters) acquire(ctx context.Context, model string) (func(), error) {
+	start := time.Now()
+	release := func() {}
+	if m != nil {
+		r, err := m.forModel(model).acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		release = r
+	}
+	backendWaitDuration.WithLabelValues(model).Observe(time.Since(start).Seconds())
+	inFlight := modelInFlight.WithLabelValues(model)
+	inFlight.Inc()
+	return func() {
+		inFlight.Dec()
+		release()
+	}, nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewLimiter_Disabled(t *testing.T) {
+	l := newLimiter(0, 10, time.Second)
+	if l != nil {
+		t.Fatalf("Expected nil limiter when max concurrency is 0")
+	}
+
+	// A nil limiter must never block.
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire on nil limiter returned error: %v", err)
+	}
+	release()
+}
+
+func TestLimiter_RejectsWhenQueueFull(t *testing.T) {
+	// Arrange: one slot and room for one waiter.
+	l := newLimiter(1, 1, time.Second)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+
+	queued := make(chan error, 1)
+	go func() {
+		r, err := l.acquire(context.Background())
+		if err == nil {
+			r()
+		}
+		queued <- err
+	}()
+
+	// Wait until the second request is sitting in the queue.
+	deadline := time.Now().Add(time.Second)
+	for l.waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("second request never entered the queue")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Act: the third request finds the queue full.
+	_, err = l.acquire(context.Background())
+
+	// Assert
+	if status.Code(err) != codes.ResourceExhausted || !isQueueRejection(err) {
+		t.Errorf("Expected a ResourceExhausted queue rejection for full queue, got %v", err)
+	}
+
+	release()
+	if err := <-queued; err != nil {
+		t.Errorf("Expected queued request to acquire once slot freed, got %v", err)
+	}
+}
+
+func TestLimiter_QueueTimeout(t *testing.T) {
+	// Arrange
+	l := newLimiter(1, 4, 20*time.Millisecond)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+	defer release()
+
+	// Act
+	start := time.Now()
+	_, err = l.acquire(context.Background())
+
+	// Assert
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted after queue timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected to wait for the queue timeout, returned after %v", elapsed)
+	}
+	if l.waiters() != 0 {
+		t.Errorf("Expected queue to be empty after timeout, has %d", l.waiters())
+	}
+}
+
+func TestLimiter_UnboundedWaitHonorsContext(t *testing.T) {
+	l := newLimiter(1, 0, 0)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(ctx)
+	if status.Code(err) != codes.DeadlineExceeded || !isQueueRejection(err) {
+		t.Errorf("Expected a DeadlineExceeded queue rejection, got %v", err)
+	}
+}
+
+func TestModelLimiters_IsolatesModels(t *testing.T) {
+	// Arrange: "heavy" gets a single slot, everything else the default.
+	m := newModelLimiters(2, map[string]int{"heavy": 1}, 0, 0)
+
+	release, err := m.acquire(context.Background(), "heavy")
+	if err != nil {
+		t.Fatalf("acquire heavy failed: %v", err)
+	}
+	defer release()
+
+	// Act & Assert: heavy is saturated...
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := m.acquire(ctx, "heavy"); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Expected saturated model to block, got %v", err)
+	}
+
+	// ...while light still gets both of its default slots immediately.
+	for i := range 2 {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		r, err := m.acquire(ctx, "light")
+		cancel()
+		if err != nil {
+			t.Fatalf("acquire light #%d failed: %v", i+1, err)
+		}
+		defer r()
+	}
+}
+
+func TestModelLimiters_InFlightGauge(t *testing.T) {
+	m := newModelLimiters(0, map[string]int{"gauged": 3}, 0, 0)
+	gauge := modelInFlight.WithLabelValues("gauged")
+
+	release, err := m.acquire(context.Background(), "gauged")
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	if got := testutil.ToFloat64(gauge); got != 1 {
+		t.Errorf("Expected 1 in flight, got %v", got)
+	}
+	release()
+	if got := testutil.ToFloat64(gauge); got != 0 {
+		t.Errorf("Expected 0 in flight after release, got %v", got)
+	}
+}
+
+func TestModelLimiters_UnconfiguredModelsUnlimited(t *testing.T) {
+	if m := newModelLimiters(0, nil, 0, 0); m != nil {
+		t.Fatal("Expected nil limiters when nothing is configured")
+	}
+
+	m := newModelLimiters(0, map[string]int{"heavy": 1}, 0, 0)
+	for range 5 {
+		if _, err := m.acquire(context.Background(), "other"); err != nil {
+			t.Fatalf("Expected unconfigured model to be unlimited, got %v", err)
+		}
+	}
+}
+
+func TestModelLimiters_RecordsWaitDuration(t *testing.T) {
+	// Arrange: hold the only slot so the next acquire has to wait.
+	m := newModelLimiters(1, nil, 0, 0)
+	wait := backendWaitDuration.WithLabelValues("waited")
+	before := histogramCount(t, wait)
+
+	release, err := m.acquire(context.Background(), "waited")
+	if err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		release()
+	}()
+
+	// Act
+	r, err := m.acquire(context.Background(), "waited")
+	if err != nil {
+		t.Fatalf("second acquire failed: %v", err)
+	}
+	r()
+
+	// Assert
+	if got := histogramCount(t, wait) - before; got != 2 {
+		t.Errorf("Expected 2 wait observations, got %d", got)
+	}
+	metric := &dto.Metric{}
+	wait.(prometheus.Metric).Write(metric)
+	if sum := metric.GetHistogram().GetSampleSum(); sum < 0.03 {
+		t.Errorf("Expected the blocked acquire to record at least 30ms of waiting, got %.3fs", sum)
+	}
+}
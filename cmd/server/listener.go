@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// addrList is a repeatable flag of listen addresses. Setting it replaces the
+// default rather than appending to it.
+type addrList struct {
+	addrs []string
+	set   bool
+}
+
+func (a *addrList) String() string {
+	if a == nil {
+		return ""
+	}
+	return strings.Join(a.addrs, ",")
+}
+
+func (a *addrList) Set(addr string) error {
+	if !a.set {
+		a.addrs = nil
+		a.set = true
+	}
+	a.addrs = append(a.addrs, addr)
+	return nil
+}
+
+// listenAll opens a TCP listener on each address. Together they accept at
+// most maxConnections open connections. If any address fails, the listeners
+// opened so far are closed.
+func listenAll(addrs []string, maxConnections int) ([]net.Listener, error) {
+	limit := newConnLimit(maxConnections)
+	var listeners []net.Listener
+	for _, addr := range addrs {
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("listening on %s: %w", addr, err)
+		}
+		listeners = append(listeners, limit.wrap(lis))
+	}
+	return listeners, nil
+}
+
+// serveAll serves grpcServer on every listener in the background and
+// returns a channel receiving each Serve result. Stopping grpcServer closes
+// all the listeners together.
+func serveAll(grpcServer *grpc.Server, listeners []net.Listener) <-chan error {
+	errs := make(chan error, len(listeners))
+	for _, lis := range listeners {
+		go func() {
+			log.Printf("gRPC Inference server listening on %s", lis.Addr())
+			errs <- grpcServer.Serve(lis)
+		}()
+	}
+	return errs
+}
+
+// connLimit caps the number of simultaneously open connections across every
+// listener it wraps; once the cap is reached further connections wait in the
+// kernel backlog until one closes. It logs each time the cap is reached.
+type connLimit struct {
+	slots chan struct{}
+}
+
+// newConnLimit returns a limit of max open connections, or nil when max is
+// not positive.
+func newConnLimit(max int) *connLimit {
+	if max <= 0 {
+		return nil
+	}
+	return &connLimit{slots: make(chan struct{}, max)}
+}
+
+// wrap returns lis with its connections counted against c. A nil c returns
+// lis unchanged.
+func (c *connLimit) wrap(lis net.Listener) net.Listener {
+	if c == nil {
+		return lis
+	}
+	return &connLimitListener{Listener: lis, limit: c, done: make(chan struct{})}
+}
+
+// connLimitListener takes a slot of its connLimit before accepting each
+// connection and frees it when the connection closes.
+type connLimitListener struct {
+	net.Listener
+	limit     *connLimit
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *connLimitListener) Accept() (net.Conn, error) {
+	select {
+	case l.limit.slots <- struct{}{}:
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		<-l.limit.slots
+		return nil, err
+	}
+	if max := cap(l.limit.slots); len(l.limit.slots) == max {
+		log.Printf("Connection limit of %d reached; new connections will wait", max)
+	}
+	return &countedConn{Conn: conn, release: func() { <-l.limit.slots }}, nil
+}
+
+// Close closes the listener and wakes an Accept waiting for a slot.
+func (l *connLimitListener) Close() error {
+	err := l.Listener.Close()
+	l.closeOnce.Do(func() { close(l.done) })
+	return err
+}
+
+// countedConn runs release once when the connection is closed.
+type countedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *countedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
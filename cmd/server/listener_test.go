@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestConnLimit_CapsOpenConnections(t *testing.T) {
+	// Arrange
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	lis := newConnLimit(2).wrap(inner)
+	defer lis.Close()
+
+	accepted := make(chan net.Conn, 3)
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	// Act: open one more connection than the limit.
+	for range 3 {
+		conn, err := net.Dial("tcp", inner.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer conn.Close()
+	}
+
+	// Assert: only two are accepted...
+	var open []net.Conn
+	for range 2 {
+		select {
+		case conn := <-accepted:
+			open = append(open, conn)
+		case <-time.After(time.Second):
+			t.Fatal("Expected connection within the limit to be accepted")
+		}
+	}
+	select {
+	case <-accepted:
+		t.Fatal("Expected third connection to wait for a free slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// ...until one of them closes.
+	open[0].Close()
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	case <-time.After(time.Second):
+		t.Fatal("Expected waiting connection to be accepted after a close")
+	}
+	open[1].Close()
+}
+
+func TestConnLimit_Disabled(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer inner.Close()
+	if lis := newConnLimit(0).wrap(inner); lis != inner {
+		t.Error("Expected listener to be returned unchanged when unlimited")
+	}
+}
+
+func TestListenAll_CapsTotalConnections(t *testing.T) {
+	// Arrange: two addresses sharing a limit of two connections.
+	listeners, err := listenAll([]string{"127.0.0.1:0", "127.0.0.1:0"}, 2)
+	if err != nil {
+		t.Fatalf("listenAll failed: %v", err)
+	}
+	accepted := make(chan net.Conn, 4)
+	for _, lis := range listeners {
+		defer lis.Close()
+		go func() {
+			for {
+				conn, err := lis.Accept()
+				if err != nil {
+					return
+				}
+				accepted <- conn
+			}
+		}()
+	}
+
+	// Act: two connections to each address.
+	for _, lis := range listeners {
+		for range 2 {
+			conn, err := net.Dial("tcp", lis.Addr().String())
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+			defer conn.Close()
+		}
+	}
+
+	// Assert: only two are accepted across both addresses.
+	for range 2 {
+		select {
+		case conn := <-accepted:
+			defer conn.Close()
+		case <-time.After(time.Second):
+			t.Fatal("Expected connection within the limit to be accepted")
+		}
+	}
+	select {
+	case conn := <-accepted:
+		conn.Close()
+		t.Fatal("Expected connections over the combined limit to wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestServeAll_ServesEveryAddress(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":[1],"status":"ok"}`))
+	listeners, err := listenAll([]string{"127.0.0.1:0", "127.0.0.1:0"}, 0)
+	if err != nil {
+		t.Fatalf("listenAll failed: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterInferenceServer(grpcServer, s)
+	serveErrs := serveAll(grpcServer, listeners)
+
+	// Act & Assert: both addresses answer RPCs.
+	for _, lis := range listeners {
+		conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("dial %s: %v", lis.Addr(), err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err = pb.NewInferenceClient(conn).Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+		cancel()
+		conn.Close()
+		if err != nil {
+			t.Errorf("Predict via %s failed: %v", lis.Addr(), err)
+		}
+	}
+
+	// Stopping the server closes every listener.
+	grpcServer.GracefulStop()
+	for range listeners {
+		if err := <-serveErrs; err != nil {
+			t.Errorf("Expected Serve to return nil after GracefulStop, got %v", err)
+		}
+	}
+}
+
+func TestListenAll_ClosesOpenedListenersOnFailure(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer taken.Close()
+
+	// Reserve a free port, then ask for it alongside the taken one.
+	probe, _ := net.Listen("tcp", "127.0.0.1:0")
+	free := probe.Addr().String()
+	probe.Close()
+
+	if _, err := listenAll([]string{free, taken.Addr().String()}, 0); err == nil {
+		t.Fatal("Expected an error for an address already in use")
+	}
+	lis, err := net.Listen("tcp", free)
+	if err != nil {
+		t.Fatalf("Expected the first listener to be closed after the failure: %v", err)
+	}
+	lis.Close()
+}
+
+func TestAddrList_ReplacesDefault(t *testing.T) {
+	a := addrList{addrs: []string{":50051"}}
+	a.Set("10.0.0.1:50051")
+	a.Set("[::1]:50051")
+	if got := a.String(); got != "10.0.0.1:50051,[::1]:50051" {
+		t.Errorf("Expected the default to be replaced, got %s", got)
+	}
+}
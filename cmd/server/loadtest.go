@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxLoadTestRequests caps the number of backend calls of one LoadTest.
+const maxLoadTestRequests = 100000
+
+// LoadTest drives Requests backend calls for one model, Concurrency at a
+// time, and reports throughput, latency percentiles and the error rate. The
+// calls take the same path as Predict's backend calls, including the
+// concurrency limiter and retries, but skip the cache and coalescing so
+// every call reaches the backend.
+func (s *server) LoadTest(ctx context.Context, req *pb.LoadTestRequest) (*pb.LoadTestResponse, error) {
+	logger := loggerFromContext(ctx)
+	start := time.Now()
+	method := "LoadTest"
+	statusLabel := "ok"
+	defer func() {
+		s.observeRequestDuration(ctx, method, time.Since(start))
+		requestCount.WithLabelValues(method, statusLabel).Inc()
+	}()
+
+	if !s.loadTestEnabled {
+		statusLabel = "disabled"
+		return nil, status.Errorf(codes.PermissionDenied, "LoadTest is disabled; start the server with -enable-load-test")
+	}
+	if s.draining.Load() {
+		statusLabel = "draining"
+		return nil, status.Errorf(codes.Unavailable, "server draining")
+	}
+
+	model := req.GetModelName()
+	requests := int64(req.GetRequests())
+	concurrency := int64(req.GetConcurrency())
+	switch {
+	case model == "":
+		statusLabel = "bad-input"
+		return nil, status.Errorf(codes.InvalidArgument, "model_name cannot be empty")
+	case requests <= 0 || requests > maxLoadTestRequests:
+		statusLabel = "bad-input"
+		return nil, status.Errorf(codes.InvalidArgument, "requests must be between 1 and %d, got %d", maxLoadTestRequests, requests)
+	case concurrency <= 0:
+		statusLabel = "bad-input"
+		return nil, status.Errorf(codes.InvalidArgument, "concurrency must be positive, got %d", concurrency)
+	}
+
+	if err := s.checkInputSize(int64(len(req.GetInputData()))); err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+	var inputArray []float64
+	if err := json.Unmarshal(req.GetInputData(), &inputArray); err != nil {
+		statusLabel = "bad-input"
+		return nil, status.Errorf(codes.InvalidArgument, "input_data must be a JSON array of numbers")
+	}
+	if len(inputArray) == 0 {
+		statusLabel = "empty-input"
+		return nil, status.Errorf(codes.InvalidArgument, "input data cannot be empty")
+	}
+	// Inputs Predict would reject are rejected here too, before any
+	// backend call.
+	if err := s.checkModelInput(model, req.GetInputData(), inputArray); err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+	inputArray, err := s.config.preprocess(model, inputArray)
+	if err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+	input := &InputData{ModelName: model, Input: inputArray}
+
+	logger.Printf("Starting load test of model %s: %d requests, concurrency %d", model, requests, concurrency)
+	var (
+		mu        sync.Mutex
+		latencies = make([]time.Duration, 0, requests)
+		failures  atomic.Int64
+		next      atomic.Int64
+		wg        sync.WaitGroup
+	)
+	for range min(concurrency, requests) {
+		wg.Go(func() {
+			for next.Add(1) <= requests && ctx.Err() == nil {
+				callStart := time.Now()
+				if _, err := s.sendDataToAPI(ctx, input, make(http.Header)); err != nil {
+					failures.Add(1)
+					continue
+				}
+				elapsed := time.Since(callStart)
+				mu.Lock()
+				latencies = append(latencies, elapsed)
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	if err := ctx.Err(); err != nil {
+		statusLabel = "canceled"
+		return nil, status.FromContextError(err).Err()
+	}
+
+	slices.Sort(latencies)
+	failed := failures.Load()
+	logger.Printf("Load test of model %s finished in %v: %d requests, %d failed", model, elapsed, requests, failed)
+	return &pb.LoadTestResponse{
+		Requests:      int32(requests),
+		Errors:        int32(failed),
+		ErrorRate:     float64(failed) / float64(requests),
+		ThroughputRps: float64(requests) / elapsed.Seconds(),
+		DurationMs:    durationMs(elapsed),
+		LatencyP50Ms:  durationMs(percentile(latencies, 0.50)),
+		LatencyP95Ms:  durationMs(percentile(latencies, 0.95)),
+		LatencyP99Ms:  durationMs(percentile(latencies, 0.99)),
+	}, nil
+}
+
+// durationMs converts d to fractional milliseconds.
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
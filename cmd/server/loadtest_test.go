@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoadTest_AggregatesBackendCalls(t *testing.T) {
+	// Arrange: every fourth call fails, and calls overlap long enough for
+	// the limiter to matter.
+	var calls, inFlight, maxInFlight atomic.Int64
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if n <= peak || maxInFlight.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if calls.Add(1)%4 == 0 {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		staticBackend(`{"status":"ok","output":[1]}`)(w, r)
+	})
+	s.loadTestEnabled = true
+	s.limiters = newModelLimiters(2, nil, 0, time.Second)
+	req := &pb.LoadTestRequest{ModelName: "m", InputData: []byte(`[1,2]`), Concurrency: 8, Requests: 40}
+
+	// Act
+	resp, err := s.LoadTest(context.Background(), req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("LoadTest failed: %v", err)
+	}
+	if calls.Load() != 40 || resp.GetRequests() != 40 {
+		t.Errorf("Expected 40 backend calls, got %d (reported %d)", calls.Load(), resp.GetRequests())
+	}
+	if resp.GetErrors() != 10 || resp.GetErrorRate() != 0.25 {
+		t.Errorf("Expected 10 errors (rate 0.25), got %d (rate %v)", resp.GetErrors(), resp.GetErrorRate())
+	}
+	if maxInFlight.Load() > 2 {
+		t.Errorf("Expected the concurrency limiter to cap in-flight calls at 2, saw %d", maxInFlight.Load())
+	}
+	if resp.GetThroughputRps() <= 0 || resp.GetLatencyP50Ms() < 5 {
+		t.Errorf("Expected positive throughput and latencies of at least 5ms, got %v rps, p50 %vms", resp.GetThroughputRps(), resp.GetLatencyP50Ms())
+	}
+	if !(resp.GetLatencyP50Ms() <= resp.GetLatencyP95Ms() && resp.GetLatencyP95Ms() <= resp.GetLatencyP99Ms()) {
+		t.Errorf("Expected ordered percentiles, got p50 %v, p95 %v, p99 %v", resp.GetLatencyP50Ms(), resp.GetLatencyP95Ms(), resp.GetLatencyP99Ms())
+	}
+}
+
+func TestLoadTest_RejectsRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		req      *pb.LoadTestRequest
+		wantCode codes.Code
+	}{
+		{"disabled", false, &pb.LoadTestRequest{ModelName: "m", InputData: []byte(`[1]`), Concurrency: 1, Requests: 1}, codes.PermissionDenied},
+		{"no model", true, &pb.LoadTestRequest{InputData: []byte(`[1]`), Concurrency: 1, Requests: 1}, codes.InvalidArgument},
+		{"no requests", true, &pb.LoadTestRequest{ModelName: "m", InputData: []byte(`[1]`), Concurrency: 1}, codes.InvalidArgument},
+		{"too many requests", true, &pb.LoadTestRequest{ModelName: "m", InputData: []byte(`[1]`), Concurrency: 1, Requests: maxLoadTestRequests + 1}, codes.InvalidArgument},
+		{"no concurrency", true, &pb.LoadTestRequest{ModelName: "m", InputData: []byte(`[1]`), Requests: 1}, codes.InvalidArgument},
+		{"bad input", true, &pb.LoadTestRequest{ModelName: "m", InputData: []byte(`{}`), Concurrency: 1, Requests: 1}, codes.InvalidArgument},
+		{"input too large", true, &pb.LoadTestRequest{ModelName: "m", InputData: []byte(`[1, 2, 3, 4, 5, 6]`), Concurrency: 1, Requests: 1}, codes.InvalidArgument},
+		{"wrong feature count", true, &pb.LoadTestRequest{ModelName: "named", InputData: []byte(`[1]`), Concurrency: 1, Requests: 1}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+			s.loadTestEnabled = tt.enabled
+			s.maxInputBytes = 16
+			cfg, err := loadConfig(writeConfig(t, "models:\n  named:\n    feature_names: [x, y]\n"))
+			if err != nil {
+				t.Fatalf("loadConfig returned error: %v", err)
+			}
+			s.config = cfg
+
+			_, err = s.LoadTest(context.Background(), tt.req)
+
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if called {
+				t.Error("Expected no backend calls")
+			}
+		})
+	}
+}
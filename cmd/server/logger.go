@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	requestIDHeader = "x-request-id"
+	tenantHeader    = "x-tenant-id"
+	// maxRequestIDLen bounds the request ID, which is copied into every log
+	// line and the response header.
+	maxRequestIDLen = 128
+)
+
+type loggerKey struct{}
+
+// discardLogger is returned when no logger was attached to the context.
+var discardLogger = log.New(io.Discard, "", 0)
+
+// withLogger returns a copy of ctx carrying logger.
+func withLogger(ctx context.Context, logger *log.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// loggerFromContext returns the request-scoped logger attached by
+// loggingInterceptor, or a logger that discards everything.
+func loggerFromContext(ctx context.Context) *log.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*log.Logger); ok {
+		return logger
+	}
+	return discardLogger
+}
+
+// loggingInterceptor attaches a logger to each request's context whose lines
+// are tagged with the request ID, model, tenant and, when the client sent
+// one, its correlation ID. The request ID is taken from the x-request-id
+// metadata when present, generated otherwise, and echoed back in the
+// response header. A supplied request ID that is too long or is not
+// printable ASCII fails the call with INVALID_ARGUMENT.
+func loggingInterceptor(out io.Writer, flags int) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		requestID := firstValue(md, requestIDHeader)
+		if err := checkClientID("request ID", requestID, maxRequestIDLen); err != nil {
+			return nil, err
+		}
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+
+		var model string
+		if r, ok := req.(interface{ GetModelName() string }); ok {
+			model = r.GetModelName()
+		}
+		prefix := fmt.Sprintf("request_id=%s model=%q tenant=%q ", requestID, model, firstValue(md, tenantHeader))
+		// An invalid correlation ID is left out here; Predict rejects it.
+		if id, err := requestCorrelationID(ctx, req); err == nil && id != "" {
+			prefix += fmt.Sprintf("correlation_id=%q ", id)
+		}
+		logger := log.New(out, prefix, flags|log.Lmsgprefix)
+
+		return handler(withLogger(ctx, logger), req)
+	}
+}
+
+// firstValue returns the first value of key in md, or "".
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
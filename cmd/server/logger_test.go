@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoggingInterceptor_TagsEveryLine(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"model_name":"sample","output":[1],"status":"ok"}`))
+	var buf bytes.Buffer
+	interceptor := loggingInterceptor(&buf, 0)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		requestIDHeader, "req-123",
+		tenantHeader, "acme",
+	))
+	info := &grpc.UnaryServerInfo{FullMethod: pb.Inference_Predict_FullMethodName}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.Predict(ctx, req.(*pb.PredictRequest))
+	}
+
+	// Act
+	_, err := interceptor(ctx, &pb.PredictRequest{ModelName: "sample", InputData: []byte(`[1, 2]`)}, info, handler)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("Expected several log lines, got:\n%s", buf.String())
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, `request_id=req-123 model="sample" tenant="acme" `) {
+			t.Errorf("Expected line to be tagged with request context, got %q", line)
+		}
+	}
+}
+
+func TestLoggingInterceptor_GeneratesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	interceptor := loggingInterceptor(&buf, 0)
+	handler := func(ctx context.Context, req any) (any, error) {
+		loggerFromContext(ctx).Printf("hello")
+		return nil, nil
+	}
+
+	interceptor(context.Background(), &pb.PredictRequest{}, &grpc.UnaryServerInfo{}, handler)
+
+	line := buf.String()
+	if !strings.HasPrefix(line, "request_id=") || strings.HasPrefix(line, "request_id= ") {
+		t.Errorf("Expected a generated request ID, got %q", line)
+	}
+}
+
+func TestLoggingInterceptor_RejectsInvalidRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"too long", strings.Repeat("x", maxRequestIDLen+1)},
+		{"newline", "req-1\nrequest_id=forged"},
+		{"non-ASCII", "requête-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var buf bytes.Buffer
+			interceptor := loggingInterceptor(&buf, 0)
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestIDHeader, tt.id))
+			handler := func(ctx context.Context, req any) (any, error) {
+				t.Error("handler should not be called")
+				return nil, nil
+			}
+
+			// Act
+			_, err := interceptor(ctx, &pb.PredictRequest{}, &grpc.UnaryServerInfo{}, handler)
+
+			// Assert
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("Expected nothing to be logged, got %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestLoggerFromContext_Fallback(t *testing.T) {
+	if logger := loggerFromContext(context.Background()); logger != discardLogger {
+		t.Error("Expected the discard logger when none is attached")
+	}
+}
@@ -4,33 +4,269 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
 	"io"
 	"log"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
-	"strings"
 	"time"
-	"fmt"
+
+	"github.com/arhantsg07/ml-inference-system/internal/quantize"
+	"github.com/arhantsg07/ml-inference-system/internal/recording"
 	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
-	port = flag.String("port", ":50051", "Server port, include ':' e.g. :50051")
+	ports             = addrList{addrs: []string{":50051"}}
+	maxConnections    = flag.Int("max-connections", 0, "Maximum number of simultaneous gRPC client connections in total, across all listen addresses (0 means unlimited)")
+	preStopGrace      = flag.Duration("prestop-grace", 15*time.Second, "How long GET /preStop keeps the server not ready before returning, for load balancers to deregister it")
+	adminToken        = flag.String("admin-token", os.Getenv("ADMIN_TOKEN"), "Bearer token required by the admin endpoints (defaults to $ADMIN_TOKEN; empty disables them)")
+	verbose           = flag.Bool("verbose", true, "Log request and response bodies for every request")
+	logSampleRate     = flag.Float64("log-sample-rate", 0, "Fraction of requests (0-1) whose bodies are logged when -verbose=false")
+	logBodyMaxBytes   = flag.Int("log-body-max-bytes", 4096, "Truncate logged request and response bodies to this many bytes (0 means unlimited)")
+	configPath        = flag.String("config", "", "Path to an optional YAML config file")
+	maxConcurrency    = flag.Int("max-concurrency", 0, "Maximum number of concurrent backend calls per model, unless overridden in the config file (0 means unlimited)")
+	queueSize         = flag.Int("queue-size", 0, "Number of requests allowed to wait for a backend slot when -max-concurrency is reached (0 waits without bound)")
+	queueTimeout      = flag.Duration("queue-timeout", time.Second, "Maximum time a request waits in the queue before being rejected")
+	sloWindow         = flag.Int("slo-window", 1000, "Number of recent requests per model used for inference_slo_success_ratio (0 disables the ratio)")
+	shedWindow        = flag.Int("shed-window", 0, "Number of recent backend latencies used for deadline-aware load shedding (0 disables shedding)")
+	shedMinSamples    = flag.Int("shed-min-samples", 20, "Minimum latency samples required before requests are shed")
+	shedFactor        = flag.Float64("shed-latency-factor", 1.0, "Shed requests whose remaining deadline is below this multiple of the p99 backend latency")
+	backendMethodFlag = flag.String("backend-method", http.MethodPost, "HTTP method of backend prediction calls: POST, PUT or PATCH")
+	backendRetries    = flag.Int("backend-retries", 0, "Number of times to retry a backend call that failed with a transient error")
+	retryBackoff      = flag.Duration("backend-retry-backoff", 100*time.Millisecond, "Initial delay between backend retries; doubles each attempt unless the backend sends Retry-After")
+	traceExemplars    = flag.Bool("trace-exemplars", false, "Attach the trace ID from a sampled W3C traceparent to request duration observations as Prometheus exemplars, and serve /metrics in the OpenMetrics format when scrapers ask for it")
+	traceConns        = flag.Bool("backend-connection-metrics", false, "Export DNS, TCP connect and TLS handshake durations of new backend connections (adds slight overhead per backend call)")
+	defaultDeadline   = flag.Duration("default-request-deadline", 30*time.Second, "Deadline given to unary calls that arrive without one (0 leaves them unbounded)")
+	backendTimeout    = flag.Duration("backend-timeout", 10*time.Second, "Timeout for each backend call, unless overridden per model in the config file (0 means no timeout)")
+	idleTimeout       = flag.Duration("backend-idle-timeout", 0, "Fail a backend call whose response body sends no data for this long; -backend-timeout then only bounds the wait for headers (0 disables it)")
+	maxInputBytes     = flag.Int64("max-input-bytes", 64<<20, "Maximum size of a request's input in bytes, including streamed inputs (0 means unlimited)")
+	maxSendBytes      = flag.Int("grpc-max-send-bytes", 4<<20, "Maximum size of a gRPC response in bytes; larger outputs fail with RESOURCE_EXHAUSTED (0 uses the gRPC default)")
+	maxOutputBytes    = flag.Int64("max-output-bytes", 16<<20, "Maximum size of a backend response body in bytes (0 means unlimited)")
+	sniffGzip         = flag.Bool("backend-sniff-gzip", false, "Decompress backend response bodies that start with the gzip magic bytes even without a Content-Encoding header, a workaround for misconfigured backends")
+	httpReadTimeout   = flag.Duration("http-read-timeout", 5*time.Second, "Maximum time for the metrics HTTP server to read a request, including headers")
+	httpWriteTimeout  = flag.Duration("http-write-timeout", 10*time.Second, "Maximum time for the metrics HTTP server to write a response")
+	grpcConnTimeout   = flag.Duration("grpc-connection-timeout", 20*time.Second, "Maximum time for a new gRPC connection to complete its handshake")
+	tlsCert           = flag.String("tls-cert", "", "PEM certificate file; with -tls-key, serves gRPC over TLS")
+	tlsKey            = flag.String("tls-key", "", "PEM private key file for -tls-cert")
+	tlsMinVersion     = flag.String("tls-min-version", "1.2", "Minimum TLS version accepted with -tls-cert: 1.0, 1.1, 1.2 or 1.3")
+	tlsCiphers        = flag.String("tls-cipher-suites", "", "Comma-separated TLS 1.2 cipher suites allowed with -tls-cert, by Go name (empty uses Go's secure defaults)")
+	tlsWait           = flag.Duration("tls-wait", 30*time.Second, "How long to wait at startup for the TLS certificate and key files to appear")
+	enableLoadTest    = flag.Bool("enable-load-test", false, "Serve the LoadTest RPC, which lets callers send many backend calls through this server")
+	grpcWeb           = flag.Bool("grpc-web", false, "Serve gRPC-Web on the metrics HTTP port so browser clients can call the Inference service")
+	grpcWebOrigins    = flag.String("grpc-web-origins", "", "Comma-separated origins allowed to make cross-origin gRPC-Web calls (\"*\" allows any; empty allows only same-origin calls)")
+	grpcCompression   = flag.String("grpc-compression", "", "Compress gRPC responses with this compressor (gzip) when the client accepts it (empty disables it)")
+	compressMinBytes  = flag.Int("grpc-compression-min-bytes", 1024, "Responses smaller than this many bytes are sent uncompressed with -grpc-compression")
+	grpcStats         = flag.Bool("grpc-stats", false, "Export per-method gRPC payload bytes and open connection metrics")
+	debugRecent       = flag.Int("debug-recent", 100, "Number of recent requests kept in memory and served at /debug/recent (0 disables it)")
+	debugRedact       = flag.Bool("debug-redact-input", false, "Show only the size of inputs at /debug/recent")
+	recordPath        = flag.String("record-path", "", "Append each request/response pair as a JSON line to this file for later replay")
+	recordBuffer      = flag.Int("record-buffer", 1000, "Number of records buffered before new records are dropped")
+	backendCredsTTL   = flag.Duration("backend-credentials-ttl", 30*time.Second, "How long backend URL and token read from the environment or token file are cached")
+	probeInterval     = flag.Duration("backend-probe-interval", 0, "Interval between background backend health probes used for readiness (0 disables probing)")
+	failOnEmptyOutput = flag.Bool("fail-on-empty-output", false, "Fail with INTERNAL when the backend reports success but returns an empty output")
+	signingSecret     = flag.String("backend-signing-secret", os.Getenv("BACKEND_SIGNING_SECRET"), "Shared secret used to sign backend requests with an HMAC-SHA256 of the body (defaults to $BACKEND_SIGNING_SECRET; empty disables signing)")
+	signatureHeader   = flag.String("backend-signature-header", defaultSignatureHeader, "Header carrying the backend request signature")
+	dedupWindow       = flag.Duration("dedup-window", time.Second, "Count Predict requests identical to one received within this window in inference_duplicate_requests_total (0 disables tracking)")
+	rejectDuplicates  = flag.Bool("reject-duplicates", false, "Reject Predict requests identical to one received within -dedup-window with ALREADY_EXISTS instead of only counting them")
+	coalesce          = flag.Bool("coalesce-requests", false, "Share one backend call between identical requests (same model and input) in flight at the same time")
+	cacheBackend      = flag.String("cache-backend", "", "Prediction cache: memory or redis (disabled when empty)")
+	cacheSize         = flag.Int("cache-size", 10000, "Maximum number of entries in the memory cache")
+	cacheTTL          = flag.Duration("cache-ttl", time.Minute, "How long cached predictions are served (0 keeps them until evicted)")
+	cacheRedisAddr    = flag.String("cache-redis-addr", "localhost:6379", "Redis address used by -cache-backend=redis")
+	tcpKeepAlive      = flag.Duration("backend-tcp-keepalive", 30*time.Second, "Interval between TCP keepalive probes on idle backend connections (negative disables them)")
+	trailerHeaders    = flag.String("backend-trailer-headers", "", "Comma-separated backend response headers returned to Predict clients as gRPC trailers, e.g. model-instance-id")
+	backendHTTP2      = flag.Bool("backend-http2", false, "Call the backend over HTTP/2 only, using cleartext h2c for http:// URLs, so concurrent calls share one connection")
+	noKeepAlives      = flag.Bool("backend-disable-keepalives", false, "Open a new backend connection for every call instead of reusing pooled ones (a debugging aid for connection setup problems)")
+	warmPoolSize      = flag.Int("backend-warm-pool", 0, "Number of backend connections opened at startup, before the server reports ready, so a burst of first requests doesn't wait for connection setup (0 disables it)")
+	keepaliveInterval = flag.Duration("backend-keepalive-interval", 0, "Interval between lightweight backend pings that keep pooled connections warm (0 disables them)")
+	deadLetterPath    = flag.String("dead-letter-path", "", "Append requests whose backend call failed after all retries to this file")
+	deadLetterURL     = flag.String("dead-letter-url", "", "POST requests whose backend call failed after all retries to this URL as JSON lines")
+	deadLetterBuffer  = flag.Int("dead-letter-buffer", 1000, "Number of dead letters buffered before new ones are dropped")
+	allowDefaultInput = flag.Bool("allow-default-input", false, "Serve requests with an empty input using -default-input and -default-model instead of rejecting them (for demos)")
+	defaultInput      = flag.String("default-input", "", "JSON array used as the input of empty requests when -allow-default-input is set")
+	defaultModel      = flag.String("default-model", "", "Model used for requests without a model name when -allow-default-input is set")
+	goroutineInterval = flag.Duration("goroutine-sample-interval", 10*time.Second, "Interval between goroutine count samples exported as inference_goroutines (0 disables sampling)")
+	goroutineWarnAt   = flag.Int("goroutine-warn-threshold", 10000, "Log a warning when the sampled goroutine count rises above this (0 disables the warning)")
+	alertErrorRate    = flag.Float64("alert-error-rate", 0, "Backend error rate (0-1) that raises an alert when exceeded for -alert-for (0 disables the evaluator)")
+	alertWindow       = flag.Duration("alert-window", time.Minute, "Sliding window over which the backend error rate is computed")
+	alertFor          = flag.Duration("alert-for", 2*time.Minute, "How long the error rate must stay above -alert-error-rate before alerting")
+	alertWebhook      = flag.String("alert-webhook", "", "URL that receives a JSON POST when the error rate alert fires (empty only logs the alert)")
+	alertCooldown     = flag.Duration("alert-cooldown", 15*time.Minute, "Minimum time between two alerts while the error rate stays high")
+	chaosFaultList    = flag.String("chaos", "", "Comma-separated synthetic failures to inject into Predict for resilience testing: unavailable, latency, truncate (empty disables; never use in production)")
+	chaosRate         = flag.Float64("chaos-rate", 0.1, "Fraction of Predict calls (0-1] that get a -chaos failure")
+	chaosLatency      = flag.Duration("chaos-latency", time.Second, "Delay added by the -chaos latency failure")
+	canarySeed        = flag.Uint64("canary-seed", 0, "Seed for the RNG that picks canary requests, for reproducible routing (0 picks a random seed)")
+	zeroInputResponse = flag.String("zero-input-response", "", "JSON array returned without calling the backend when the input is all zeros (disabled when empty)")
 )
 
 // server implements the Inference gRPC service.
 type server struct {
 	pb.UnimplementedInferenceServer
 	httpClient *http.Client
+
+	// backend resolves the backend URL and token for each call; nil reads
+	// them from the environment every time.
+	backend backendProvider
+
+	// signer signs backend request bodies; nil sends them unsigned.
+	signer *requestSigner
+
+	// deadLetters receives requests whose backend call failed after all
+	// retries; nil disables it.
+	deadLetters *deadLetterSink
+
+	// coalescer shares one backend call between identical concurrent
+	// requests; nil disables coalescing.
+	coalescer *requestCoalescer
+
+	// duplicates counts, and optionally rejects, exact resubmits of recent
+	// Predict requests; nil disables it.
+	duplicates *duplicateDetector
+
+	// errorAlert alerts on a sustained backend error rate; nil disables it.
+	errorAlert *errorRateAlert
+
+	// chaos injects synthetic failures into Predict; nil disables it.
+	chaos *chaosInjector
+
+	// async tracks goroutines that outlive their request; shutdown waits
+	// for them.
+	async asyncTasks
+
+	// cache holds backend responses keyed by model and input; nil disables
+	// caching.
+	cache *predictionCache
+
+	// config holds the structured settings from the -config file.
+	config Config
+
+	// canary splits traffic between stable and canary backends for models
+	// with a canary config; nil sends everything to the stable backend.
+	canary *canaryRouter
+
+	// serving is true while the gRPC server is accepting RPCs.
+	serving atomic.Bool
+
+	// warming fails readiness while the backend warm pool is being opened.
+	warming atomic.Bool
+
+	// draining is set through the admin endpoints to reject new requests
+	// without shutting down.
+	draining atomic.Bool
+
+	// stopping is set by the preStop hook to fail readiness while requests
+	// are still served, so load balancers can deregister the server before
+	// it shuts down. preStopGrace is how long the hook waits.
+	stopping     atomic.Bool
+	preStopGrace time.Duration
+
+	// verbose logs request and response bodies for every request; otherwise
+	// they are logged for a logSampleRate fraction of requests. It can be
+	// switched at runtime through the /loglevel admin endpoint.
+	verbose       atomic.Bool
+	logSampleRate float64
+
+	// logBodyMaxBytes truncates logged bodies to this many bytes; 0 means
+	// unlimited.
+	logBodyMaxBytes int
+
+	// adminToken authorizes the admin endpoints; empty disables them.
+	adminToken string
+
+	// limiters bound concurrent backend calls per model; nil means
+	// unlimited.
+	limiters *modelLimiters
+
+	// backendRetries is the number of times a retryable backend failure is
+	// retried, waiting retryBackoff (doubling each attempt) or the backend's
+	// Retry-After in between.
+	backendRetries int
+	retryBackoff   time.Duration
+
+	// backendMethod is the HTTP method of backend calls; empty means POST.
+	backendMethod string
+
+	// backendTimeout bounds each backend call unless the model overrides
+	// it in the config; 0 means no timeout.
+	backendTimeout time.Duration
+
+	// interceptors are the unary interceptors the gRPC server chains.
+	// PredictLargeInput runs its reassembled request through them too.
+	interceptors []grpc.UnaryServerInterceptor
+
+	// trailerHeaders lists the backend response headers that Predict
+	// returns to the client as trailers.
+	trailerHeaders []string
+
+	// backendIdleTimeout fails a backend call whose response body stops
+	// arriving for this long; 0 disables it. When set, backendTimeout only
+	// bounds the wait for the response headers.
+	backendIdleTimeout time.Duration
+
+	// loadTestEnabled serves the LoadTest RPC; it is rejected otherwise.
+	loadTestEnabled bool
+
+	// traceExemplars attaches the trace ID of sampled requests to their
+	// requestDuration observations as exemplars.
+	traceExemplars bool
+
+	// traceConnections records DNS, connect and TLS handshake durations of
+	// new backend connections.
+	traceConnections bool
+
+	// maxOutputBytes caps the size of a backend response body; 0 means
+	// unlimited.
+	maxOutputBytes int64
+
+	// sniffGzip decompresses backend response bodies that start with the
+	// gzip magic bytes but come without a Content-Encoding header.
+	sniffGzip bool
+
+	// maxInputBytes caps the size of a request's input, including inputs
+	// reassembled from PredictLargeInput chunks; 0 means unlimited.
+	maxInputBytes int64
+
+	// maxSendBytes is the gRPC send limit; larger responses fail with a
+	// clear error instead of a transport failure. 0 means unlimited.
+	maxSendBytes int
+
+	// shedder rejects requests that cannot finish before their deadline;
+	// nil disables load shedding.
+	shedder *loadShedder
+
+	// slo tracks the sliding-window latency SLO success ratio of models
+	// with a latency objective; nil exports only the request counter.
+	slo *sloTracker
+
+	// prober checks the backend in the background for readiness; nil
+	// disables probing.
+	prober *backendProber
+
+	// failOnEmptyOutput rejects successful backend responses with an empty
+	// output instead of forwarding them.
+	failOnEmptyOutput bool
+
+	// defaultInput and defaultModel stand in for an empty input and model
+	// name when allowDefaultInput is set, for demos and smoke tests.
+	allowDefaultInput bool
+	defaultInput      []byte
+	defaultModel      string
+
+	// zeroInputResponse, when non-nil, is returned for all-zero inputs
+	// instead of calling the backend.
+	zeroInputResponse []float64
 }
 
 var (
@@ -49,36 +285,184 @@ var (
 		},
 		[]string{"method"},
 	)
+	serializationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "serialization_duration_seconds",
+			Help:    "Time spent in encoding/json on the Predict path (seconds)",
+			Buckets: prometheus.ExponentialBuckets(0.00001, 4, 10),
+		},
+		[]string{"operation"},
+	)
+	outputInputRatio = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "inference_output_input_ratio",
+			Help:    "Number of output values per input value of successful Predict calls; a sudden shift can mean a model swap or bug",
+			Buckets: prometheus.ExponentialBuckets(1.0/64, 2, 14),
+		},
+		[]string{"model"},
+	)
 )
 
 func init() {
-	prometheus.MustRegister(requestCount, requestDuration)
+	prometheus.MustRegister(requestCount, requestDuration, serializationDuration, outputInputRatio)
+}
+
+// timeSerialization runs fn and records its duration under operation.
+func timeSerialization(operation string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	serializationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	return err
 }
 
 type InputData struct {
-	ModelName string    `json:"model_name"`
-	Input     []float64 `json:"input"`
+	ModelName string          `json:"model_name"`
+	Input     []float64       `json:"input"`
+	Shape     []int64         `json:"shape,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+}
+
+// NamedInputData is the backend request for models with feature names: the
+// input values keyed by name.
+type NamedInputData struct {
+	ModelName string             `json:"model_name"`
+	Input     map[string]float64 `json:"input"`
+	Params    json.RawMessage    `json:"params,omitempty"`
 }
 
 type APIResponse struct {
 	ModelName string    `json:"model_name"`
 	Output    []float64 `json:"output"`
 	Status    string    `json:"status"`
+	Version   string    `json:"version,omitempty"`
+
+	// Outputs holds the output heads of multi-head models, which send
+	// "output" as an object of head name to array. Output is nil then.
+	Outputs map[string][]float64 `json:"outputs,omitempty"`
+
+	// Shapes holds the shape of each entry in Outputs for multi-tensor
+	// models, which send "outputs" as an array of named tensors.
+	Shapes map[string][]int64 `json:"shapes,omitempty"`
+
+	// Confidence is the backend's optional "confidence", sent as a number
+	// or an array of numbers. A number becomes a one-element slice.
+	Confidence []float64 `json:"confidence,omitempty"`
+
+	// Forwarded holds the response fields listed in the model's
+	// forward_fields config, as sent by the backend. callBackend picks them
+	// from the body; the "forwarded" key only matters for cached entries.
+	Forwarded map[string]json.RawMessage `json:"forwarded,omitempty"`
+
+	// Timings is the backend's Server-Timing breakdown in milliseconds; it
+	// comes from the response headers, not the body.
+	Timings map[string]float64 `json:"-"`
+
+	// Headers holds the backend response headers listed in
+	// -backend-trailer-headers, returned to the client as trailers. They
+	// describe the call that produced the response, so they are not cached.
+	Headers http.Header `json:"-"`
 }
 
-func (s *server) sendDataToAPI(ctx context.Context, inputData *InputData) (*APIResponse, error) {
-	baseURL := os.Getenv("MODEL_SERVER_URL")
-	if baseURL == "" {
-		baseURL = "http://localhost:8080"
+// UnmarshalJSON accepts "output" as either an array, for single-output
+// models, or an object of arrays keyed by head name, for multi-head models,
+// and "confidence" as either a number or an array. Multi-tensor models
+// instead send "outputs" as an array of named tensors with shapes, which
+// fill Outputs and Shapes. A missing or null "output" leaves both Output and
+// Outputs nil, while an empty array leaves Output empty but non-nil, so
+// callers can tell the two apart.
+func (r *APIResponse) UnmarshalJSON(data []byte) error {
+	type plain APIResponse
+	aux := struct {
+		*plain
+		Output     json.RawMessage `json:"output"`
+		Outputs    json.RawMessage `json:"outputs"`
+		Confidence json.RawMessage `json:"confidence"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if confidence := bytes.TrimSpace(aux.Confidence); len(confidence) > 0 && confidence[0] == '[' {
+		if err := json.Unmarshal(confidence, &r.Confidence); err != nil {
+			return fmt.Errorf("confidence must be a number or an array of numbers: %w", err)
+		}
+	} else if len(confidence) > 0 && string(confidence) != "null" {
+		var v float64
+		if err := json.Unmarshal(confidence, &v); err != nil {
+			return fmt.Errorf("confidence must be a number or an array of numbers: %w", err)
+		}
+		r.Confidence = []float64{v}
+	}
+	output := bytes.TrimSpace(aux.Output)
+	outputs := bytes.TrimSpace(aux.Outputs)
+	if len(outputs) > 0 && outputs[0] == '[' {
+		if len(output) > 0 && string(output) != "null" {
+			return fmt.Errorf("output and a tensor outputs array cannot both be set")
+		}
+		return r.setTensors(outputs)
+	}
+	if len(outputs) > 0 {
+		// Cached multi-head responses hold their heads in "outputs".
+		if err := json.Unmarshal(outputs, &r.Outputs); err != nil {
+			return err
+		}
+	}
+	if len(output) > 0 && output[0] == '{' {
+		return json.Unmarshal(output, &r.Outputs)
+	}
+	if len(output) > 0 {
+		return json.Unmarshal(output, &r.Output)
 	}
-	apiURL := fmt.Sprintf("%s/predict", strings.TrimRight(baseURL, "/"))
+	return nil
+}
+
+// isMultiHead reports whether the backend returned named output heads or
+// tensors.
+func (r *APIResponse) isMultiHead() bool {
+	return r.Outputs != nil
+}
 
-	requestBody := InputData{
+// outputLen returns the number of output values, summed over the heads of
+// multi-head models.
+func (r *APIResponse) outputLen() int {
+	n := len(r.Output)
+	for _, head := range r.Outputs {
+		n += len(head)
+	}
+	return n
+}
+
+// sendDataToAPI posts the input to the backend variant the canary router
+// picks, retrying transient failures. header holds extra per-request headers
+// for the backend call.
+func (s *server) sendDataToAPI(ctx context.Context, inputData *InputData, header http.Header) (*APIResponse, error) {
+	variant, canaryURL := s.canary.route(&s.config, inputData.ModelName)
+	return s.sendToVariant(ctx, inputData, header, variant, canaryURL)
+}
+
+// sendToVariant is sendDataToAPI for an already chosen variant; canaryURL is
+// the backend URL for the canary and "" for the stable backend.
+func (s *server) sendToVariant(ctx context.Context, inputData *InputData, header http.Header, variant, canaryURL string) (apiResponse *APIResponse, err error) {
+	logger := loggerFromContext(ctx)
+
+	var requestBody any = InputData{
 		ModelName: inputData.ModelName,
 		Input:     inputData.Input,
+		Shape:     inputData.Shape,
+		Params:    inputData.Params,
+	}
+	named, err := s.config.namedInput(inputData.ModelName, inputData.Input)
+	if err != nil {
+		return nil, err
+	}
+	if named != nil {
+		requestBody = NamedInputData{ModelName: inputData.ModelName, Input: named, Params: inputData.Params}
 	}
 
-	jsonData, err := json.Marshal(requestBody)
+	var jsonData []byte
+	err = timeSerialization("marshal_backend", func() (err error) {
+		jsonData, err = json.Marshal(requestBody)
+		return err
+	})
 	if err != nil {
 		return nil, status.Errorf(
 			codes.Internal,
@@ -86,16 +470,62 @@ func (s *server) sendDataToAPI(ctx context.Context, inputData *InputData) (*APIR
 		)
 	}
 
-	// logging (trim long bodies in production)
-	log.Printf("Sending request to %s", apiURL)
-	if len(jsonData) < 4096 {
-		log.Printf("Request body: %s", string(jsonData))
-	} else {
-		log.Printf("Request body too large to print (%d bytes)", len(jsonData))
+	if bodyLoggingEnabled(ctx) {
+		logger.Printf("Request body: %s", s.bodyForLog(jsonData))
+	}
+
+	// The variant is fixed for the call so retries go to the same backend.
+	logger.Printf("Routing model %s to %s backend", inputData.ModelName, variant)
+	backendStart := time.Now()
+	defer func() {
+		backendDuration.WithLabelValues(inputData.ModelName, variant).Observe(time.Since(backendStart).Seconds())
+		backendRequests.WithLabelValues(inputData.ModelName, variant, status.Code(err).String()).Inc()
+		s.errorAlert.record(isBackendFailure(err))
+	}()
+
+	for attempt := 0; ; attempt++ {
+		// Resolve the target on every attempt so a rotated token is picked
+		// up without a restart.
+		var target backendTarget
+		target, err = s.resolveBackend(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "resolving backend: %v", err)
+		}
+		if canaryURL != "" {
+			target.URL = canaryURL
+		}
+		apiResponse, err = s.callBackend(ctx, inputData.ModelName, target, jsonData, header)
+		if err == nil || attempt >= s.backendRetries || !isRetryable(err) {
+			return apiResponse, err
+		}
+
+		delay := retryDelay(err, s.retryBackoff, attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			logger.Printf("Not retrying: backoff %v exceeds remaining deadline", delay)
+			return nil, err
+		}
+		logger.Printf("Backend attempt %d/%d failed, retrying in %v: %v", attempt+1, s.backendRetries+1, delay, err)
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return nil, err
+		}
 	}
+}
 
-	// sending the http post req with context from gRPC
-	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(jsonData))
+// callBackend sends jsonData to the backend in a single request using
+// s.backendMethod and decodes the response.
+func (s *server) callBackend(ctx context.Context, model string, target backendTarget, jsonData []byte, header http.Header) (*APIResponse, error) {
+	logger := loggerFromContext(ctx)
+	apiURL, err := backendEndpoint(target.URL, "predict")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid backend URL %s: %v", redactURL(target.URL), err)
+	}
+	logger.Printf("Sending request to %s", redactURL(apiURL))
+	method := s.backendMethod
+	if method == "" {
+		method = http.MethodPost
+	}
+	// sending the http req with context from gRPC
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, status.Errorf(
 			codes.InvalidArgument,
@@ -103,153 +533,712 @@ func (s *server) sendDataToAPI(ctx context.Context, inputData *InputData) (*APIR
 		)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", contentTypeJSON)
+	}
+	if target.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+target.Token)
+	}
+	s.signer.sign(req, jsonData)
+
+	release, err := s.limiters.acquire(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-	resp, err := s.httpClient.Do(req)
+	// The backend timeout starts once a slot is acquired so queueing time
+	// doesn't eat into it.
+	callCtx, cancelCall := context.WithCancelCause(req.Context())
+	defer cancelCall(nil)
+	var headerTimer *time.Timer
+	if timeout := s.timeoutFor(model); timeout > 0 && s.backendIdleTimeout > 0 {
+		// With an idle timeout the backend timeout only bounds the wait for
+		// the response headers; the body may take as long as it keeps
+		// arriving.
+		headerTimer = time.AfterFunc(timeout, func() {
+			cancelCall(fmt.Errorf("no response headers within %v: %w", timeout, context.DeadlineExceeded))
+		})
+		defer headerTimer.Stop()
+	} else if timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(callCtx, timeout)
+		defer cancel()
+	}
+	if s.traceConnections {
+		callCtx = withConnectionTrace(callCtx)
+	}
+	req = req.WithContext(callCtx)
+
+	backendStart := time.Now()
+	resp, err := s.backendClient().Do(req)
+	// Failed calls are recorded too: timeouts and stalls are exactly the
+	// latencies the shedder needs to see.
+	defer func() { s.shedder.observe(time.Since(backendStart)) }()
 	if err != nil {
+		if cause := context.Cause(callCtx); headerTimer != nil && errors.Is(cause, context.DeadlineExceeded) {
+			err = cause
+		}
 		return nil, status.Errorf(
 			codes.Unavailable,
 			"Failed to reach external API: %v", err,
 		)
 	}
 	defer resp.Body.Close()
+	if headerTimer != nil {
+		headerTimer.Stop()
+	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Read response body, reading one byte past the limit to detect overflow
+	var bodyReader io.Reader = resp.Body
+	if s.backendIdleTimeout > 0 {
+		idle := newIdleReader(bodyReader, s.backendIdleTimeout, cancelCall)
+		defer idle.stop()
+		bodyReader = idle
+	}
+	if s.maxOutputBytes > 0 {
+		bodyReader = io.LimitReader(bodyReader, s.maxOutputBytes+1)
+	}
+	body, err := io.ReadAll(bodyReader)
 	if err != nil {
+		if cause := context.Cause(callCtx); errors.Is(cause, errBackendStalled) {
+			return nil, status.Errorf(
+				codes.DeadlineExceeded,
+				"%v after %d bytes of the response (-backend-idle-timeout)", cause, len(body),
+			)
+		}
+		if isConnectionClosed(err) {
+			return nil, status.Errorf(
+				codes.Unavailable,
+				"backend connection closed before full response (read %d bytes): %v", len(body), err,
+			)
+		}
 		return nil, status.Errorf(
 			codes.Unavailable,
 			"failed to read response from external API: %v", err,
 		)
 	}
+	if s.maxOutputBytes > 0 && int64(len(body)) > s.maxOutputBytes {
+		return nil, status.Errorf(
+			codes.Internal,
+			"backend response exceeds limit of %d bytes", s.maxOutputBytes,
+		)
+	}
+	// Binary outputs are skipped: their first float can match the gzip
+	// magic bytes.
+	if s.sniffGzip && resp.Header.Get("Content-Encoding") == "" && !isBinaryResponse(resp.Header) {
+		decompressed, sniffed, err := sniffGzipBody(body, s.maxOutputBytes)
+		if err != nil {
+			return nil, err
+		}
+		if sniffed {
+			logger.Printf("Decompressed a gzip response body sent without Content-Encoding")
+			body = decompressed
+		}
+	}
 
-	log.Printf("API Response Status: %d", resp.StatusCode)
-	if len(body) < 4096 {
-		log.Printf("API Response Body: %s", string(body))
-	} else {
-		log.Printf("API response body too large to print (%d bytes)", len(body))
+	logger.Printf("API Response Status: %d", resp.StatusCode)
+	if bodyLoggingEnabled(ctx) {
+		if isBinaryResponse(resp.Header) {
+			logger.Printf("API Response Body: %d bytes of binary output", len(body))
+		} else {
+			logger.Printf("API Response Body: %s", s.bodyForLog(body))
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		// Rate limiting is transient: surface it as ResourceExhausted and
+		// pass the backend's Retry-After on to the client.
+		return nil, rateLimitedError(resp.Header.Get("Retry-After"), body)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		// Map 4xx to InvalidArgument, 5xx to Internal/Unavailable
 		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-			return nil, status.Errorf(codes.InvalidArgument, "API returned status %d: %s", resp.StatusCode, string(body))
+			return nil, backendClientError(resp.StatusCode, body)
 		}
 		return nil, status.Errorf(codes.Internal, "API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
+	if isBinaryResponse(resp.Header) {
+		var apiResponse *APIResponse
+		if err := timeSerialization("unmarshal_backend", func() (err error) {
+			apiResponse, err = decodeBinaryResponse(model, resp.Header, body)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		apiResponse.Timings = parseServerTiming(resp.Header.Values("Server-Timing"))
+		apiResponse.Headers = pickHeaders(s.trailerHeaders, resp.Header)
+		return apiResponse, nil
+	}
+
+	if err := s.config.checkSuccess(body); err != nil {
+		return nil, err
+	}
+
 	var apiResponse APIResponse
-	if err := json.Unmarshal(body, &apiResponse); err != nil {
+	if err := timeSerialization("unmarshal_backend", func() error {
+		return json.Unmarshal(body, &apiResponse)
+	}); err != nil {
 		return nil, status.Errorf(
 			codes.Internal,
 			"Failed to parse external API response: %v", err,
 		)
 	}
+	if apiResponse.Output == nil && apiResponse.Outputs == nil {
+		return nil, status.Errorf(codes.Internal, "backend response missing output field")
+	}
 
+	apiResponse.Forwarded, err = s.config.forwardedFields(model, body)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal,
+			"Failed to parse external API response: %v", err,
+		)
+	}
+
+	apiResponse.Timings = parseServerTiming(resp.Header.Values("Server-Timing"))
+	apiResponse.Headers = pickHeaders(s.trailerHeaders, resp.Header)
 	return &apiResponse, nil
 
 }
 
 // Predict takes the input data and then calls the sendDataToAPI function.
 func (s *server) Predict(ctx context.Context, req *pb.PredictRequest) (*pb.PredictResponse, error) {
+	logger := loggerFromContext(ctx)
 	start := time.Now()
 	method := "Predict"
 	var statusLabel string = "ok"
 	defer func() {
-		requestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		s.observeRequestDuration(ctx, method, time.Since(start))
 		requestCount.WithLabelValues(method, statusLabel).Inc()
+		s.observeSLO(req.GetModelName(), statusLabel, time.Since(start))
 	}()
 
+	if s.draining.Load() {
+		statusLabel = "draining"
+		return nil, status.Errorf(codes.Unavailable, "server draining")
+	}
+
+	req = s.applyDefaults(req)
+	requestedModel := req.GetModelName()
+	if model := s.config.resolveAlias(requestedModel); model != requestedModel {
+		req = proto.Clone(req).(*pb.PredictRequest)
+		req.ModelName = model
+	}
+
+	correlationID, err := requestCorrelationID(ctx, req)
+	if err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+
+	if err := s.checkInputSize(int64(len(req.GetInputData()))); err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+
 	var inputArray []float64
 
-	if err := json.Unmarshal(req.GetInputData(), &inputArray); err != nil {
-		log.Printf("failed to unmarshal input: %v", err)
+	if err := timeSerialization("unmarshal_input", func() error {
+		return json.Unmarshal(req.GetInputData(), &inputArray)
+	}); err != nil {
+		logger.Printf("failed to unmarshal input: %v", err)
 		statusLabel = "bad-input"
 
-		return nil, status.Errorf(
+		return nil, localize(ctx, status.Errorf(
 			codes.InvalidArgument,
 			"input_data must be a JSON array of numbers",
-		)
+		), msgMalformedInput)
 	}
 
 	if len(inputArray) == 0 {
 		statusLabel = "empty-input"
-		return nil, status.Errorf(
+		return nil, localize(ctx, status.Errorf(
 			codes.InvalidArgument, "input data cannot be empty",
-		)
+		), msgEmptyInput)
 	}
 
-	log.Printf("Parsed input array: %v", inputArray)
+	if err := s.config.checkFeatureCount(req.GetModelName(), len(inputArray)); err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+	if err := s.config.checkFeatureTypes(req.GetModelName(), inputArray); err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+	if shape := req.GetInputShape(); len(shape) > 0 {
+		if err := s.config.checkInputShape(req.GetModelName(), shape, len(inputArray)); err != nil {
+			statusLabel = "bad-input"
+			return nil, err
+		}
+	}
+	if err := s.config.validateInput(req.GetModelName(), req.GetInputData()); err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
 
-	// referring to the above struct
-	input_data := &InputData{
-		ModelName: req.GetModelName(),
-		Input:     inputArray,
+	logBodies := s.shouldLogBodies()
+	ctx = withBodyLogging(ctx, logBodies)
+	if logBodies {
+		if req.GetModelName() != requestedModel {
+			logger.Printf("Resolved model alias %s to %s", requestedModel, req.GetModelName())
+		}
+		logger.Printf("Parsed input array: %v", inputArray)
 	}
 
-	apiResponse, err := s.sendDataToAPI(ctx, input_data)
+	if err := validatePostProcess(req.GetPostProcess(), req.GetTopK()); err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+	if q := req.GetQuantization(); q != "" && !quantize.Valid(q) {
+		statusLabel = "bad-input"
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported quantization %q (want %s or %s)", q, quantize.Float16, quantize.Int8)
+	}
+	format, err := outputFormat(ctx, req)
 	if err != nil {
-		log.Printf("Error sending to external API: %v", err)
-		statusLabel = "api-error"
-		return nil, status.Errorf(
-			codes.Unavailable,
-			"failed to call external API: %v", err,
-		)
+		statusLabel = "bad-input"
+		return nil, err
+	}
+	if err := validatePrecision(req); err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+	if err := validateParams(req.GetParams()); err != nil {
+		statusLabel = "bad-input"
+		return nil, err
+	}
+	if err := s.duplicates.check(req, pinnedVersion(ctx)); err != nil {
+		statusLabel = "duplicate"
+		return nil, err
 	}
 
-	log.Printf("Successfully sent data to external API")
-	log.Printf("Successfully processed the prediction request")
+	fault, err := s.chaos.inject(ctx)
+	if err != nil {
+		statusLabel = "chaos"
+		return nil, err
+	}
 
-	log.Printf("Model: %s, Output: %v, Status: %s",
-		apiResponse.ModelName, apiResponse.Output, apiResponse.Status)
+	pinned := pinnedVersion(ctx)
+	// Parameters and the input shape change the output, so they are part of
+	// the key shared by the cache and the coalescer.
+	hash := requestHash(req.GetModelName(), pinned, inputArray, req.GetInputShape(), req.GetParams())
+	accept := acceptFor(req.GetQuantization(), format)
+	// The variant is chosen up front so that canary and stable responses
+	// are cached and coalesced separately.
+	variant, canaryURL := s.canary.route(&s.config, req.GetModelName())
+	key := cacheKey(hash, accept, variant)
 
-	// converting the response to match the gRPC format
-	// throw err, if failed marshalling
-	outputBytes, err := json.Marshal(apiResponse.Output)
-	if err != nil {
-		statusLabel = "internal-error"
-		return nil, status.Errorf(
-			codes.Internal,
-			"failed to marshal output: %v", err,
-		)
+	kind := classifyInput(inputArray)
+	if kind != inputVaried {
+		degenerateInputs.WithLabelValues(kind.String()).Inc()
+		logger.Printf("Received %s input for model %s", kind, req.GetModelName())
+	}
+
+	// Pinned requests always go to the backend so the served version can be
+	// checked.
+	var apiResponse *APIResponse
+	if kind == inputAllZero && s.zeroInputResponse != nil && pinned == "" {
+		statusLabel = "zero-input"
+		apiResponse = &APIResponse{
+			ModelName: req.GetModelName(),
+			Output:    s.zeroInputResponse,
+			Status:    "ok",
+		}
+	} else if cached := s.cache.get(ctx, key); cached != nil {
+		logger.Printf("Serving cached response")
+		apiResponse = cached
+	} else {
+		if err := s.shedder.admit(ctx); err != nil {
+			statusLabel = "shed"
+			return nil, err
+		}
+
+		backendInput, err := s.config.preprocess(req.GetModelName(), inputArray)
+		if err != nil {
+			statusLabel = "bad-input"
+			return nil, err
+		}
+
+		// referring to the above struct
+		input_data := &InputData{
+			ModelName: req.GetModelName(),
+			Input:     backendInput,
+			Shape:     req.GetInputShape(),
+			Params:    req.GetParams(),
+		}
+
+		header := make(http.Header)
+		header.Set("Accept", accept)
+		if pinned != "" {
+			header.Set(backendModelVersionHeader, pinned)
+		}
+		if correlationID != "" {
+			header.Set(backendCorrelationIDHeader, correlationID)
+		}
+
+		apiResponse, err = s.coalescer.do(ctx, key, func(ctx context.Context) (*APIResponse, error) {
+			return s.sendToVariant(ctx, input_data, header, variant, canaryURL)
+		})
+		if err != nil {
+			logger.Printf("Error sending to external API: %v", err)
+			if isDeadLetter(ctx, err) {
+				s.deadLetters.write(req, err)
+			}
+			statusLabel = "api-error"
+			if status.Code(err) == codes.ResourceExhausted {
+				statusLabel = "rejected"
+			}
+			err = wrapStatus(err, "failed to call external API")
+			if status.Code(err) == codes.Unavailable {
+				err = localize(ctx, err, msgBackendUnavailable)
+			}
+			return nil, err
+		}
+
+		logger.Printf("Successfully sent data to external API")
+		if len(apiResponse.Headers) > 0 {
+			// Set before the output checks so that rejected outputs can be
+			// traced to the backend instance too.
+			grpc.SetTrailer(ctx, headerTrailer(apiResponse.Headers))
+		}
+
+		if s.failOnEmptyOutput && len(apiResponse.Output) == 0 && len(apiResponse.Outputs) == 0 && s.config.isSuccess(apiResponse.Status) {
+			logger.Printf("Backend returned empty output with status %q", apiResponse.Status)
+			statusLabel = "empty-output"
+			return nil, status.Errorf(codes.Internal, "backend returned empty output")
+		}
+		// output_length describes a single output array, so multi-head
+		// responses skip the check.
+		if err := s.config.checkOutputLength(req.GetModelName(), apiResponse.Output); err != nil && !apiResponse.isMultiHead() {
+			logger.Printf("Unexpected output shape: %v", err)
+			statusLabel = "bad-output"
+			return nil, err
+		}
+		if err := s.config.validateOutput(req.GetModelName(), apiResponse); err != nil {
+			logger.Printf("Output failed schema validation: %v", err)
+			statusLabel = "bad-output"
+			return nil, err
+		}
+		// Checked before caching so that a mismatched response is never
+		// served to later requests pinned to the same version.
+		if err := checkPinnedVersion(pinned, apiResponse.Version); err != nil {
+			logger.Printf("Model version mismatch: %v", err)
+			statusLabel = "version-mismatch"
+			return nil, err
+		}
+		s.cache.set(ctx, key, apiResponse)
+	}
+
+	logger.Printf("Successfully processed the prediction request")
+
+	if logBodies && apiResponse.isMultiHead() {
+		logger.Printf("Model: %s, Version: %s, Outputs: %v, Status: %s",
+			apiResponse.ModelName, apiResponse.Version, apiResponse.Outputs, apiResponse.Status)
+	} else if logBodies {
+		logger.Printf("Model: %s, Version: %s, Output: %v, Status: %s",
+			apiResponse.ModelName, apiResponse.Version, apiResponse.Output, apiResponse.Status)
+	} else {
+		logger.Printf("Model: %s, Version: %s, Status: %s",
+			apiResponse.ModelName, apiResponse.Version, apiResponse.Status)
+	}
+
+	// Empty inputs were rejected above, so the ratio is always defined.
+	outputInputRatio.WithLabelValues(req.GetModelName()).Observe(float64(apiResponse.outputLen()) / float64(len(inputArray)))
+
+	resp := &pb.PredictResponse{
+		ModelName:        requestedModel,
+		CorrelationId:    correlationID,
+		RequestHash:      hash,
+		Status:           s.config.normalizeStatus(apiResponse.Status),
+		ModelVersion:     apiResponse.Version,
+		BackendTimingsMs: apiResponse.Timings,
+		Confidence:       apiResponse.Confidence,
+	}
+	for field, value := range apiResponse.Forwarded {
+		if resp.Metadata == nil {
+			resp.Metadata = make(map[string][]byte, len(apiResponse.Forwarded))
+		}
+		resp.Metadata[field] = value
+	}
+	if apiResponse.isMultiHead() {
+		heads := apiResponse.Outputs
+		if fault == faultTruncate {
+			heads = truncateOutputs(heads)
+		}
+		if err := s.multiHeadOutputs(req, format, heads, resp); err != nil {
+			statusLabel = "bad-input"
+			if status.Code(err) == codes.Internal {
+				statusLabel = "internal-error"
+			}
+			return nil, err
+		}
+		resp.OutputShapes = tensorShapes(apiResponse.Shapes)
+		if err := s.checkResponseSize(resp); err != nil {
+			statusLabel = "output-too-large"
+			return nil, err
+		}
+		return resp, nil
+	}
+	output := apiResponse.Output
+	if fault == faultTruncate {
+		output = output[:len(output)/2]
+	}
+	if req.GetPostProcess() == postProcessSoftmax {
+		output = softmax(output)
 	}
-	return &pb.PredictResponse{
-		OutputData: outputBytes,
-		Status:     apiResponse.Status,
-	}, nil
+	if req.Precision != nil {
+		output = roundOutput(output, req.GetPrecision())
+	}
+
+	if k := int(req.GetTopK()); k > 0 {
+		indices, probs, err := topK(output, k)
+		if err != nil {
+			statusLabel = "bad-input"
+			return nil, err
+		}
+		resp.TopKIndices = indices
+		resp.TopKProbabilities = probs
+		return resp, nil
+	}
+
+	switch req.GetQuantization() {
+	case quantize.Float16:
+		resp.OutputEncoding = quantize.Float16
+		resp.OutputData = quantize.EncodeFloat16(output)
+	case quantize.Int8:
+		resp.OutputEncoding = quantize.Int8
+		resp.OutputData, resp.QuantScale, resp.QuantZeroPoint = quantize.EncodeInt8(output)
+	default:
+		// converting the response to match the gRPC format
+		// throw err, if failed marshalling
+		encode := outputEncoder(format)
+		if format != outputFormatJSON {
+			resp.OutputEncoding = format
+		}
+		var outputBytes []byte
+		err := timeSerialization("marshal_output", func() (err error) {
+			outputBytes, err = encode(output)
+			return err
+		})
+		if err != nil {
+			statusLabel = "internal-error"
+			return nil, status.Errorf(
+				codes.Internal,
+				"failed to marshal output: %v", err,
+			)
+		}
+		resp.OutputData = outputBytes
+	}
+
+	if err := s.checkResponseSize(resp); err != nil {
+		statusLabel = "output-too-large"
+		return nil, err
+	}
+	return resp, nil
 }
 
 func main() {
+	flag.Var(&ports, "port", "Server address, include ':' e.g. :50051; repeat to listen on several addresses")
 	flag.Parse()
 
-	lis, err := net.Listen("tcp", *port)
+	if *logSampleRate < 0 || *logSampleRate > 1 {
+		log.Fatalf("-log-sample-rate must be between 0 and 1, got %v", *logSampleRate)
+	}
+	if *logBodyMaxBytes < 0 {
+		log.Fatalf("-log-body-max-bytes must not be negative, got %d", *logBodyMaxBytes)
+	}
+
+	listeners, err := listenAll(ports.addrs, *maxConnections)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	httpClient := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	httpClient := newBackendClient(backendDialer(*tcpKeepAlive), *noKeepAlives, *backendHTTP2, *warmPoolSize)
 
-	grpcServer := grpc.NewServer()
-	pb.RegisterInferenceServer(grpcServer, &server{
+	srv := &server{
 		httpClient: httpClient,
-	})
+		backend:    newEnvBackend(*backendCredsTTL),
+		signer:     newRequestSigner(*signingSecret, *signatureHeader),
+		canary:     newCanaryRouter(*canarySeed),
+		shedder:    newLoadShedder(*shedWindow, *shedMinSamples, *shedFactor),
+		duplicates: newDuplicateDetector(*dedupWindow, *rejectDuplicates),
+		slo:        newSLOTracker(*sloWindow),
+
+		backendRetries: *backendRetries,
+		retryBackoff:   *retryBackoff,
+		backendTimeout: *backendTimeout,
+		maxOutputBytes: *maxOutputBytes,
+		maxInputBytes:  *maxInputBytes,
+		maxSendBytes:   *maxSendBytes,
+		preStopGrace:   *preStopGrace,
+		adminToken:     *adminToken,
+		logSampleRate:  *logSampleRate,
+
+		failOnEmptyOutput: *failOnEmptyOutput,
+		logBodyMaxBytes:   *logBodyMaxBytes,
+		traceConnections:  *traceConns,
+		traceExemplars:    *traceExemplars,
+		sniffGzip:         *sniffGzip,
+
+		backendIdleTimeout: *idleTimeout,
+		trailerHeaders:     parseTrailerHeaders(*trailerHeaders),
+		loadTestEnabled:    *enableLoadTest,
+	}
+	srv.verbose.Store(*verbose)
+	srv.backendMethod, err = parseBackendMethod(*backendMethodFlag)
+	if err != nil {
+		log.Fatalf("invalid -backend-method: %v", err)
+	}
+	if *configPath != "" {
+		cfg, err := loadConfig(*configPath)
+		if err != nil {
+			log.Fatalf("failed to load config: %v", err)
+		}
+		srv.config = cfg
+	}
+	store, err := newCache(*cacheBackend, *cacheSize, *cacheRedisAddr)
+	if err != nil {
+		log.Fatalf("invalid cache settings: %v", err)
+	}
+	srv.cache = newPredictionCache(store, *cacheTTL)
+	if *coalesce {
+		srv.coalescer = &requestCoalescer{tasks: &srv.async}
+	}
+	srv.deadLetters, err = newDeadLetterSink(*deadLetterPath, *deadLetterURL, *deadLetterBuffer, deadLetterPostTimeout)
+	if err != nil {
+		log.Fatalf("invalid dead-letter settings: %v", err)
+	}
+	srv.limiters = newModelLimiters(*maxConcurrency, srv.config.concurrencyLimits(), *queueSize, *queueTimeout)
+	if *allowDefaultInput {
+		var parsed []float64
+		if err := json.Unmarshal([]byte(*defaultInput), &parsed); err != nil || len(parsed) == 0 {
+			log.Fatalf("-allow-default-input requires -default-input to be a non-empty JSON array of numbers")
+		}
+		srv.allowDefaultInput = true
+		srv.defaultInput = []byte(*defaultInput)
+		srv.defaultModel = *defaultModel
+	}
+	if *zeroInputResponse != "" {
+		if err := json.Unmarshal([]byte(*zeroInputResponse), &srv.zeroInputResponse); err != nil {
+			log.Fatalf("invalid -zero-input-response: %v", err)
+		}
+	}
+
+	srv.chaos, err = newChaosInjector(*chaosFaultList, *chaosRate, *chaosLatency, 0)
+	if err != nil {
+		log.Fatalf("invalid chaos settings: %v", err)
+	}
+	if srv.chaos != nil {
+		log.Printf("WARNING: CHAOS MODE ENABLED. Injecting %s into %.0f%% of Predict calls. Never use this in production.", *chaosFaultList, *chaosRate*100)
+	}
 
-	// Start HTTP server for /metrics and /health
+	srv.errorAlert, err = newErrorRateAlert(*alertErrorRate, *alertWindow, *alertFor, *alertCooldown, *alertWebhook)
+	if err != nil {
+		log.Fatalf("invalid alert settings: %v", err)
+	}
+	if srv.errorAlert != nil {
+		srv.errorAlert.tasks = &srv.async
+	}
+
+	srv.prober = newBackendProber(*probeInterval, srv.checkBackend)
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go srv.prober.run(backgroundCtx)
+	go srv.errorAlert.run(backgroundCtx, time.Second)
+	go srv.keepWarm(backgroundCtx, *keepaliveInterval)
+	go newGoroutineMonitor(*goroutineWarnAt, log.Default()).run(backgroundCtx, *goroutineInterval)
+
+	interceptors := []grpc.UnaryServerInterceptor{
+		loggingInterceptor(log.Writer(), log.Flags()),
+		deadlineInterceptor(*defaultDeadline),
+		priorityInterceptor(),
+	}
+	recent := newRecentRequests(*debugRecent, *debugRedact)
+	if recent != nil {
+		interceptors = append(interceptors, recentInterceptor(recent))
+	}
+
+	var recorder *recording.Writer
+	if *recordPath != "" {
+		f, err := os.OpenFile(*recordPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatalf("failed to open record file: %v", err)
+		}
+		defer f.Close()
+		recorder = recording.NewWriter(f, *recordBuffer)
+		interceptors = append(interceptors, recordingInterceptor(recorder))
+		log.Printf("Recording requests to %s", *recordPath)
+	}
+
+	if *grpcCompression != "" {
+		compress, err := compressionInterceptor(*grpcCompression, *compressMinBytes)
+		if err != nil {
+			log.Fatalf("invalid compression settings: %v", err)
+		}
+		interceptors = append(interceptors, compress)
+	}
+
+	srv.interceptors = interceptors
+	serverOpts := []grpc.ServerOption{
+		grpc.ConnectionTimeout(*grpcConnTimeout),
+		grpc.ChainUnaryInterceptor(interceptors...),
+	}
+	if *maxSendBytes > 0 {
+		serverOpts = append(serverOpts, grpc.MaxSendMsgSize(*maxSendBytes))
+	}
+	if *grpcStats {
+		serverOpts = append(serverOpts, grpc.StatsHandler(transportStats{}))
+	}
+	// TLS settings are checked even without -tls-cert so typos surface.
+	tlsConfig, err := newTLSConfig(*tlsMinVersion, *tlsCiphers)
+	if err != nil {
+		log.Fatalf("invalid TLS settings: %v", err)
+	}
+	if *tlsCert != "" || *tlsKey != "" {
+		creds, err := loadTLSCredentials(*tlsCert, *tlsKey, *tlsWait, tlsConfig)
+		if err != nil {
+			log.Fatalf("failed to load TLS credentials: %v", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+		log.Printf("Serving gRPC over TLS")
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
+	pb.RegisterInferenceServer(grpcServer, srv)
+
+	// Start HTTP server for /metrics, health checks and admin endpoints
 	httpMux := http.NewServeMux()
-	httpMux.Handle("/metrics", promhttp.Handler())
-	httpMux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("ok"))
-	})
+	metricsHandler := promhttp.Handler()
+	if *traceExemplars {
+		// Exemplars are only exposed in the OpenMetrics format.
+		metricsHandler = promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+			promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+	}
+	httpMux.Handle("/metrics", metricsHandler)
+	httpMux.HandleFunc("/health", srv.healthHandler)
+	httpMux.HandleFunc("/ready", srv.readyHandler)
+	httpMux.HandleFunc("GET /preStop", srv.preStopHandler)
+	if recent != nil {
+		httpMux.HandleFunc("GET /debug/recent", recent.recentHandler)
+	}
+	httpMux.HandleFunc("/drain", srv.requireAdmin(srv.drainHandler))
+	httpMux.HandleFunc("/undrain", srv.requireAdmin(srv.undrainHandler))
+	httpMux.HandleFunc("/loglevel", srv.requireAdmin(srv.logLevelHandler))
+	httpMux.HandleFunc("/config", srv.requireAdminMethod(http.MethodGet, srv.configHandler(flag.CommandLine)))
 
-	httpSrv := &http.Server{
-		Addr:    ":9090",
-		Handler: httpMux,
+	var httpHandler http.Handler = httpMux
+	if *grpcWeb {
+		httpHandler = grpcWebHandler(grpcServer, parseOrigins(*grpcWebOrigins), httpMux)
+		log.Printf("Serving gRPC-Web on the HTTP port for origins %q", *grpcWebOrigins)
 	}
 
+	httpSrv := newHTTPServer(":9090", httpHandler, *httpReadTimeout, *httpWriteTimeout)
+
 	// Run HTTP server in background
 	go func() {
 		log.Printf("HTTP metrics server listening on %s", httpSrv.Addr)
@@ -258,19 +1247,35 @@ func main() {
 		}
 	}()
 
-	// Run gRPC server in background
+	if *warmPoolSize > 0 {
+		srv.warming.Store(true)
+		go srv.warmConnections(backgroundCtx, *warmPoolSize, warmUpTimeout)
+	}
+
+	// Run gRPC server in background on every listen address
+	srv.serving.Store(true)
+	serveErrs := serveAll(grpcServer, listeners)
 	go func() {
-		log.Printf("gRPC Inference server listening on %s", *port)
-		if err := grpcServer.Serve(lis); err != nil {
+		err := <-serveErrs
+		srv.serving.Store(false)
+		if err != nil {
 			log.Fatalf("failed to serve gRPC: %v", err)
 		}
 	}()
 
 	// Handle graceful shutdown
-	stop := make(chan os.Signal, 1)						// makes a memory allocation for receiving signal
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)  // registers the interest in the signals interrupt, sigterm
-	<-stop												// waits for the signal
+	stop := make(chan os.Signal, 1)                    // makes a memory allocation for receiving signal
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM) // registers the interest in the signals interrupt, sigterm
+	<-stop                                             // waits for the signal
 	log.Printf("Shutting down servers...")
+	stopForcing := forceOnSecondSignal(stop, func(sig os.Signal) {
+		log.Printf("Received %v during shutdown; forcing immediate exit", sig)
+		grpcServer.Stop()
+		os.Exit(1)
+	})
+	defer stopForcing()
+	srv.serving.Store(false)
+	stopBackground()
 
 	// Shutdown HTTP server with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -294,5 +1299,29 @@ func main() {
 		grpcServer.Stop()
 	}
 
+	// Flush the sinks alongside any detached backend calls, which are done
+	// or abandoned together once the shutdown timeout passes.
+	if recorder != nil {
+		srv.async.Go(func() {
+			if err := recorder.Close(); err != nil {
+				log.Printf("Failed to flush recording: %v", err)
+			}
+			if n := recorder.Dropped(); n > 0 {
+				log.Printf("Dropped %d records because the recording buffer was full", n)
+			}
+		})
+	}
+	srv.async.Go(func() {
+		if err := srv.deadLetters.Close(); err != nil {
+			log.Printf("Failed to flush dead letters: %v", err)
+		}
+	})
+	log.Printf("Waiting for %d async tasks to finish", srv.async.pending.Load())
+	asyncCtx, cancelAsync := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelAsync()
+	if n := srv.async.wait(asyncCtx); n > 0 {
+		log.Printf("%d async tasks did not finish in time; their writes may be lost", n)
+	}
+
 	log.Printf("Shutdown complete")
 }
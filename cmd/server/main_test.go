@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestServer starts a fake model backend serving handler and returns a
+// server wired to it.
+func newTestServer(t *testing.T, handler http.HandlerFunc) *server {
+	t.Helper()
+	backend := httptest.NewServer(handler)
+	t.Cleanup(backend.Close)
+	t.Setenv("MODEL_SERVER_URL", backend.URL)
+	return &server{httpClient: backend.Client()}
+}
+
+// staticBackend returns a handler that always replies with body.
+func staticBackend(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}
+}
+
+// histogramCount returns the number of observations recorded by h.
+func histogramCount(t *testing.T, h prometheus.Observer) uint64 {
+	t.Helper()
+	m := &dto.Metric{}
+	if err := h.(prometheus.Metric).Write(m); err != nil {
+		t.Fatalf("failed to read histogram: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestPredict_Success(t *testing.T) {
+	// Arrange
+	var got InputData
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/predict" {
+			t.Errorf("Expected request to /predict, got %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"model_name":"sample","output":[0.1,0.9],"status":"ok"}`))
+	})
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{
+		ModelName: "sample",
+		InputData: []byte(`[1, 2, 3]`),
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if got.ModelName != "sample" || len(got.Input) != 3 {
+		t.Errorf("Backend received unexpected request: %+v", got)
+	}
+	if string(resp.OutputData) != "[0.1,0.9]" {
+		t.Errorf("Expected output [0.1,0.9], got %s", resp.OutputData)
+	}
+	if resp.Status != "ok" {
+		t.Errorf("Expected status ok, got %q", resp.Status)
+	}
+}
+
+func TestPredict_RecordsSerializationDuration(t *testing.T) {
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":[1,2],"status":"ok"}`))
+
+	operations := []string{"unmarshal_input", "marshal_backend", "unmarshal_backend", "marshal_output"}
+	before := make(map[string]uint64)
+	for _, op := range operations {
+		before[op] = histogramCount(t, serializationDuration.WithLabelValues(op))
+	}
+
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 2]`)}); err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+
+	for _, op := range operations {
+		if got := histogramCount(t, serializationDuration.WithLabelValues(op)); got != before[op]+1 {
+			t.Errorf("Expected one %s observation, got %d", op, got-before[op])
+		}
+	}
+}
+
+func BenchmarkUnmarshalInput(b *testing.B) {
+	input := make([]float64, 100000)
+	for i := range input {
+		input[i] = float64(i) * 0.5
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded []float64
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPredict_ModelVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		version string
+	}{
+		{"reported", `{"model_name":"m","output":[1],"status":"ok","version":"v3"}`, "v3"},
+		{"omitted", `{"model_name":"m","output":[1],"status":"ok"}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, staticBackend(tt.body))
+			resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+			if err != nil {
+				t.Fatalf("Predict returned error: %v", err)
+			}
+			if resp.ModelVersion != tt.version {
+				t.Errorf("Expected model version %q, got %q", tt.version, resp.ModelVersion)
+			}
+		})
+	}
+}
+
+func TestPredict_OutputSizeLimit(t *testing.T) {
+	// Arrange: a backend returning a ~1 MiB body.
+	huge := `{"model_name":"m","status":"ok","output":[` + strings.Repeat("0.5,", 256*1024) + `0.5]}`
+	s := newTestServer(t, staticBackend(huge))
+	s.maxOutputBytes = 64 * 1024
+
+	// Act
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("Expected Internal for oversized response, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "backend response exceeds limit") {
+		t.Errorf("Expected limit message, got %v", err)
+	}
+
+	// A body exactly at the limit is accepted.
+	exact := `{"model_name":"m","output":[1],"status":"ok"}`
+	s = newTestServer(t, staticBackend(exact))
+	s.maxOutputBytes = int64(len(exact))
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}); err != nil {
+		t.Errorf("Expected body at the limit to be accepted, got %v", err)
+	}
+}
+
+func TestPredict_ResponseSizeLimit(t *testing.T) {
+	// Arrange: 100k outputs re-marshal to about 400 KB of JSON.
+	huge := `{"model_name":"m","status":"ok","output":[` + strings.Repeat("0.5,", 100_000) + `0.5]}`
+	s := newTestServer(t, staticBackend(huge))
+	s.maxSendBytes = 128 * 1024
+
+	// Act
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted for an oversized response, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "output too large to return: response is 400") || !strings.Contains(err.Error(), "limit is 131072") {
+		t.Errorf("Expected the response size and limit in the error, got %v", err)
+	}
+
+	// Quantized outputs are smaller and fit.
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Quantization: "int8"})
+	if err != nil {
+		t.Fatalf("Expected the int8 output to fit, got %v", err)
+	}
+	if len(resp.GetOutputData()) != 100_001 {
+		t.Errorf("Expected 100001 quantized values, got %d bytes", len(resp.GetOutputData()))
+	}
+}
+
+func TestPredict_EmptyOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		failOnEmpty bool
+		body        string
+		wantCode    codes.Code
+		wantMsg     string
+	}{
+		{"permissive by default", false, `{"model_name":"m","output":[],"status":"ok"}`, codes.OK, ""},
+		{"fails when enabled", true, `{"model_name":"m","output":[],"status":"ok"}`, codes.Internal, "backend returned empty output"},
+		{"missing output always fails", false, `{"model_name":"m","status":"ok"}`, codes.Internal, "backend response missing output field"},
+		{"null output always fails", false, `{"model_name":"m","output":null,"status":"ok"}`, codes.Internal, "backend response missing output field"},
+		{"non-success status passes through", true, `{"model_name":"m","output":[],"status":"degraded"}`, codes.OK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, staticBackend(tt.body))
+			s.failOnEmptyOutput = tt.failOnEmpty
+
+			// Act
+			resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantMsg != "" && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Expected %q in the error, got %v", tt.wantMsg, err)
+			}
+			if tt.wantCode == codes.OK && resp == nil {
+				t.Error("Expected a response to be forwarded")
+			}
+		})
+	}
+}
+
+func TestPredict_ObservesOutputInputRatio(t *testing.T) {
+	tests := []struct {
+		name      string
+		model     string
+		input     string
+		body      string
+		wantRatio float64
+	}{
+		{"expanding", "ratio-expand", `[1,2]`, `{"status":"ok","output":[1,2,3,4,5,6]}`, 3},
+		{"reducing", "ratio-reduce", `[1,2,3,4]`, `{"status":"ok","output":[0.5]}`, 0.25},
+		{"multi-head", "ratio-heads", `[1,2]`, `{"status":"ok","output":{"a":[1,2],"b":[3]}}`, 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, staticBackend(tt.body))
+
+			// Act
+			if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: tt.model, InputData: []byte(tt.input)}); err != nil {
+				t.Fatalf("Predict failed: %v", err)
+			}
+
+			// Assert
+			m := &dto.Metric{}
+			if err := outputInputRatio.WithLabelValues(tt.model).(prometheus.Metric).Write(m); err != nil {
+				t.Fatalf("failed to read histogram: %v", err)
+			}
+			if got := m.GetHistogram().GetSampleCount(); got != 1 {
+				t.Fatalf("Expected 1 observation, got %d", got)
+			}
+			if got := m.GetHistogram().GetSampleSum(); got != tt.wantRatio {
+				t.Errorf("Expected a ratio of %v, got %v", tt.wantRatio, got)
+			}
+		})
+	}
+}
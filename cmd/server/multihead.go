@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// multiHeadOutputs fills resp.Outputs with one JSON array per output head.
+// Softmax is applied to each head separately. Top-k, quantization and
+// output formats other than json pick from, rescale or re-encode a single
+// array, so they are rejected for multi-head models.
+func (s *server) multiHeadOutputs(req *pb.PredictRequest, format string, heads map[string][]float64, resp *pb.PredictResponse) error {
+	if req.GetTopK() > 0 {
+		return status.Errorf(codes.InvalidArgument, "top_k is not supported for multi-head model %s", req.GetModelName())
+	}
+	if req.GetQuantization() != "" {
+		return status.Errorf(codes.InvalidArgument, "quantization is not supported for multi-head model %s", req.GetModelName())
+	}
+	if format != outputFormatJSON {
+		return status.Errorf(codes.InvalidArgument, "output format %s is not supported for multi-head model %s", format, req.GetModelName())
+	}
+
+	resp.Outputs = make(map[string][]byte, len(heads))
+	for name, output := range heads {
+		if req.GetPostProcess() == postProcessSoftmax {
+			output = softmax(output)
+		}
+		if req.Precision != nil {
+			output = roundOutput(output, req.GetPrecision())
+		}
+		var data []byte
+		err := timeSerialization("marshal_output", func() (err error) {
+			data, err = json.Marshal(output)
+			return err
+		})
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to marshal output head %s: %v", name, err)
+		}
+		resp.Outputs[name] = data
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIResponse_UnmarshalOutputShapes(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantOutput  []float64
+		wantOutputs map[string][]float64
+	}{
+		{"single output", `{"output":[0.1,0.9],"status":"ok"}`, []float64{0.1, 0.9}, nil},
+		{"multi-head", `{"output":{"class":[0.2,0.8],"box":[1,2,3,4]},"status":"ok"}`, nil, map[string][]float64{"class": {0.2, 0.8}, "box": {1, 2, 3, 4}}},
+		{"no output", `{"status":"ok"}`, nil, nil},
+		{"cached multi-head", `{"output":null,"outputs":{"class":[1]},"status":"ok"}`, nil, map[string][]float64{"class": {1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp APIResponse
+			if err := json.Unmarshal([]byte(tt.body), &resp); err != nil {
+				t.Fatalf("Unmarshal returned error: %v", err)
+			}
+			if got, want := mustJSON(t, resp.Output), mustJSON(t, tt.wantOutput); got != want {
+				t.Errorf("Expected Output %s, got %s", want, got)
+			}
+			if got, want := mustJSON(t, resp.Outputs), mustJSON(t, tt.wantOutputs); got != want {
+				t.Errorf("Expected Outputs %s, got %s", want, got)
+			}
+			if resp.Status != "ok" {
+				t.Errorf("Expected status ok, got %q", resp.Status)
+			}
+		})
+	}
+}
+
+func TestAPIResponse_RejectsMalformedOutput(t *testing.T) {
+	for _, body := range []string{`{"output":"nope"}`, `{"output":{"class":"nope"}}`} {
+		var resp APIResponse
+		if err := json.Unmarshal([]byte(body), &resp); err == nil {
+			t.Errorf("Expected %s to fail to parse", body)
+		}
+	}
+}
+
+func TestPredict_MultiHeadOutputs(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":{"class":[0,0],"score":[0.5]},"status":"ok"}`))
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), PostProcess: postProcessSoftmax})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if len(resp.GetOutputData()) != 0 {
+		t.Errorf("Expected no OutputData for a multi-head model, got %s", resp.GetOutputData())
+	}
+	want := map[string]string{"class": "[0.5,0.5]", "score": "[1]"}
+	if len(resp.GetOutputs()) != len(want) {
+		t.Fatalf("Expected heads %v, got %v", want, resp.GetOutputs())
+	}
+	for head, output := range want {
+		if got := string(resp.GetOutputs()[head]); got != output {
+			t.Errorf("Head %s: expected softmaxed output %s, got %s", head, output, got)
+		}
+	}
+}
+
+func TestPredict_MultiHeadRejectsSingleArrayOptions(t *testing.T) {
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":{"class":[1,2]},"status":"ok"}`))
+	for name, req := range map[string]*pb.PredictRequest{
+		"top_k":        {ModelName: "m", InputData: []byte(`[1]`), TopK: 1},
+		"quantization": {ModelName: "m", InputData: []byte(`[1]`), Quantization: "int8"},
+	} {
+		if _, err := s.Predict(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument for a multi-head model, got %v", name, err)
+		}
+	}
+}
+
+// mustJSON encodes v for comparison in test failures.
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal %v: %v", v, err)
+	}
+	return string(data)
+}
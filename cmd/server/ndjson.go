@@ -0,0 +1,21 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// encodeNDJSON encodes each output value as a JSON number on its own line,
+// so streaming consumers can handle the values one at a time.
+func encodeNDJSON(output []float64) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, v := range output {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPredict_NDJSONOutput(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"status":"ok","output":[0.5,-2,1e21,3.25]}`))
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), OutputFormat: outputFormatNDJSON}
+
+	// Act
+	resp, err := s.Predict(context.Background(), req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	if want := "0.5\n-2\n1e+21\n3.25\n"; string(resp.GetOutputData()) != want {
+		t.Errorf("Expected one value per line %q, got %q", want, resp.GetOutputData())
+	}
+	if resp.GetOutputEncoding() != outputFormatNDJSON {
+		t.Errorf("Expected output encoding %q, got %q", outputFormatNDJSON, resp.GetOutputEncoding())
+	}
+}
+
+func TestPredict_RejectsInvalidOutputFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.PredictRequest
+	}{
+		{"unknown format", &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), OutputFormat: "csv"}},
+		{"with quantization", &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), OutputFormat: outputFormatNDJSON, Quantization: "int8"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, staticBackend(`{"status":"ok","output":[1]}`))
+
+			_, err := s.Predict(context.Background(), tt.req)
+
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPredict_JSONOutputFormatIsDefault(t *testing.T) {
+	s := newTestServer(t, staticBackend(`{"status":"ok","output":[1,2]}`))
+
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), OutputFormat: outputFormatJSON})
+
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	if string(resp.GetOutputData()) != "[1,2]" || resp.GetOutputEncoding() != "" {
+		t.Errorf("Expected a plain JSON array, got %q (encoding %q)", resp.GetOutputData(), resp.GetOutputEncoding())
+	}
+}
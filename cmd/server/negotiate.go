@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"mime"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	contentTypeJSON   = "application/json"
+	contentTypeBinary = "application/octet-stream"
+
+	// acceptBinary prefers a binary output but still accepts JSON, so
+	// backends without binary support keep working.
+	acceptBinary = contentTypeBinary + ", " + contentTypeJSON + ";q=0.9"
+
+	// backendStatusHeader carries the status of a binary backend response,
+	// which has no JSON body to hold it.
+	backendStatusHeader = "X-Model-Status"
+)
+
+// acceptFor returns the Accept header for a backend call whose output will
+// be returned with the given quantization and output format. Quantized and
+// float32-le outputs are binary anyway, so a binary backend response saves
+// the JSON round trip; float32-le is the very layout binary backends send.
+func acceptFor(quantization, format string) string {
+	if quantization != "" || format == outputFormatFloat32LE {
+		return acceptBinary
+	}
+	return contentTypeJSON
+}
+
+// isBinaryResponse reports whether the backend answered with a binary
+// output rather than JSON.
+func isBinaryResponse(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == contentTypeBinary
+}
+
+// decodeBinaryResponse decodes a binary backend response: the body is the
+// output as little-endian float32 values, and the status and version come
+// from the X-Model-Status and X-Model-Version headers. A missing status
+// means "ok".
+func decodeBinaryResponse(model string, header http.Header, body []byte) (*APIResponse, error) {
+	if len(body)%4 != 0 {
+		return nil, status.Errorf(codes.Internal, "binary backend response has %d bytes, not a multiple of 4", len(body))
+	}
+	output := make([]float64, len(body)/4)
+	for i := range output {
+		output[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(body[i*4:])))
+	}
+	resp := &APIResponse{
+		ModelName: model,
+		Output:    output,
+		Status:    header.Get(backendStatusHeader),
+		Version:   header.Get(backendModelVersionHeader),
+	}
+	if resp.Status == "" {
+		resp.Status = "ok"
+	}
+	return resp, nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/arhantsg07/ml-inference-system/internal/quantize"
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// float32Body encodes values as a binary backend response body.
+func float32Body(values ...float32) []byte {
+	body := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(body[i*4:], math.Float32bits(v))
+	}
+	return body
+}
+
+// negotiatingBackend answers in binary when the request accepts it and
+// with JSON otherwise, recording the Accept header it received.
+func negotiatingBackend(accept *string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*accept = r.Header.Get("Accept")
+		if r.Header.Get("Accept") == acceptBinary {
+			w.Header().Set("Content-Type", contentTypeBinary)
+			w.Header().Set(backendStatusHeader, "success")
+			w.Header().Set(backendModelVersionHeader, "v2")
+			w.Write(float32Body(0.25, -1.5))
+			return
+		}
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write([]byte(`{"model_name":"m","output":[0.25,-1.5],"status":"ok","version":"v1"}`))
+	}
+}
+
+func TestPredict_NegotiatesJSON(t *testing.T) {
+	// Arrange
+	var accept string
+	s := newTestServer(t, negotiatingBackend(&accept))
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if accept != contentTypeJSON {
+		t.Errorf("Expected Accept %q, got %q", contentTypeJSON, accept)
+	}
+	if string(resp.GetOutputData()) != "[0.25,-1.5]" || resp.GetModelVersion() != "v1" {
+		t.Errorf("Unexpected JSON response: %v", resp)
+	}
+}
+
+func TestPredict_NegotiatesBinary(t *testing.T) {
+	// Arrange
+	var accept string
+	s := newTestServer(t, negotiatingBackend(&accept))
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Quantization: quantize.Float16})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if accept != acceptBinary {
+		t.Errorf("Expected Accept %q, got %q", acceptBinary, accept)
+	}
+	got, err := quantize.DecodeFloat16(resp.GetOutputData())
+	if err != nil {
+		t.Fatalf("DecodeFloat16 returned error: %v", err)
+	}
+	if len(got) != 2 || got[0] != 0.25 || got[1] != -1.5 {
+		t.Errorf("Expected output [0.25 -1.5] decoded from the binary body, got %v", got)
+	}
+	if resp.GetStatus() != "success" || resp.GetModelVersion() != "v2" {
+		t.Errorf("Expected status and version from headers, got %q and %q", resp.GetStatus(), resp.GetModelVersion())
+	}
+}
+
+func TestAcceptFor(t *testing.T) {
+	tests := []struct {
+		name         string
+		quantization string
+		format       string
+		want         string
+	}{
+		{"json", "", outputFormatJSON, contentTypeJSON},
+		{"ndjson", "", outputFormatNDJSON, contentTypeJSON},
+		{"float64-le", "", outputFormatFloat64LE, contentTypeJSON},
+		{"float32-le", "", outputFormatFloat32LE, acceptBinary},
+		{"quantized", quantize.Float16, outputFormatJSON, acceptBinary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acceptFor(tt.quantization, tt.format); got != tt.want {
+				t.Errorf("acceptFor(%q, %q) = %q, want %q", tt.quantization, tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredict_NegotiatesBinaryForFloat32LE(t *testing.T) {
+	// Arrange
+	var accept string
+	s := newTestServer(t, negotiatingBackend(&accept))
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), OutputFormat: outputFormatFloat32LE})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if accept != acceptBinary {
+		t.Errorf("Expected Accept %q, got %q", acceptBinary, accept)
+	}
+	if want := string(float32Body(0.25, -1.5)); string(resp.GetOutputData()) != want || resp.GetModelVersion() != "v2" {
+		t.Errorf("Expected the binary backend output, got %v", resp)
+	}
+}
+
+func TestPredict_BinaryResponseFallsBackToJSONBackend(t *testing.T) {
+	// A backend without binary support ignores the preference.
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":[0.5],"status":"ok"}`))
+
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Quantization: quantize.Float16})
+
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if got, _ := quantize.DecodeFloat16(resp.GetOutputData()); len(got) != 1 || got[0] != 0.5 {
+		t.Errorf("Expected output [0.5], got %v", got)
+	}
+}
+
+func TestDecodeBinaryResponse_RejectsTruncatedBody(t *testing.T) {
+	_, err := decodeBinaryResponse("m", http.Header{}, []byte{1, 2, 3})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("Expected Internal for a truncated body, got %v", err)
+	}
+}
+
+func TestPredict_BinaryResponseNotCachedForJSONRequests(t *testing.T) {
+	// Arrange: 0.1 can't be represented exactly as a float32.
+	backendCalls := 0
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		backendCalls++
+		if r.Header.Get("Accept") == acceptBinary {
+			w.Header().Set("Content-Type", contentTypeBinary)
+			w.Write(float32Body(0.1))
+			return
+		}
+		w.Write([]byte(`{"model_name":"m","output":[0.1],"status":"ok"}`))
+	})
+	s.cache = newPredictionCache(newFakeCache(), time.Minute)
+
+	// Act
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Quantization: quantize.Float16}); err != nil {
+		t.Fatalf("quantized Predict returned error: %v", err)
+	}
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if got := string(resp.GetOutputData()); got != "[0.1]" {
+		t.Errorf("Expected the full-precision output [0.1], got %s", got)
+	}
+	if backendCalls != 2 {
+		t.Errorf("Expected the JSON request to miss the binary cache entry (2 backend calls), got %d", backendCalls)
+	}
+}
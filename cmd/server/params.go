@@ -0,0 +1,21 @@
+package main
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validateParams checks that the model parameters of a request, if any, are
+// a JSON object. Their contents are up to the backend.
+func validateParams(params []byte) error {
+	if len(params) == 0 {
+		return nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(params, &obj); err != nil || obj == nil {
+		return status.Errorf(codes.InvalidArgument, "params must be a JSON object, got %.64s", params)
+	}
+	return nil
+}
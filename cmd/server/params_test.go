@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPredict_ForwardsParams(t *testing.T) {
+	// Arrange
+	var got map[string]json.RawMessage
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		staticBackend(`{"status":"ok","output":[1]}`)(w, r)
+	})
+	req := &pb.PredictRequest{
+		ModelName: "m",
+		InputData: []byte(`[1,2]`),
+		Params:    []byte(`{"temperature":0.7,"top_p":0.9}`),
+	}
+
+	// Act
+	if _, err := s.Predict(context.Background(), req); err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+
+	// Assert
+	if string(got["params"]) != `{"temperature":0.7,"top_p":0.9}` {
+		t.Errorf("Expected params to reach the backend unchanged, got %s", got["params"])
+	}
+	if string(got["input"]) != `[1,2]` {
+		t.Errorf("Expected the input alongside params, got %s", got["input"])
+	}
+}
+
+func TestPredict_OmitsParamsWhenUnset(t *testing.T) {
+	// Arrange
+	var got map[string]json.RawMessage
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		staticBackend(`{"status":"ok","output":[1]}`)(w, r)
+	})
+
+	// Act
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}); err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+
+	// Assert
+	if _, ok := got["params"]; ok {
+		t.Errorf("Expected no params in the backend request, got %s", got["params"])
+	}
+}
+
+func TestPredict_RejectsInvalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+	}{
+		{"array", `[0.7]`},
+		{"number", `0.7`},
+		{"null", `null`},
+		{"malformed", `{"temperature":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			called := false
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+			req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Params: []byte(tt.params)}
+
+			// Act
+			_, err := s.Predict(context.Background(), req)
+
+			// Assert
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+			if called {
+				t.Error("Expected the backend not to be called")
+			}
+		})
+	}
+}
+
+func TestRequestHash_DependsOnParams(t *testing.T) {
+	input := []float64{1, 2}
+	if requestHash("m", "", input, nil, nil) == requestHash("m", "", input, nil, []byte(`{"temperature":0.7}`)) {
+		t.Error("Expected requests with and without params to get different hashes")
+	}
+	if requestHash("m", "", input, nil, []byte(`{"temperature":0.7}`)) == requestHash("m", "", input, nil, []byte(`{"temperature":0.1}`)) {
+		t.Error("Expected requests with different params to get different hashes")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const postProcessSoftmax = "softmax"
+
+// validatePostProcess checks the post-processing options of a request before
+// any backend work is done.
+func validatePostProcess(postProcess string, topK int32) error {
+	switch postProcess {
+	case "", postProcessSoftmax:
+	default:
+		return status.Errorf(codes.InvalidArgument, "unsupported post_process %q", postProcess)
+	}
+	if topK < 0 {
+		return status.Errorf(codes.InvalidArgument, "top_k must not be negative, got %d", topK)
+	}
+	if topK > 0 && postProcess != postProcessSoftmax {
+		return status.Errorf(codes.InvalidArgument, "top_k requires post_process %q", postProcessSoftmax)
+	}
+	return nil
+}
+
+// softmax converts logits into probabilities. The maximum is subtracted
+// before exponentiating to avoid overflow on large logits.
+func softmax(logits []float64) []float64 {
+	if len(logits) == 0 {
+		return nil
+	}
+	maxLogit := logits[0]
+	for _, v := range logits[1:] {
+		maxLogit = math.Max(maxLogit, v)
+	}
+
+	probs := make([]float64, len(logits))
+	var sum float64
+	for i, v := range logits {
+		probs[i] = math.Exp(v - maxLogit)
+		sum += probs[i]
+	}
+	for i := range probs {
+		probs[i] /= sum
+	}
+	return probs
+}
+
+// topK returns the indices and values of the k largest probabilities in
+// descending order. Ties are broken by the lower index.
+func topK(probs []float64, k int) ([]int32, []float64, error) {
+	if k > len(probs) {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "top_k (%d) exceeds output length (%d)", k, len(probs))
+	}
+
+	order := make([]int, len(probs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return probs[order[a]] > probs[order[b]]
+	})
+
+	indices := make([]int32, k)
+	values := make([]float64, k)
+	for i := range k {
+		indices[i] = int32(order[i])
+		values[i] = probs[order[i]]
+	}
+	return indices, values, nil
+}
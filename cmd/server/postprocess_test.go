@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSoftmax(t *testing.T) {
+	probs := softmax([]float64{1, 2, 3, 1000})
+
+	var sum float64
+	for _, p := range probs {
+		if math.IsNaN(p) || p < 0 || p > 1 {
+			t.Fatalf("softmax produced invalid probability %v", p)
+		}
+		sum += p
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Expected probabilities to sum to 1, got %v", sum)
+	}
+	if probs[3] < 0.999 {
+		t.Errorf("Expected dominant logit to get nearly all mass, got %v", probs[3])
+	}
+}
+
+func TestTopK(t *testing.T) {
+	probs := []float64{0.1, 0.5, 0.1, 0.3}
+
+	indices, values, err := topK(probs, 1)
+	if err != nil {
+		t.Fatalf("topK returned error: %v", err)
+	}
+	if len(indices) != 1 || indices[0] != 1 || values[0] != 0.5 {
+		t.Errorf("Expected top-1 to be index 1 (0.5), got %v %v", indices, values)
+	}
+
+	indices, _, err = topK(probs, 4)
+	if err != nil {
+		t.Fatalf("topK returned error: %v", err)
+	}
+	want := []int32{1, 3, 0, 2}
+	for i := range want {
+		if indices[i] != want[i] {
+			t.Errorf("Expected order %v with ties broken by index, got %v", want, indices)
+			break
+		}
+	}
+
+	if _, _, err := topK(probs, 5); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for k larger than output, got %v", err)
+	}
+}
+
+func TestValidatePostProcess(t *testing.T) {
+	tests := []struct {
+		name        string
+		postProcess string
+		topK        int32
+		wantErr     bool
+	}{
+		{"none", "", 0, false},
+		{"softmax", "softmax", 0, false},
+		{"softmax with top k", "softmax", 3, false},
+		{"unknown transform", "sigmoid", 0, true},
+		{"top k without softmax", "", 2, true},
+		{"negative top k", "softmax", -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePostProcess(tt.postProcess, tt.topK)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePostProcess(%q, %d) error = %v, wantErr %v", tt.postProcess, tt.topK, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPredict_TopK(t *testing.T) {
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":[0.5,2.0,1.0],"status":"ok"}`))
+
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{
+		ModelName:   "m",
+		InputData:   []byte(`[1, 2]`),
+		PostProcess: "softmax",
+		TopK:        2,
+	})
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if len(resp.OutputData) != 0 {
+		t.Errorf("Expected full output to be omitted, got %s", resp.OutputData)
+	}
+	if len(resp.TopKIndices) != 2 || resp.TopKIndices[0] != 1 || resp.TopKIndices[1] != 2 {
+		t.Errorf("Expected top-2 indices [1 2], got %v", resp.TopKIndices)
+	}
+	if resp.TopKProbabilities[0] <= resp.TopKProbabilities[1] {
+		t.Errorf("Expected probabilities in descending order, got %v", resp.TopKProbabilities)
+	}
+
+	_, err = s.Predict(context.Background(), &pb.PredictRequest{
+		ModelName:   "m",
+		InputData:   []byte(`[1, 2]`),
+		PostProcess: "softmax",
+		TopK:        4,
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument when top_k exceeds output length, got %v", err)
+	}
+}
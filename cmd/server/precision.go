@@ -0,0 +1,48 @@
+package main
+
+import (
+	"math"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPrecision is the most decimal places a request can round its output
+// to. float64 carries about 15 significant digits, so more places than that
+// would not change the output.
+const maxPrecision = 15
+
+// validatePrecision checks the output precision of a request. Quantized
+// outputs are already reduced to float16 or int8, so rounding them to decimal
+// places makes no sense.
+func validatePrecision(req *pb.PredictRequest) error {
+	if req.Precision == nil {
+		return nil
+	}
+	p := req.GetPrecision()
+	if p < 0 || p > maxPrecision {
+		return status.Errorf(codes.InvalidArgument, "precision must be between 0 and %d, got %d", maxPrecision, p)
+	}
+	if req.GetQuantization() != "" {
+		return status.Errorf(codes.InvalidArgument, "precision cannot be combined with quantization")
+	}
+	return nil
+}
+
+// roundOutput returns output with every value rounded to places decimal
+// places, halves away from zero. output itself is left unchanged.
+func roundOutput(output []float64, places int32) []float64 {
+	scale := math.Pow10(int(places))
+	rounded := make([]float64, len(output))
+	for i, v := range output {
+		if math.Abs(v*scale) >= 1<<53 {
+			// v has no digits beyond places that float64 can hold, and
+			// scaling it back would only add error.
+			rounded[i] = v
+			continue
+		}
+		rounded[i] = math.Round(v*scale) / scale
+	}
+	return rounded
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPredict_Precision(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision *int32
+		want      string
+	}{
+		{"unset", nil, `[0.123456789,2.5,-2.5,1.005,1e+300]`},
+		{"integers", proto.Int32(0), `[0,3,-3,1,1e+300]`},
+		{"two places", proto.Int32(2), `[0.12,2.5,-2.5,1,1e+300]`},
+		{"five places", proto.Int32(5), `[0.12346,2.5,-2.5,1.005,1e+300]`},
+		{"max places", proto.Int32(maxPrecision), `[0.123456789,2.5,-2.5,1.005,1e+300]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, staticBackend(`{"status":"ok","output":[0.123456789,2.5,-2.5,1.005,1e300]}`))
+			req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Precision: tt.precision}
+
+			// Act
+			resp, err := s.Predict(context.Background(), req)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict failed: %v", err)
+			}
+			if got := string(resp.GetOutputData()); got != tt.want {
+				t.Errorf("Expected output %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPredict_PrecisionAppliesToTopK(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"status":"ok","output":[1,2,3]}`))
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), PostProcess: postProcessSoftmax, TopK: 2, Precision: proto.Int32(3)}
+
+	// Act
+	resp, err := s.Predict(context.Background(), req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	want := []float64{0.665, 0.245}
+	for i, p := range resp.GetTopKProbabilities() {
+		if p != want[i] {
+			t.Errorf("Expected probabilities %v, got %v", want, resp.GetTopKProbabilities())
+			break
+		}
+	}
+}
+
+func TestPredict_RejectsInvalidPrecision(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.PredictRequest
+	}{
+		{"negative", &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Precision: proto.Int32(-1)}},
+		{"too many places", &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Precision: proto.Int32(maxPrecision + 1)}},
+		{"with quantization", &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Precision: proto.Int32(2), Quantization: "float16"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, staticBackend(`{"status":"ok","output":[1]}`))
+
+			_, err := s.Predict(context.Background(), tt.req)
+
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRoundOutput_HugeValuesUnchanged(t *testing.T) {
+	in := []float64{math.MaxFloat64, -math.MaxFloat64, math.Inf(1)}
+
+	got := roundOutput(in, maxPrecision)
+
+	for i := range in {
+		if got[i] != in[i] {
+			t.Errorf("Expected %v unchanged, got %v", in[i], got[i])
+		}
+	}
+}
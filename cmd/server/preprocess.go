@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Preprocessing operations accepted in PreprocessStep.Op.
+const (
+	preprocessClip   = "clip"
+	preprocessScale  = "scale"
+	preprocessOneHot = "one_hot"
+	preprocessPad    = "pad"
+)
+
+// PreprocessStep is one transform of a model's input pipeline. Op selects
+// the transform; each takes only its own parameters:
+//
+//   - clip limits every value to [Min, Max]; either bound may be omitted.
+//   - scale replaces every value v with v*Factor + Offset.
+//   - one_hot replaces the value at Index, a class number in [0, Classes),
+//     with Classes values that are all 0 except a 1 at that class.
+//   - pad appends Value until the input has Length values, or truncates it
+//     to Length values.
+type PreprocessStep struct {
+	Op string `yaml:"op"`
+
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+
+	Factor *float64 `yaml:"factor"`
+	Offset float64  `yaml:"offset"`
+
+	Index   int `yaml:"index"`
+	Classes int `yaml:"classes"`
+
+	Length int     `yaml:"length"`
+	Value  float64 `yaml:"value"`
+}
+
+func (p *PreprocessStep) validate() error {
+	params := []struct {
+		name string
+		set  bool
+	}{
+		{"min", p.Min != nil},
+		{"max", p.Max != nil},
+		{"factor", p.Factor != nil},
+		{"offset", p.Offset != 0},
+		{"index", p.Index != 0},
+		{"classes", p.Classes != 0},
+		{"length", p.Length != 0},
+		{"value", p.Value != 0},
+	}
+	var allowed []string
+	switch p.Op {
+	case preprocessClip:
+		allowed = []string{"min", "max"}
+		if p.Min == nil && p.Max == nil {
+			return fmt.Errorf("clip needs min, max or both")
+		}
+		if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+			return fmt.Errorf("clip min %v is above max %v", *p.Min, *p.Max)
+		}
+	case preprocessScale:
+		allowed = []string{"factor", "offset"}
+		if p.Factor == nil {
+			return fmt.Errorf("scale needs a factor")
+		}
+	case preprocessOneHot:
+		allowed = []string{"index", "classes"}
+		if p.Index < 0 {
+			return fmt.Errorf("one_hot index must not be negative")
+		}
+		if p.Classes < 2 {
+			return fmt.Errorf("one_hot needs at least 2 classes")
+		}
+	case preprocessPad:
+		allowed = []string{"length", "value"}
+		if p.Length <= 0 {
+			return fmt.Errorf("pad length must be positive")
+		}
+	default:
+		return fmt.Errorf("op %q is not one of %s, %s, %s, %s", p.Op, preprocessClip, preprocessScale, preprocessOneHot, preprocessPad)
+	}
+	for _, param := range params {
+		if param.set && !slices.Contains(allowed, param.name) {
+			return fmt.Errorf("%s does not take %s", p.Op, param.name)
+		}
+	}
+	return nil
+}
+
+// changesLength reports whether the step can change the number of values.
+func (p *PreprocessStep) changesLength() bool {
+	return p.Op == preprocessOneHot || p.Op == preprocessPad
+}
+
+// apply returns the transformed input; input itself is left unchanged.
+func (p *PreprocessStep) apply(input []float64) ([]float64, error) {
+	switch p.Op {
+	case preprocessClip:
+		out := make([]float64, len(input))
+		for i, v := range input {
+			if p.Min != nil {
+				v = max(v, *p.Min)
+			}
+			if p.Max != nil {
+				v = min(v, *p.Max)
+			}
+			out[i] = v
+		}
+		return out, nil
+	case preprocessScale:
+		out := make([]float64, len(input))
+		for i, v := range input {
+			out[i] = v**p.Factor + p.Offset
+		}
+		return out, nil
+	case preprocessOneHot:
+		if p.Index >= len(input) {
+			return nil, fmt.Errorf("one_hot index %d is beyond the %d input values", p.Index, len(input))
+		}
+		class := input[p.Index]
+		if class != math.Trunc(class) || class < 0 || class >= float64(p.Classes) {
+			return nil, fmt.Errorf("one_hot input[%d] must be a class number in [0, %d), got %v", p.Index, p.Classes, class)
+		}
+		encoded := make([]float64, p.Classes)
+		encoded[int(class)] = 1
+		return slices.Concat(input[:p.Index], encoded, input[p.Index+1:]), nil
+	case preprocessPad:
+		out := make([]float64, p.Length)
+		n := copy(out, input)
+		for i := n; i < len(out); i++ {
+			out[i] = p.Value
+		}
+		return out, nil
+	}
+	return input, nil
+}
+
+// preprocess runs the input through model's preprocessing steps in order
+// and returns the result, which is what the backend receives. Models without
+// steps get input back unchanged. An input a step cannot handle is rejected
+// with INVALID_ARGUMENT.
+func (c *Config) preprocess(model string, input []float64) ([]float64, error) {
+	for i, step := range c.Models[model].Preprocess {
+		var err error
+		if input, err = step.apply(input); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "preprocessing step %d for model %s: %v", i, model, err)
+		}
+	}
+	return input, nil
+}
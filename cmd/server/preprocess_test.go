@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const pipelineConfig = `
+models:
+  m:
+    preprocess:
+      - op: clip
+        min: 0
+        max: 10
+      - op: scale
+        factor: 0.5
+        offset: 1
+      - op: one_hot
+        index: 0
+        classes: 3
+      - op: pad
+        length: 6
+        value: -1
+`
+
+func TestPredict_PreprocessingPipeline(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []float64
+	}{
+		// [2, 20, -4] clips to [2, 10, 0], scales to [2, 6, 1], one-hot
+		// encodes class 2 to [0, 0, 1, 6, 1] and pads to six values.
+		{"padded", `[2, 20, -4]`, []float64{0, 0, 1, 6, 1, -1}},
+		// [0, 4, 4, 4, 4] becomes [1, 3, 3, 3, 3], then [0, 1, 0, 3, 3, 3, 3],
+		// which is truncated to six values.
+		{"truncated", `[0, 4, 4, 4, 4]`, []float64{0, 1, 0, 3, 3, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var got InputData
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &got)
+				staticBackend(`{"output":[1],"status":"ok"}`)(w, r)
+			})
+			cfg, err := loadConfig(writeConfig(t, pipelineConfig))
+			if err != nil {
+				t.Fatalf("loadConfig returned error: %v", err)
+			}
+			s.config = cfg
+
+			// Act
+			_, err = s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(tt.input)})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict returned error: %v", err)
+			}
+			if !slices.Equal(got.Input, tt.want) {
+				t.Errorf("Expected the backend to receive %v, got %v", tt.want, got.Input)
+			}
+		})
+	}
+}
+
+func TestPredict_PreprocessingRejectsInput(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("backend should not be called")
+	})
+	cfg, err := loadConfig(writeConfig(t, pipelineConfig))
+	if err != nil {
+		t.Fatalf("loadConfig returned error: %v", err)
+	}
+	s.config = cfg
+
+	// Act: 8 clips to 8 and scales to 5, which is not one of the 3 classes.
+	_, err = s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[8, 1]`)})
+
+	// Assert
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "preprocessing step 2") {
+		t.Errorf("Expected InvalidArgument from step 2, got %v", err)
+	}
+}
+
+func TestLoadConfig_InvalidPreprocessing(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    string
+		wantErr string
+	}{
+		{"unknown op", "op: normalize", `op "normalize" is not one of`},
+		{"clip without bounds", "op: clip", "clip needs min, max or both"},
+		{"clip bounds reversed", "{op: clip, min: 2, max: 1}", "above max"},
+		{"scale without factor", "{op: scale, offset: 1}", "scale needs a factor"},
+		{"one_hot with one class", "{op: one_hot, classes: 1}", "at least 2 classes"},
+		{"one_hot negative index", "{op: one_hot, index: -1, classes: 3}", "must not be negative"},
+		{"pad without length", "{op: pad, value: 0}", "pad length must be positive"},
+		{"foreign parameter", "{op: clip, max: 1, factor: 2}", "clip does not take factor"},
+		{"unknown parameter", "{op: pad, length: 2, width: 3}", "width"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contents := "models:\n  m:\n    preprocess:\n      - " + tt.step + "\n"
+
+			_, err := loadConfig(writeConfig(t, contents))
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_PreprocessingConflictsWithFeatureNames(t *testing.T) {
+	contents := "models:\n  m:\n    feature_names: [a, b]\n    preprocess:\n      - {op: pad, length: 3}\n"
+	if _, err := loadConfig(writeConfig(t, contents)); err == nil {
+		t.Error("Expected an error combining pad with feature_names")
+	}
+	contents = "models:\n  m:\n    feature_names: [a, b]\n    preprocess:\n      - {op: clip, min: 0}\n"
+	if _, err := loadConfig(writeConfig(t, contents)); err != nil {
+		t.Errorf("Expected clip to be allowed with feature_names, got %v", err)
+	}
+}
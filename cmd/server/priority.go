@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// priorityHeader is the gRPC metadata key clients use to mark a request's
+// urgency: "high", "normal" (the default) or "low".
+const priorityHeader = "x-priority"
+
+// priority orders requests waiting for a backend slot; lower values are
+// served first.
+type priority int
+
+const (
+	priorityHigh priority = iota
+	priorityNormal
+	priorityLow
+
+	numPriorities
+)
+
+func (p priority) String() string {
+	switch p {
+	case priorityHigh:
+		return "high"
+	case priorityLow:
+		return "low"
+	default:
+		return "normal"
+	}
+}
+
+// parsePriority returns the priority named by value; empty means normal.
+func parsePriority(value string) (priority, bool) {
+	switch value {
+	case "high":
+		return priorityHigh, true
+	case "", "normal":
+		return priorityNormal, true
+	case "low":
+		return priorityLow, true
+	default:
+		return priorityNormal, false
+	}
+}
+
+// requestPriority returns the priority the client requested through
+// metadata. Missing or unknown values are normal; priorityInterceptor
+// rejects unknown values before they get here.
+func requestPriority(ctx context.Context) priority {
+	md, _ := metadata.FromIncomingContext(ctx)
+	p, _ := parsePriority(firstValue(md, priorityHeader))
+	return p
+}
+
+// priorityInterceptor rejects requests whose x-priority metadata is not one
+// of high, normal or low.
+func priorityInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if value := firstValue(md, priorityHeader); value != "" {
+			if _, ok := parsePriority(value); !ok {
+				return nil, status.Errorf(codes.InvalidArgument, "%s must be high, normal or low, got %q", priorityHeader, value)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
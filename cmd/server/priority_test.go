@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// withPriority returns a context carrying x-priority metadata as a client
+// would send it.
+func withPriority(value string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(priorityHeader, value))
+}
+
+// waitForWaiters blocks until l has n queued requests.
+func waitForWaiters(t *testing.T, l *limiter, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for l.waiters() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d waiters, have %d", n, l.waiters())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLimiter_HighPriorityOvertakesQueuedLow(t *testing.T) {
+	// Arrange: the only slot is taken and two low-priority requests queue.
+	l := newLimiter(1, 0, 0)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+
+	order := make(chan string, 3)
+	acquire := func(name, prio string) {
+		r, err := l.acquire(withPriority(prio))
+		if err != nil {
+			t.Errorf("%s: acquire failed: %v", name, err)
+			return
+		}
+		order <- name
+		r()
+	}
+	go acquire("low-1", "low")
+	waitForWaiters(t, l, 1)
+	go acquire("low-2", "low")
+	waitForWaiters(t, l, 2)
+
+	// Act: a high-priority request arrives last.
+	go acquire("high", "high")
+	waitForWaiters(t, l, 3)
+	release()
+
+	// Assert: it is served first, then the low ones in arrival order.
+	for _, want := range []string{"high", "low-1", "low-2"} {
+		select {
+		case got := <-order:
+			if got != want {
+				t.Fatalf("Expected %s to get the next slot, got %s", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %s", want)
+		}
+	}
+}
+
+func TestLimiter_ShedsLowPriorityWhenQueueFull(t *testing.T) {
+	// Arrange: one slot, room for one waiter, filled by a low-priority
+	// request.
+	l := newLimiter(1, 1, time.Second)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first acquire failed: %v", err)
+	}
+	low := make(chan error, 1)
+	go func() {
+		_, err := l.acquire(withPriority("low"))
+		low <- err
+	}()
+	waitForWaiters(t, l, 1)
+
+	// Act: normal priority is rejected like before; high takes the place of
+	// the low-priority waiter.
+	if _, err := l.acquire(context.Background()); status.Code(err) == codes.OK {
+		t.Fatal("Expected a normal priority request to be rejected by the full queue")
+	}
+	high := make(chan error, 1)
+	go func() {
+		r, err := l.acquire(withPriority("high"))
+		if err == nil {
+			r()
+		}
+		high <- err
+	}()
+
+	// Assert
+	select {
+	case err := <-low:
+		if status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("Expected the low-priority request to be shed with ResourceExhausted, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the low-priority request to be shed")
+	}
+	waitForWaiters(t, l, 1)
+	release()
+	if err := <-high; err != nil {
+		t.Errorf("Expected the high-priority request to get the slot, got %v", err)
+	}
+}
+
+func TestPriorityInterceptor(t *testing.T) {
+	interceptor := priorityInterceptor()
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{"no metadata", context.Background(), codes.OK},
+		{"high", withPriority("high"), codes.OK},
+		{"normal", withPriority("normal"), codes.OK},
+		{"low", withPriority("low"), codes.OK},
+		{"unknown", withPriority("urgent"), codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("Expected %v, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
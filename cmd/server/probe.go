@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// probeJitter spreads probes over ±20% of the interval so a fleet of servers
+// started together doesn't probe the backend in lockstep.
+const probeJitter = 0.2
+
+var backendHealthy = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "inference_backend_healthy",
+		Help: "1 if the last background probe of the model backend succeeded, 0 otherwise",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(backendHealthy)
+}
+
+// backendProber periodically checks the backend in the background so that
+// readiness reflects backend health even when no traffic is flowing.
+type backendProber struct {
+	interval time.Duration
+	check    func(ctx context.Context) error
+	healthy  atomic.Bool
+}
+
+// newBackendProber returns a prober calling check every interval, or nil
+// when interval is not positive. The backend counts as unhealthy until the
+// first probe succeeds.
+func newBackendProber(interval time.Duration, check func(ctx context.Context) error) *backendProber {
+	if interval <= 0 {
+		return nil
+	}
+	return &backendProber{interval: interval, check: check}
+}
+
+// isHealthy reports the result of the last probe. A nil prober is always
+// healthy.
+func (p *backendProber) isHealthy() bool {
+	return p == nil || p.healthy.Load()
+}
+
+// run probes immediately and then every jittered interval until ctx is done.
+func (p *backendProber) run(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	for {
+		p.probe(ctx)
+		delay := time.Duration(float64(p.interval) * (1 + probeJitter*(2*rand.Float64()-1)))
+		if sleepContext(ctx, delay) != nil {
+			return
+		}
+	}
+}
+
+func (p *backendProber) probe(parent context.Context) {
+	ctx, cancel := context.WithTimeout(parent, p.interval)
+	defer cancel()
+	err := p.check(ctx)
+	if parent.Err() != nil {
+		// Shutting down; don't record a spurious failure.
+		return
+	}
+
+	healthy := err == nil
+	if was := p.healthy.Swap(healthy); was != healthy {
+		if healthy {
+			log.Printf("Backend probe succeeded, backend is healthy")
+		} else {
+			log.Printf("Backend probe failed, backend is unhealthy: %v", err)
+		}
+	}
+	if healthy {
+		backendHealthy.Set(1)
+	} else {
+		backendHealthy.Set(0)
+	}
+}
+
+// checkBackend sends a GET to the backend root and fails unless it answers
+// with a 2xx status.
+func (s *server) checkBackend(ctx context.Context) error {
+	target, err := s.resolveBackend(ctx)
+	if err != nil {
+		return err
+	}
+	probeURL, err := backendEndpoint(target.URL, "/")
+	if err != nil {
+		return fmt.Errorf("invalid backend URL %s: %w", redactURL(target.URL), err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probeURL, nil)
+	if err != nil {
+		return err
+	}
+	if target.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+target.Token)
+	}
+	resp, err := s.backendClient().Do(req)
+	if err != nil {
+		return err
+	}
+	// Drain the body so the connection goes back to the pool for reuse.
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("backend returned %s", resp.Status)
+	}
+	return nil
+}
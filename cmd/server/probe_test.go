@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackendProber_ReadyAfterBackendRecovers(t *testing.T) {
+	// Arrange: the backend fails its first three probes.
+	var probes atomic.Int32
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if probes.Add(1) <= 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"Status":"up"}`))
+	})
+	s.serving.Store(true)
+	s.prober = newBackendProber(5*time.Millisecond, s.checkBackend)
+
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		s.readyHandler(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return rec.Code
+	}
+	if code := ready(); code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected not ready before the first probe, got %d", code)
+	}
+
+	// Act
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.prober.run(ctx)
+		close(done)
+	}()
+
+	// Assert: readiness flips without any Predict traffic.
+	deadline := time.Now().Add(time.Second)
+	for ready() != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected ready once the backend recovered, still not ready after %d probes", probes.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := probes.Load(); n < 4 {
+		t.Errorf("Expected ready only after a successful probe, got ready after %d probes", n)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("prober did not stop after its context was cancelled")
+	}
+}
+
+func TestBackendProber_Disabled(t *testing.T) {
+	p := newBackendProber(0, nil)
+	if p != nil {
+		t.Fatal("Expected nil prober when interval is 0")
+	}
+	if !p.isHealthy() {
+		t.Error("Expected a disabled prober to report healthy")
+	}
+	p.run(context.Background()) // must return immediately
+}
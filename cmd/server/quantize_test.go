@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/arhantsg07/ml-inference-system/internal/quantize"
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPredict_QuantizedOutput(t *testing.T) {
+	output := []float64{0.0123, 0.25, -1.5, 3.75, 0.999}
+	body := `{"model_name":"m","output":[0.0123,0.25,-1.5,3.75,0.999],"status":"ok"}`
+
+	tests := []struct {
+		mode      string
+		wantBytes int
+		decode    func(*pb.PredictResponse) []float64
+		tolerance func(resp *pb.PredictResponse, v float64) float64
+	}{
+		{
+			mode:      quantize.Float16,
+			wantBytes: 2 * len(output),
+			decode: func(resp *pb.PredictResponse) []float64 {
+				out, _ := quantize.DecodeFloat16(resp.GetOutputData())
+				return out
+			},
+			tolerance: func(_ *pb.PredictResponse, v float64) float64 { return math.Abs(v) / 1024 },
+		},
+		{
+			mode:      quantize.Int8,
+			wantBytes: len(output),
+			decode: func(resp *pb.PredictResponse) []float64 {
+				return quantize.DecodeInt8(resp.GetOutputData(), resp.GetQuantScale(), resp.GetQuantZeroPoint())
+			},
+			tolerance: func(resp *pb.PredictResponse, _ float64) float64 { return resp.GetQuantScale() / 2 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, staticBackend(body))
+
+			// Act
+			resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Quantization: tt.mode})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict returned error: %v", err)
+			}
+			if resp.GetOutputEncoding() != tt.mode {
+				t.Errorf("Expected encoding %s, got %q", tt.mode, resp.GetOutputEncoding())
+			}
+			if len(resp.GetOutputData()) != tt.wantBytes {
+				t.Errorf("Expected %d bytes of output, got %d", tt.wantBytes, len(resp.GetOutputData()))
+			}
+			decoded := tt.decode(resp)
+			for i, want := range output {
+				if diff := math.Abs(decoded[i] - want); diff > tt.tolerance(resp, want) {
+					t.Errorf("value %d: %v round-tripped to %v", i, want, decoded[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPredict_UnsupportedQuantization(t *testing.T) {
+	s := &server{}
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`), Quantization: "int4"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// recentInputPreview is how much of a request's input /debug/recent shows.
+const recentInputPreview = 64
+
+// recentRequest summarizes one call for /debug/recent.
+type recentRequest struct {
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	Model     string    `json:"model,omitempty"`
+	Input     string    `json:"input"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMs float64   `json:"latency_ms"`
+}
+
+// recentRequests is a ring buffer holding the last size calls, for live
+// debugging without log aggregation.
+type recentRequests struct {
+	mu      sync.Mutex
+	entries []recentRequest
+	next    int
+	full    bool
+
+	// redactInput replaces inputs with their size.
+	redactInput bool
+}
+
+// newRecentRequests returns a buffer of size calls, or nil when size is not
+// positive.
+func newRecentRequests(size int, redactInput bool) *recentRequests {
+	if size <= 0 {
+		return nil
+	}
+	return &recentRequests{entries: make([]recentRequest, size), redactInput: redactInput}
+}
+
+func (r *recentRequests) add(entry recentRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot returns the buffered calls, newest first.
+func (r *recentRequests) snapshot() []recentRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.next
+	if r.full {
+		n = len(r.entries)
+	}
+	out := make([]recentRequest, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return out
+}
+
+// summarizeInput describes a request's input for the buffer: a preview of
+// its JSON, or just its size when inputs are redacted.
+func (r *recentRequests) summarizeInput(req any) string {
+	withInput, ok := req.(interface{ GetInputData() []byte })
+	if !ok {
+		return ""
+	}
+	input := withInput.GetInputData()
+	if r.redactInput || len(input) == 0 {
+		return fmt.Sprintf("%d bytes", len(input))
+	}
+	if len(input) > recentInputPreview {
+		return fmt.Sprintf("%s... (%d bytes)", input[:recentInputPreview], len(input))
+	}
+	return string(input)
+}
+
+// recentInterceptor adds every unary call to r once it completes.
+func recentInterceptor(r *recentRequests) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		entry := recentRequest{
+			Time:      start,
+			Method:    info.FullMethod,
+			Input:     r.summarizeInput(req),
+			Status:    status.Code(err).String(),
+			LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if withModel, ok := req.(interface{ GetModelName() string }); ok {
+			entry.Model = withModel.GetModelName()
+		}
+		if err != nil {
+			entry.Error = status.Convert(err).Message()
+		}
+		r.add(entry)
+		return resp, err
+	}
+}
+
+// recentHandler serves the buffered calls as JSON, newest first.
+func (r *recentRequests) recentHandler(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(r.snapshot())
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fetchRecent returns the entries served by r's /debug/recent handler.
+func fetchRecent(t *testing.T, r *recentRequests) []recentRequest {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.recentHandler(rec, httptest.NewRequest(http.MethodGet, "/debug/recent", nil))
+	var entries []recentRequest
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("failed to decode /debug/recent: %v (%s)", err, rec.Body.String())
+	}
+	return entries
+}
+
+func TestRecentInterceptor_CapsAtSize(t *testing.T) {
+	// Arrange
+	r := newRecentRequests(3, false)
+	interceptor := recentInterceptor(r)
+	info := &grpc.UnaryServerInfo{FullMethod: pb.Inference_Predict_FullMethodName}
+	handler := func(ctx context.Context, req any) (any, error) {
+		if req.(*pb.PredictRequest).GetModelName() == "m4" {
+			return nil, status.Errorf(codes.Unavailable, "backend down")
+		}
+		return &pb.PredictResponse{}, nil
+	}
+
+	// Act
+	for i := 1; i <= 5; i++ {
+		req := &pb.PredictRequest{ModelName: fmt.Sprintf("m%d", i), InputData: []byte(fmt.Sprintf("[%d]", i))}
+		interceptor(context.Background(), req, info, handler)
+	}
+	entries := fetchRecent(t, r)
+
+	// Assert: only the last three, newest first.
+	var models []string
+	for _, e := range entries {
+		models = append(models, e.Model)
+	}
+	if got := strings.Join(models, ","); got != "m5,m4,m3" {
+		t.Fatalf("Expected the last 3 requests newest first, got %s", got)
+	}
+	if entries[0].Input != "[5]" || entries[0].Status != "OK" || entries[0].Method != pb.Inference_Predict_FullMethodName {
+		t.Errorf("Unexpected entry: %+v", entries[0])
+	}
+	if entries[1].Status != "Unavailable" || entries[1].Error != "backend down" {
+		t.Errorf("Expected the failed call's status and error, got %+v", entries[1])
+	}
+}
+
+func TestRecentRequests_PartialAndEmpty(t *testing.T) {
+	r := newRecentRequests(4, false)
+	if entries := fetchRecent(t, r); len(entries) != 0 {
+		t.Errorf("Expected no entries, got %+v", entries)
+	}
+	r.add(recentRequest{Model: "a"})
+	r.add(recentRequest{Model: "b"})
+	if entries := fetchRecent(t, r); len(entries) != 2 || entries[0].Model != "b" {
+		t.Errorf("Expected [b a], got %+v", entries)
+	}
+	if newRecentRequests(0, false) != nil {
+		t.Error("Expected a nil buffer for size 0")
+	}
+}
+
+func TestRecentRequests_SummarizeInput(t *testing.T) {
+	long := "[" + strings.Repeat("1,", 50) + "1]"
+	tests := []struct {
+		name   string
+		redact bool
+		input  string
+		want   string
+	}{
+		{"short", false, "[1, 2]", "[1, 2]"},
+		{"long", false, long, long[:recentInputPreview] + "... (103 bytes)"},
+		{"redacted", true, "[1, 2]", "6 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRecentRequests(1, tt.redact)
+			if got := r.summarizeInput(&pb.PredictRequest{InputData: []byte(tt.input)}); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/arhantsg07/ml-inference-system/internal/recording"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+var recordsDropped = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "inference_records_dropped_total",
+		Help: "Request/response records discarded because the recording buffer was full",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(recordsDropped)
+}
+
+// recordingInterceptor tees every unary call into rec for later replay.
+// Recording happens after the handler returns and never blocks it.
+func recordingInterceptor(rec *recording.Writer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+
+		reqMsg, _ := req.(proto.Message)
+		respMsg, _ := resp.(proto.Message)
+		r, recErr := recording.NewRecord(info.FullMethod, reqMsg, respMsg, err)
+		if recErr != nil {
+			log.Printf("failed to record %s: %v", info.FullMethod, recErr)
+			return resp, err
+		}
+		if !rec.Write(r) {
+			recordsDropped.Inc()
+		}
+		return resp, err
+	}
+}
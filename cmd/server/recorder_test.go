@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/arhantsg07/ml-inference-system/internal/recording"
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+)
+
+func TestRecordingInterceptor(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	w := recording.NewWriter(&buf, 10)
+	interceptor := recordingInterceptor(w)
+	info := &grpc.UnaryServerInfo{FullMethod: pb.Inference_Predict_FullMethodName}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return &pb.PredictResponse{OutputData: []byte(`[1]`), Status: "ok"}, nil
+	}
+
+	// Act
+	_, err := interceptor(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}, info, handler)
+	if err != nil {
+		t.Fatalf("interceptor returned error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	// Assert
+	records, err := recording.Read(&buf)
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if len(records) != 1 || records[0].Method != pb.Inference_Predict_FullMethodName {
+		t.Fatalf("Expected one Predict record, got %+v", records)
+	}
+	resp := &pb.PredictResponse{}
+	if _, err := records[0].DecodeResponse(resp); err != nil || resp.Status != "ok" {
+		t.Errorf("Expected recorded response with status ok, got %v (err %v)", resp, err)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// backendRateLimitedReason is the ErrorInfo reason of a backend's 429.
+const backendRateLimitedReason = "HTTP_429"
+
+// isRetryable reports whether a backend error is transient and worth
+// retrying. ResourceExhausted only counts when the backend rate limited the
+// call: the server's own queue and shedding rejections also use it, and
+// retrying them would just put the load straight back.
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return true
+	case codes.ResourceExhausted:
+		return isBackendRateLimited(err)
+	}
+	return false
+}
+
+// isBackendRateLimited reports whether err is a backend's 429, as built by
+// rateLimitedError.
+func isBackendRateLimited(err error) bool {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetDomain() == backendErrorDomain && info.GetReason() == backendRateLimitedReason
+		}
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before the next attempt. A RetryInfo
+// detail on err (from the backend's Retry-After) takes precedence over the
+// exponential backoff.
+func retryDelay(err error, backoff time.Duration, attempt int) time.Duration {
+	if d, ok := retryInfoDelay(err); ok {
+		return d
+	}
+	return backoff << attempt
+}
+
+// retryInfoDelay extracts the RetryInfo delay attached to a status error.
+func retryInfoDelay(err error) (time.Duration, bool) {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// rateLimitedError builds the ResourceExhausted error returned when the
+// backend responds with 429. An ErrorInfo detail marks it as the backend's,
+// and Retry-After becomes a RetryInfo detail when the backend sent one.
+func rateLimitedError(retryAfter string, body []byte) error {
+	st := status.Newf(codes.ResourceExhausted, "API rate limited the request: %s", string(body))
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   backendRateLimitedReason,
+		Domain:   backendErrorDomain,
+		Metadata: map[string]string{"http_status": strconv.Itoa(http.StatusTooManyRequests)},
+	}}
+	if delay, ok := parseRetryAfter(retryAfter); ok {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	}
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
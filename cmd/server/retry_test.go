@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"3", 3 * time.Second, true},
+		{" 0 ", 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Mon, 02 Jan 2006 15:04:05 GMT", 0, true}, // in the past
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	plain := status.Error(codes.Unavailable, "down")
+	if got := retryDelay(plain, 100*time.Millisecond, 2); got != 400*time.Millisecond {
+		t.Errorf("Expected exponential backoff of 400ms, got %v", got)
+	}
+
+	limited := rateLimitedError("7", nil)
+	if got := retryDelay(limited, 100*time.Millisecond, 2); got != 7*time.Second {
+		t.Errorf("Expected Retry-After of 7s to take precedence, got %v", got)
+	}
+}
+
+func TestPredict_RateLimitedMapsToResourceExhausted(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("slow down"))
+	})
+
+	// Act
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted for 429, got %v", err)
+	}
+	delay, ok := retryInfoDelay(err)
+	if !ok {
+		t.Fatal("Expected RetryInfo detail on the error")
+	}
+	if delay != 2*time.Second {
+		t.Errorf("Expected retry delay of 2s, got %v", delay)
+	}
+}
+
+func TestPredict_RetryHonorsRetryAfter(t *testing.T) {
+	// Arrange: rate limit the first call only.
+	calls := 0
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+	})
+	s.backendRetries = 2
+	s.retryBackoff = time.Millisecond
+
+	// Act
+	start := time.Now()
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 backend calls, got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected retry to wait for Retry-After (1s), waited %v", elapsed)
+	}
+}
+
+func TestPredict_RetryAfterBeyondDeadlineFailsFast(t *testing.T) {
+	calls := 0
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	s.backendRetries = 3
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := s.Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no retries when Retry-After exceeds the deadline, got %d calls", calls)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected to fail fast, took %v", elapsed)
+	}
+}
+
+// truncatingBackend promises a larger body than it sends and then closes the
+// connection, as a crashing backend would.
+func truncatingBackend(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("failed to hijack connection: %v", err)
+			return
+		}
+		defer conn.Close()
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 100\r\n\r\n")
+		buf.WriteString(`{"model_name":"m","out`)
+		buf.Flush()
+	}
+}
+
+func TestPredict_BackendClosesMidResponse(t *testing.T) {
+	s := newTestServer(t, truncatingBackend(t))
+
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("Expected Unavailable, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "backend connection closed before full response") {
+		t.Errorf("Expected a clear truncation message, got %v", err)
+	}
+}
+
+func TestPredict_BackendClosesMidResponseIsRetried(t *testing.T) {
+	calls := 0
+	truncate := truncatingBackend(t)
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			truncate(w, r)
+			return
+		}
+		w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+	})
+	s.backendRetries = 1
+	s.retryBackoff = time.Millisecond
+
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}); err != nil {
+		t.Fatalf("Expected retry after truncated response to succeed, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 backend calls, got %d", calls)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"backend unavailable", status.Error(codes.Unavailable, "down"), true},
+		{"backend 429", rateLimitedError("", []byte("slow down")), true},
+		{"backend 429 with Retry-After", rateLimitedError("1", nil), true},
+		{"backend 429 wrapped", wrapStatus(rateLimitedError("", nil), "attempt 1"), true},
+		{"queue full", status.Error(codes.ResourceExhausted, "request queue is full"), false},
+		{"queue timeout", status.Error(codes.ResourceExhausted, "timed out after 1s waiting in request queue"), false},
+		{"shed", status.Error(codes.ResourceExhausted, "low priority request shed for a high priority one"), false},
+		{"bad input", status.Error(codes.InvalidArgument, "bad"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
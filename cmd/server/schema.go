@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// compileSchemas compiles the input and output JSON Schemas of every model
+// once, so requests only pay for validation. Relative schema paths are
+// resolved against dir, the directory of the config file.
+func (c *Config) compileSchemas(dir string) error {
+	compiler := jsonschema.NewCompiler()
+	compile := func(path string) (*jsonschema.Schema, error) {
+		if path == "" {
+			return nil, nil
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return compiler.Compile(path)
+	}
+	for name, m := range c.Models {
+		var err error
+		if m.inputSchema, err = compile(m.InputSchema); err != nil {
+			return fmt.Errorf("models[%q].input_schema: %w", name, err)
+		}
+		if m.outputSchema, err = compile(m.OutputSchema); err != nil {
+			return fmt.Errorf("models[%q].output_schema: %w", name, err)
+		}
+		c.Models[name] = m
+	}
+	return nil
+}
+
+// validateInput checks the raw JSON input against the model's input schema
+// and returns InvalidArgument listing each failing location.
+func (c *Config) validateInput(model string, input []byte) error {
+	schema := c.Models[model].inputSchema
+	if schema == nil {
+		return nil
+	}
+	violations, err := schemaViolations(schema, input)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "input_data is not valid JSON: %v", err)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	st := status.Newf(codes.InvalidArgument, "input does not match the schema for model %s: %s", model, formatViolations(violations))
+	withDetails, detailErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// validateOutput checks the backend output, a single array or the object of
+// output heads, against the model's output schema and returns Internal
+// listing each failing location.
+func (c *Config) validateOutput(model string, resp *APIResponse) error {
+	schema := c.Models[model].outputSchema
+	if schema == nil {
+		return nil
+	}
+	var output any = resp.Output
+	if resp.isMultiHead() {
+		output = resp.Outputs
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal output for validation: %v", err)
+	}
+	violations, err := schemaViolations(schema, data)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to validate output: %v", err)
+	}
+	if len(violations) > 0 {
+		return status.Errorf(codes.Internal, "backend output does not match the schema for model %s: %s", model, formatViolations(violations))
+	}
+	return nil
+}
+
+// schemaViolations validates the JSON document data against schema and
+// returns one violation per failing location, keyed by its JSON pointer.
+func schemaViolations(schema *jsonschema.Schema, data []byte) ([]*errdetails.BadRequest_FieldViolation, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	err = schema.Validate(doc)
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		location := unit.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       location,
+			Description: unit.Error.String(),
+		})
+	}
+	return violations, nil
+}
+
+func formatViolations(violations []*errdetails.BadRequest_FieldViolation) string {
+	parts := make([]string, len(violations))
+	for i, v := range violations {
+		parts[i] = v.GetField() + ": " + v.GetDescription()
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sample schemas: the input is two to three non-negative numbers, the output
+// probabilities between 0 and 1.
+const (
+	sampleInputSchema = `{
+		"type": "array",
+		"items": {"type": "number", "minimum": 0},
+		"minItems": 2,
+		"maxItems": 3
+	}`
+	sampleOutputSchema = `{
+		"type": "array",
+		"items": {"type": "number", "minimum": 0, "maximum": 1}
+	}`
+)
+
+// schemaConfig loads a config whose "scored" model uses the sample schemas,
+// referenced relative to the config file.
+func schemaConfig(t *testing.T) Config {
+	t.Helper()
+	path := writeConfig(t, `
+models:
+  scored:
+    input_schema: input.json
+    output_schema: schemas/output.json
+`)
+	dir := filepath.Dir(path)
+	os.Mkdir(filepath.Join(dir, "schemas"), 0o755)
+	for name, schema := range map[string]string{"input.json": sampleInputSchema, "schemas/output.json": sampleOutputSchema} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(schema), 0o600); err != nil {
+			t.Fatalf("failed to write schema: %v", err)
+		}
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig returned error: %v", err)
+	}
+	return cfg
+}
+
+func TestPredict_InputSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantCode   codes.Code
+		wantFields []string
+	}{
+		{"valid", `[0.5, 2]`, codes.OK, nil},
+		{"negative value", `[0.5, -1, 3]`, codes.InvalidArgument, []string{"/1"}},
+		{"too many values", `[1, 2, 3, 4]`, codes.InvalidArgument, []string{"/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			called := false
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.Write([]byte(`{"model_name":"scored","output":[0.2,0.8],"status":"ok"}`))
+			})
+			s.config = schemaConfig(t)
+
+			// Act
+			_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "scored", InputData: []byte(tt.input)})
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK {
+				return
+			}
+			if called {
+				t.Error("Expected the input to be rejected before reaching the backend")
+			}
+			var fields []string
+			for _, detail := range status.Convert(err).Details() {
+				if br, ok := detail.(*errdetails.BadRequest); ok {
+					for _, v := range br.GetFieldViolations() {
+						fields = append(fields, v.GetField())
+					}
+				}
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("Expected violations at %v, got %v (%v)", tt.wantFields, fields, err)
+			}
+			if !strings.Contains(err.Error(), "input does not match the schema for model scored: "+tt.wantFields[0]+": ") {
+				t.Errorf("Expected the schema path in the message, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPredict_OutputSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{"valid", `[0.2, 0.8]`, codes.OK, ""},
+		{"out of range", `[0.2, 1.5]`, codes.Internal, "backend output does not match the schema for model scored: /1: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, staticBackend(`{"model_name":"scored","output":`+tt.output+`,"status":"ok"}`))
+			s.config = schemaConfig(t)
+
+			_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "scored", InputData: []byte(`[1, 2]`)})
+
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantMsg != "" && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantMsg, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_InvalidSchema(t *testing.T) {
+	path := writeConfig(t, `
+models:
+  m:
+    input_schema: missing.json
+`)
+	if _, err := loadConfig(path); err == nil || !strings.Contains(err.Error(), `models["m"].input_schema`) {
+		t.Errorf("Expected an error naming the missing schema, got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(filepath.Dir(path), "missing.json"), []byte(`{"type": 5}`), 0o600)
+	if _, err := loadConfig(path); err == nil {
+		t.Error("Expected an error for a schema that doesn't compile")
+	}
+}
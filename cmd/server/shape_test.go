@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPredict_ForwardsInputShape(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape []int64
+	}{
+		{"no shape", nil},
+		{"matrix", []int64{2, 3}},
+		{"image", []int64{1, 2, 3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var got map[string]json.RawMessage
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &got)
+				staticBackend(`{"output":[1],"status":"ok"}`)(w, r)
+			})
+			req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 2, 3, 4, 5, 6]`), InputShape: tt.shape}
+
+			// Act
+			_, err := s.Predict(context.Background(), req)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Predict returned error: %v", err)
+			}
+			var shape []int64
+			if raw, ok := got["shape"]; ok {
+				json.Unmarshal(raw, &shape)
+			}
+			if !slices.Equal(shape, tt.shape) {
+				t.Errorf("Expected the backend to receive shape %v, got %v", tt.shape, shape)
+			}
+			if string(got["input"]) != "[1,2,3,4,5,6]" {
+				t.Errorf("Expected the flat input to be forwarded, got %s", got["input"])
+			}
+		})
+	}
+}
+
+func TestPredict_RejectsMismatchedInputShape(t *testing.T) {
+	tests := []struct {
+		name    string
+		shape   []int64
+		config  string
+		wantErr string
+	}{
+		{"product too small", []int64{2, 2}, "", "does not match the 6 input values"},
+		{"product too large", []int64{3, 3}, "", "does not match the 6 input values"},
+		{"overflowing product", []int64{1 << 62, 1 << 62}, "", "does not match the 6 input values"},
+		{"zero dimension", []int64{6, 0}, "", "input_shape[1] must be positive"},
+		{"negative dimension", []int64{-2, -3}, "", "input_shape[0] must be positive"},
+		{"named features", []int64{2, 3}, "models:\n  m:\n    feature_names: [a, b, c, d, e, f]\n", "named features"},
+		{"length-changing preprocessing", []int64{2, 3}, "models:\n  m:\n    preprocess:\n      - {op: pad, length: 8}\n", "changes the input length"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Error("backend should not be called")
+			})
+			if tt.config != "" {
+				cfg, err := loadConfig(writeConfig(t, tt.config))
+				if err != nil {
+					t.Fatalf("loadConfig returned error: %v", err)
+				}
+				s.config = cfg
+			}
+			req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 2, 3, 4, 5, 6]`), InputShape: tt.shape}
+
+			// Act
+			_, err := s.Predict(context.Background(), req)
+
+			// Assert
+			if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected InvalidArgument containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRequestHash_DependsOnShape(t *testing.T) {
+	input := []float64{1, 2, 3, 4, 5, 6}
+	hashes := map[string]bool{}
+	for _, shape := range [][]int64{nil, {6}, {2, 3}, {3, 2}, {1, 2, 3}} {
+		hashes[requestHash("m", "", input, shape, nil)] = true
+	}
+	if len(hashes) != 5 {
+		t.Errorf("Expected 5 distinct hashes for 5 shapes, got %d", len(hashes))
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var shedRequests = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "inference_shed_requests_total",
+		Help: "Requests rejected up front because their deadline was shorter than the observed backend latency",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(shedRequests)
+}
+
+// loadShedder tracks recent backend latencies and rejects requests whose
+// remaining deadline is too short to plausibly complete, so they fail fast
+// instead of occupying a backend slot only to time out.
+type loadShedder struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	filled  bool
+
+	// minSamples is the number of observations required before shedding.
+	minSamples int
+	// factor scales the observed p99 latency; a request is shed when its
+	// remaining deadline is below factor * p99.
+	factor float64
+}
+
+// newLoadShedder returns a shedder keeping the last window latency samples,
+// or nil when window is not positive.
+func newLoadShedder(window, minSamples int, factor float64) *loadShedder {
+	if window <= 0 {
+		return nil
+	}
+	return &loadShedder{
+		samples:    make([]time.Duration, window),
+		minSamples: min(minSamples, window),
+		factor:     factor,
+	}
+}
+
+// observe records the latency of a backend call, whether or not it succeeded.
+func (l *loadShedder) observe(d time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % len(l.samples)
+	if l.next == 0 {
+		l.filled = true
+	}
+}
+
+// p99 returns the 99th percentile of the recorded latencies and the number
+// of samples it was computed from.
+func (l *loadShedder) p99() (time.Duration, int) {
+	l.mu.Lock()
+	n := l.next
+	if l.filled {
+		n = len(l.samples)
+	}
+	sorted := slices.Clone(l.samples[:n])
+	l.mu.Unlock()
+
+	if n == 0 {
+		return 0, 0
+	}
+	slices.Sort(sorted)
+	return percentile(sorted, 0.99), n
+}
+
+// percentile returns the p-th quantile (0 < p <= 1) of sorted latencies
+// using the nearest-rank method, or 0 when there are none.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(math.Ceil(p*float64(len(sorted))))-1]
+}
+
+// admit returns a ResourceExhausted error when the context deadline is
+// shorter than the scaled p99 backend latency. Requests without a deadline
+// are always admitted.
+func (l *loadShedder) admit(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	p99, n := l.p99()
+	if n < l.minSamples {
+		return nil
+	}
+
+	required := time.Duration(float64(p99) * l.factor)
+	if remaining := time.Until(deadline); remaining < required {
+		shedRequests.Inc()
+		return status.Errorf(
+			codes.ResourceExhausted,
+			"request deadline %v is shorter than current backend p99 latency %v", remaining.Round(time.Millisecond), p99.Round(time.Millisecond),
+		)
+	}
+	return nil
+}
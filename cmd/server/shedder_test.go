@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoadShedder_P99(t *testing.T) {
+	l := newLoadShedder(100, 1, 1)
+	for i := 1; i <= 100; i++ {
+		l.observe(time.Duration(i) * time.Millisecond)
+	}
+	if p99, n := l.p99(); p99 != 99*time.Millisecond || n != 100 {
+		t.Errorf("Expected p99 of 99ms over 100 samples, got %v over %d", p99, n)
+	}
+
+	// Older samples are evicted once the window wraps.
+	for range 100 {
+		l.observe(time.Millisecond)
+	}
+	if p99, _ := l.p99(); p99 != time.Millisecond {
+		t.Errorf("Expected p99 to reflect only recent samples, got %v", p99)
+	}
+}
+
+func TestLoadShedder_Admit(t *testing.T) {
+	l := newLoadShedder(10, 5, 1)
+
+	short, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Not enough samples yet: everything is admitted.
+	for range 4 {
+		l.observe(time.Second)
+	}
+	if err := l.admit(short); err != nil {
+		t.Fatalf("Expected admission below min samples, got %v", err)
+	}
+
+	l.observe(time.Second)
+	if err := l.admit(short); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected short-deadline request to be shed, got %v", err)
+	}
+
+	long, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := l.admit(long); err != nil {
+		t.Errorf("Expected long-deadline request to be admitted, got %v", err)
+	}
+	if err := l.admit(context.Background()); err != nil {
+		t.Errorf("Expected request without deadline to be admitted, got %v", err)
+	}
+}
+
+func TestPredict_ShedsShortDeadlinesUnderHighLatency(t *testing.T) {
+	// Arrange: a backend that takes 50ms per call.
+	backendCalls := 0
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		backendCalls++
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+	})
+	s.shedder = newLoadShedder(10, 3, 1)
+
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 2]`)}
+	for range 3 {
+		if _, err := s.Predict(context.Background(), req); err != nil {
+			t.Fatalf("warm-up Predict failed: %v", err)
+		}
+	}
+
+	// Act
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := s.Predict(ctx, req)
+
+	// Assert
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted for short deadline, got %v", err)
+	}
+	if backendCalls != 3 {
+		t.Errorf("Expected shed request not to reach the backend, got %d calls", backendCalls)
+	}
+}
+
+func TestPredict_ShedderObservesFailedCalls(t *testing.T) {
+	// Arrange: a backend that never answers within the backend timeout.
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	})
+	s.backendTimeout = 20 * time.Millisecond
+	s.shedder = newLoadShedder(10, 1, 1)
+
+	// Act
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 2]`)})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected the timed-out backend call to fail")
+	}
+	if p99, n := s.shedder.p99(); n != 1 || p99 < s.backendTimeout {
+		t.Errorf("Expected one sample of at least %v, got %v over %d", s.backendTimeout, p99, n)
+	}
+}
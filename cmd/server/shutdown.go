@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os"
+	"sync"
+)
+
+// forceOnSecondSignal watches sigs once a first signal has started a
+// graceful shutdown, and calls force if another one arrives, so an operator
+// can cut short a shutdown that hangs. The returned function stops watching
+// and waits for the watcher to exit.
+func forceOnSecondSignal(sigs <-chan os.Signal, force func(os.Signal)) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		select {
+		case sig := <-sigs:
+			force(sig)
+		case <-done:
+		}
+	})
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestForceOnSecondSignal(t *testing.T) {
+	// Arrange: the first signal has started a graceful shutdown.
+	sigs := make(chan os.Signal, 1)
+	sigs <- syscall.SIGTERM
+	<-sigs
+	forced := make(chan os.Signal, 1)
+	stop := forceOnSecondSignal(sigs, func(sig os.Signal) { forced <- sig })
+	defer stop()
+
+	// Act: the operator sends a second signal.
+	sigs <- os.Interrupt
+
+	// Assert
+	select {
+	case sig := <-forced:
+		if sig != os.Interrupt {
+			t.Errorf("Expected the forced stop to report the second signal, got %v", sig)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a second signal to force the stop")
+	}
+}
+
+func TestForceOnSecondSignal_StopsWatching(t *testing.T) {
+	// Arrange
+	sigs := make(chan os.Signal, 1)
+	forced := false
+	stop := forceOnSecondSignal(sigs, func(os.Signal) { forced = true })
+
+	// Act: shutdown finishes on its own, then a late signal arrives.
+	stop()
+	sigs <- syscall.SIGTERM
+
+	// Assert
+	if forced {
+		t.Error("Expected no forced stop once shutdown completed")
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+func TestPredict_SignsBackendRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantHeader string
+	}{
+		{"default header", "", "X-Signature"},
+		{"custom header", "X-Body-Hmac", "X-Body-Hmac"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var body []byte
+			var signature string
+			s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				signature = r.Header.Get(tt.wantHeader)
+				w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+			})
+			s.signer = newRequestSigner("s3cret", tt.header)
+
+			// Act
+			if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 2]`)}); err != nil {
+				t.Fatalf("Predict returned error: %v", err)
+			}
+
+			// Assert
+			mac := hmac.New(sha256.New, []byte("s3cret"))
+			mac.Write(body)
+			want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if signature != want {
+				t.Errorf("Expected %s: %s, got %q", tt.wantHeader, want, signature)
+			}
+		})
+	}
+}
+
+func TestNewRequestSigner_DisabledWithoutSecret(t *testing.T) {
+	signer := newRequestSigner("", "X-Signature")
+	if signer != nil {
+		t.Fatal("Expected nil signer without a secret")
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://backend/predict", nil)
+	signer.sign(req, []byte(`{}`))
+	if got := req.Header.Get("X-Signature"); got != "" {
+		t.Errorf("Expected no signature, got %q", got)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	sloMet      = "met"
+	sloViolated = "violated"
+)
+
+var (
+	sloRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_slo_requests_total",
+			Help: "Predict requests for models with a latency_objective, by whether they met it",
+		},
+		[]string{"model", "result"},
+	)
+	sloSuccessRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inference_slo_success_ratio",
+			Help: "Share of the model's last -slo-window SLO requests that met its latency_objective",
+		},
+		[]string{"model"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(sloRequests, sloSuccessRatio)
+}
+
+// sloExcludedStatuses are the Predict status labels of requests rejected for
+// the client's own mistakes, which don't count for or against the SLO.
+var sloExcludedStatuses = map[string]bool{
+	"bad-input":   true,
+	"empty-input": true,
+	"duplicate":   true,
+}
+
+// sloTracker keeps, per model, whether each of its last window requests met
+// the model's latency objective, for the sliding-window success ratio.
+type sloTracker struct {
+	window int
+
+	mu     sync.Mutex
+	models map[string]*sloOutcomes
+}
+
+// sloOutcomes is a ring buffer of request outcomes.
+type sloOutcomes struct {
+	met    []bool
+	next   int
+	filled bool
+	count  int // number of true entries in met
+}
+
+// newSLOTracker returns a tracker over the last window requests of each
+// model, or nil when window is not positive.
+func newSLOTracker(window int) *sloTracker {
+	if window <= 0 {
+		return nil
+	}
+	return &sloTracker{window: window, models: make(map[string]*sloOutcomes)}
+}
+
+// record adds an outcome for model and returns the model's success ratio
+// over its window.
+func (t *sloTracker) record(model string, met bool) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w := t.models[model]
+	if w == nil {
+		w = &sloOutcomes{met: make([]bool, t.window)}
+		t.models[model] = w
+	}
+	if w.filled && w.met[w.next] {
+		w.count--
+	}
+	w.met[w.next] = met
+	if met {
+		w.count++
+	}
+	w.next = (w.next + 1) % len(w.met)
+	if w.next == 0 {
+		w.filled = true
+	}
+	n := w.next
+	if w.filled {
+		n = len(w.met)
+	}
+	return float64(w.count) / float64(n)
+}
+
+// observeSLO records a finished Predict call for model against the model's
+// latency_objective. A call meets the objective when it succeeded within it;
+// calls for models without an objective, and calls rejected as the client's
+// mistake, are not recorded.
+func (s *server) observeSLO(model, statusLabel string, elapsed time.Duration) {
+	objective := s.config.Models[model].LatencyObjective
+	if objective <= 0 || sloExcludedStatuses[statusLabel] {
+		return
+	}
+	met := (statusLabel == "ok" || statusLabel == "zero-input") && elapsed <= objective
+	result := sloViolated
+	if met {
+		result = sloMet
+	}
+	sloRequests.WithLabelValues(model, result).Inc()
+	if s.slo != nil {
+		sloSuccessRatio.WithLabelValues(model).Set(s.slo.record(model, met))
+	}
+}
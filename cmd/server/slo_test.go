@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveSLO(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        string
+		status       string
+		elapsed      time.Duration
+		wantMet      float64
+		wantViolated float64
+	}{
+		{"fast success", "slo-fast", "ok", 50 * time.Millisecond, 1, 0},
+		{"at the objective", "slo-edge", "ok", 100 * time.Millisecond, 1, 0},
+		{"slow success", "slo-slow", "ok", 150 * time.Millisecond, 0, 1},
+		{"fast failure", "slo-failed", "api-error", 10 * time.Millisecond, 0, 1},
+		{"client error", "slo-client", "bad-input", 10 * time.Millisecond, 0, 0},
+		{"no objective", "slo-none", "ok", 10 * time.Millisecond, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := &server{slo: newSLOTracker(10)}
+			s.config.Models = map[string]ModelConfig{tt.model: {LatencyObjective: 100 * time.Millisecond}}
+			if tt.model == "slo-none" {
+				s.config.Models = nil
+			}
+
+			// Act
+			s.observeSLO(tt.model, tt.status, tt.elapsed)
+
+			// Assert
+			if got := testutil.ToFloat64(sloRequests.WithLabelValues(tt.model, sloMet)); got != tt.wantMet {
+				t.Errorf("Expected %v met, got %v", tt.wantMet, got)
+			}
+			if got := testutil.ToFloat64(sloRequests.WithLabelValues(tt.model, sloViolated)); got != tt.wantViolated {
+				t.Errorf("Expected %v violated, got %v", tt.wantViolated, got)
+			}
+		})
+	}
+}
+
+func TestObserveSLO_SlidingSuccessRatio(t *testing.T) {
+	// Arrange
+	s := &server{slo: newSLOTracker(4)}
+	s.config.Models = map[string]ModelConfig{"slo-ratio": {LatencyObjective: 100 * time.Millisecond}}
+	ratio := sloSuccessRatio.WithLabelValues("slo-ratio")
+
+	// Act & Assert
+	s.observeSLO("slo-ratio", "ok", 200*time.Millisecond)
+	if got := testutil.ToFloat64(ratio); got != 0 {
+		t.Errorf("Expected ratio 0 after one violation, got %v", got)
+	}
+	for range 3 {
+		s.observeSLO("slo-ratio", "ok", 10*time.Millisecond)
+	}
+	if got := testutil.ToFloat64(ratio); got != 0.75 {
+		t.Errorf("Expected ratio 0.75 with 3 of 4 met, got %v", got)
+	}
+	// The violation slides out of the window of 4.
+	s.observeSLO("slo-ratio", "ok", 10*time.Millisecond)
+	if got := testutil.ToFloat64(ratio); got != 1 {
+		t.Errorf("Expected ratio 1 once the violation left the window, got %v", got)
+	}
+}
+
+func TestPredict_RecordsSLO(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"output":[1],"status":"ok"}`))
+	s.slo = newSLOTracker(10)
+	s.config.Models = map[string]ModelConfig{"slo-predict": {LatencyObjective: time.Hour}}
+	before := testutil.ToFloat64(sloRequests.WithLabelValues("slo-predict", sloMet))
+
+	// Act
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "slo-predict", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if got := testutil.ToFloat64(sloRequests.WithLabelValues("slo-predict", sloMet)) - before; got != 1 {
+		t.Errorf("Expected 1 request counted as meeting the objective, got %v", got)
+	}
+	if got := testutil.ToFloat64(sloSuccessRatio.WithLabelValues("slo-predict")); got != 1 {
+		t.Errorf("Expected success ratio 1, got %v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/stats"
+)
+
+var (
+	grpcBytesReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_grpc_received_bytes_total",
+			Help: "Bytes of request payload received on the wire per RPC method",
+		},
+		[]string{"method"},
+	)
+	grpcBytesSent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "inference_grpc_sent_bytes_total",
+			Help: "Bytes of response payload sent on the wire per RPC method",
+		},
+		[]string{"method"},
+	)
+	grpcActiveConnections = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "inference_grpc_active_connections",
+			Help: "Number of open gRPC client connections",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(grpcBytesReceived, grpcBytesSent, grpcActiveConnections)
+}
+
+type rpcMethodKey struct{}
+
+// transportStats is a grpc stats.Handler exporting payload bytes per method
+// and the number of open connections.
+type transportStats struct{}
+
+func (transportStats) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, rpcMethodKey{}, info.FullMethodName)
+}
+
+func (transportStats) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	method, _ := ctx.Value(rpcMethodKey{}).(string)
+	switch p := s.(type) {
+	case *stats.InPayload:
+		grpcBytesReceived.WithLabelValues(method).Add(float64(p.WireLength))
+	case *stats.OutPayload:
+		grpcBytesSent.WithLabelValues(method).Add(float64(p.WireLength))
+	}
+}
+
+func (transportStats) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (transportStats) HandleConn(ctx context.Context, s stats.ConnStats) {
+	switch s.(type) {
+	case *stats.ConnBegin:
+		grpcActiveConnections.Inc()
+	case *stats.ConnEnd:
+		grpcActiveConnections.Dec()
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestTransportStats_CountsBytesAndConnections(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"model_name":"m","output":[1,2,3],"status":"ok"}`))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer(grpc.StatsHandler(transportStats{}))
+	pb.RegisterInferenceServer(grpcServer, s)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	method := pb.Inference_Predict_FullMethodName
+	receivedBefore := testutil.ToFloat64(grpcBytesReceived.WithLabelValues(method))
+	sentBefore := testutil.ToFloat64(grpcBytesSent.WithLabelValues(method))
+	connsBefore := testutil.ToFloat64(grpcActiveConnections)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+
+	// Act
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := pb.NewInferenceClient(conn).Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 2]`)}); err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+
+	// Assert
+	if got := testutil.ToFloat64(grpcBytesReceived.WithLabelValues(method)) - receivedBefore; got <= 0 {
+		t.Errorf("Expected received bytes to increase, moved by %v", got)
+	}
+	if got := testutil.ToFloat64(grpcBytesSent.WithLabelValues(method)) - sentBefore; got <= 0 {
+		t.Errorf("Expected sent bytes to increase, moved by %v", got)
+	}
+	if got := testutil.ToFloat64(grpcActiveConnections) - connsBefore; got != 1 {
+		t.Errorf("Expected one active connection, got %v", got)
+	}
+
+	conn.Close()
+	deadline := time.Now().Add(time.Second)
+	for testutil.ToFloat64(grpcActiveConnections) != connsBefore {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the connection gauge to drop after the client closed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
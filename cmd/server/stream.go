@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// PredictLargeInput reassembles an input streamed in chunks and serves it
+// like Predict, so huge inputs don't require raising the gRPC message size
+// limit. The reassembled input is capped at maxInputBytes. The reassembled
+// request then goes through the unary interceptors, so it is logged, bounded
+// by the default deadline and recorded like any Predict call.
+func (s *server) PredictLargeInput(stream pb.Inference_PredictLargeInputServer) error {
+	var req *pb.PredictRequest
+	var input bytes.Buffer
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			req = &pb.PredictRequest{
+				ModelName:   chunk.GetModelName(),
+				PostProcess: chunk.GetPostProcess(),
+				TopK:        chunk.GetTopK(),
+			}
+		}
+		if err := s.checkInputSize(int64(input.Len() + len(chunk.GetData()))); err != nil {
+			return err
+		}
+		input.Write(chunk.GetData())
+	}
+	if req == nil {
+		return status.Errorf(codes.InvalidArgument, "no input chunks received")
+	}
+	req.InputData = input.Bytes()
+
+	resp, err := s.interceptStreamed(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
+
+// interceptStreamed serves a reassembled streamed request with Predict,
+// wrapped in s.interceptors in order. gRPC only runs unary interceptors for
+// unary RPCs, and the model name they log and the input they record are only
+// known once every chunk has arrived.
+func (s *server) interceptStreamed(ctx context.Context, req *pb.PredictRequest) (*pb.PredictResponse, error) {
+	info := &grpc.UnaryServerInfo{Server: s, FullMethod: pb.Inference_PredictLargeInput_FullMethodName}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return s.Predict(ctx, req.(*pb.PredictRequest))
+	}
+	for i := len(s.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := s.interceptors[i], handler
+		handler = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.PredictResponse), nil
+}
+
+// checkInputSize rejects inputs larger than maxInputBytes.
+func (s *server) checkInputSize(n int64) error {
+	if s.maxInputBytes > 0 && n > s.maxInputBytes {
+		return status.Errorf(codes.InvalidArgument, "input exceeds the maximum size of %d bytes", s.maxInputBytes)
+	}
+	return nil
+}
+
+// checkResponseSize returns ResourceExhausted when resp would exceed the
+// gRPC send limit, which would otherwise fail in the transport with a less
+// helpful error.
+func (s *server) checkResponseSize(resp proto.Message) error {
+	if s.maxSendBytes <= 0 {
+		return nil
+	}
+	if size := proto.Size(resp); size > s.maxSendBytes {
+		return status.Errorf(codes.ResourceExhausted, "output too large to return: response is %d bytes, limit is %d (-grpc-max-send-bytes)", size, s.maxSendBytes)
+	}
+	return nil
+}
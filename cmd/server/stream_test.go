@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeChunkStream feeds chunks to PredictLargeInput and captures the reply.
+type fakeChunkStream struct {
+	grpc.ServerStream
+	chunks []*pb.PredictInputChunk
+	resp   *pb.PredictResponse
+}
+
+func (f *fakeChunkStream) Context() context.Context { return context.Background() }
+
+func (f *fakeChunkStream) Recv() (*pb.PredictInputChunk, error) {
+	if len(f.chunks) == 0 {
+		return nil, io.EOF
+	}
+	c := f.chunks[0]
+	f.chunks = f.chunks[1:]
+	return c, nil
+}
+
+func (f *fakeChunkStream) SendAndClose(resp *pb.PredictResponse) error {
+	f.resp = resp
+	return nil
+}
+
+func TestPredictLargeInput_ReassemblesChunks(t *testing.T) {
+	// Arrange: the backend echoes the input it received.
+	var gotModel string
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var in InputData
+		json.NewDecoder(r.Body).Decode(&in)
+		gotModel = in.ModelName
+		json.NewEncoder(w).Encode(APIResponse{ModelName: in.ModelName, Output: in.Input, Status: "ok"})
+	})
+	stream := &fakeChunkStream{chunks: []*pb.PredictInputChunk{
+		{ModelName: "big", Data: []byte(`[1.5, 2`)},
+		{Data: []byte(`, 3`)},
+		{Data: []byte(`, 4]`)},
+	}}
+
+	// Act
+	err := s.PredictLargeInput(stream)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("PredictLargeInput returned error: %v", err)
+	}
+	if gotModel != "big" {
+		t.Errorf("Expected model from the first chunk, backend got %q", gotModel)
+	}
+	if got := string(stream.resp.GetOutputData()); got != "[1.5,2,3,4]" {
+		t.Errorf("Expected reassembled input [1.5,2,3,4], got %s", got)
+	}
+}
+
+func TestPredictLargeInput_SizeGuard(t *testing.T) {
+	// Arrange: every chunk is under the limit but the total is not.
+	backendCalls := 0
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		backendCalls++
+	})
+	s.maxInputBytes = 10
+	stream := &fakeChunkStream{chunks: []*pb.PredictInputChunk{
+		{ModelName: "big", Data: []byte(`[1, 2,`)},
+		{Data: []byte(` 3, 4]`)},
+	}}
+
+	// Act
+	err := s.PredictLargeInput(stream)
+
+	// Assert
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for oversized input, got %v", err)
+	}
+	if backendCalls != 0 {
+		t.Errorf("Expected oversized input not to reach the backend, got %d calls", backendCalls)
+	}
+}
+
+func TestPredictLargeInput_NoChunks(t *testing.T) {
+	s := &server{}
+	if err := s.PredictLargeInput(&fakeChunkStream{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an empty stream, got %v", err)
+	}
+}
+
+func TestPredict_MaxInputBytes(t *testing.T) {
+	s := &server{maxInputBytes: 4}
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1, 2, 3]`)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for oversized input, got %v", err)
+	}
+}
+
+func TestPredictLargeInput_RunsUnaryInterceptors(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"output":[1],"status":"ok"}`))
+	s.verbose.Store(true)
+	var buf bytes.Buffer
+	var hadDeadline bool
+	s.interceptors = []grpc.UnaryServerInterceptor{
+		loggingInterceptor(&buf, 0),
+		deadlineInterceptor(time.Minute),
+		func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			_, hadDeadline = ctx.Deadline()
+			return handler(ctx, req)
+		},
+	}
+	stream := &fakeChunkStream{chunks: []*pb.PredictInputChunk{
+		{ModelName: "big", Data: []byte(`[1,`)},
+		{Data: []byte(` 2]`)},
+	}}
+
+	// Act
+	err := s.PredictLargeInput(stream)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("PredictLargeInput returned error: %v", err)
+	}
+	logs := buf.String()
+	for _, want := range []string{`model="big"`, "Parsed input array: [1 2]", "Successfully processed the prediction request"} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("Expected the streamed call logged with %q, logs:\n%s", want, logs)
+		}
+	}
+	if !hadDeadline {
+		t.Error("Expected the streamed call to get the default deadline")
+	}
+}
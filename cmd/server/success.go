@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SuccessCheck decides whether a 2xx backend response is a real success by
+// looking at a field of its JSON body, for backends that answer logical
+// errors with 200 and an error flag. Exactly one of SuccessValues and
+// ErrorValues must be set.
+type SuccessCheck struct {
+	// Field is the dot-separated path of the field to check, e.g. "error"
+	// or "result.ok".
+	Field string `yaml:"field"`
+
+	// SuccessValues lists the values of Field that mean success; any other
+	// value, or a missing field, is an error.
+	SuccessValues []any `yaml:"success_values"`
+
+	// ErrorValues lists the values of Field that mean an error; any other
+	// value, or a missing field, is a success.
+	ErrorValues []any `yaml:"error_values"`
+
+	// MessageField is the optional path of the backend's error message,
+	// which is included in the error returned to the client.
+	MessageField string `yaml:"message_field"`
+}
+
+func (sc *SuccessCheck) validate() error {
+	if sc.Field == "" {
+		return fmt.Errorf("success_check.field is required")
+	}
+	if (len(sc.SuccessValues) == 0) == (len(sc.ErrorValues) == 0) {
+		return fmt.Errorf("success_check needs exactly one of success_values and error_values")
+	}
+	for _, v := range slices.Concat(sc.SuccessValues, sc.ErrorValues) {
+		switch v.(type) {
+		case nil, bool, int, float64, string:
+		default:
+			return fmt.Errorf("success_check values must be scalars, got %v", v)
+		}
+	}
+	return nil
+}
+
+// checkSuccess returns an Internal error when the success check configured
+// in c says the backend response body is a logical error. It does nothing
+// without a success check, so any 2xx response is a success.
+func (c *Config) checkSuccess(body []byte) error {
+	sc := c.SuccessCheck
+	if sc == nil {
+		return nil
+	}
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return status.Errorf(codes.Internal, "Failed to parse external API response: %v", err)
+	}
+	value, found := lookupField(doc, sc.Field)
+	var ok bool
+	if len(sc.SuccessValues) > 0 {
+		ok = found && containsValue(sc.SuccessValues, value)
+	} else {
+		ok = !found || !containsValue(sc.ErrorValues, value)
+	}
+	if ok {
+		return nil
+	}
+
+	got := "missing"
+	if found {
+		got = jsonValue(value)
+	}
+	msg := fmt.Sprintf("backend reported an error (%s is %s)", sc.Field, got)
+	if detail, ok := lookupField(doc, sc.MessageField); ok && sc.MessageField != "" {
+		if s, isString := detail.(string); isString {
+			msg += ": " + s
+		} else {
+			msg += ": " + jsonValue(detail)
+		}
+	}
+	return status.Error(codes.Internal, msg)
+}
+
+// lookupField follows a dot-separated path of object keys through a decoded
+// JSON document.
+func lookupField(doc any, path string) (any, bool) {
+	for key := range strings.SplitSeq(path, ".") {
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if doc, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return doc, true
+}
+
+// containsValue reports whether value, decoded from JSON, equals one of the
+// configured values. Both sides are compared in their JSON encoding so a
+// YAML 1 matches a JSON 1.0.
+func containsValue(values []any, value any) bool {
+	want := jsonValue(value)
+	return slices.ContainsFunc(values, func(v any) bool {
+		return jsonValue(v) == want
+	})
+}
+
+func jsonValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
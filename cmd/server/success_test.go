@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// successConfig loads a config whose success check is given as YAML.
+func successConfig(t *testing.T, check string) Config {
+	t.Helper()
+	cfg, err := loadConfig(writeConfig(t, "success_check:\n"+check))
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+	return cfg
+}
+
+func TestPredict_SuccessCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    string
+		body     string
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{
+			name:     "error flag set",
+			check:    "  field: error\n  error_values: [true]\n  message_field: message\n",
+			body:     `{"status":"ok","output":[],"error":true,"message":"model not loaded"}`,
+			wantCode: codes.Internal,
+			wantMsg:  "backend reported an error (error is true): model not loaded",
+		},
+		{
+			name:     "error flag unset",
+			check:    "  field: error\n  error_values: [true]\n",
+			body:     `{"status":"ok","output":[1],"error":false}`,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "error flag missing",
+			check:    "  field: error\n  error_values: [true]\n",
+			body:     `{"status":"ok","output":[1]}`,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "nested success value",
+			check:    "  field: result.code\n  success_values: [0]\n",
+			body:     `{"status":"ok","output":[1],"result":{"code":0}}`,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "nested failure value",
+			check:    "  field: result.code\n  success_values: [0]\n",
+			body:     `{"status":"ok","output":[1],"result":{"code":7}}`,
+			wantCode: codes.Internal,
+			wantMsg:  "(result.code is 7)",
+		},
+		{
+			name:     "success field missing",
+			check:    "  field: result.code\n  success_values: [0]\n",
+			body:     `{"status":"ok","output":[1]}`,
+			wantCode: codes.Internal,
+			wantMsg:  "(result.code is missing)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			s := newTestServer(t, staticBackend(tt.body))
+			s.config = successConfig(t, tt.check)
+
+			// Act
+			_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+			// Assert
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantMsg != "" && !strings.Contains(status.Convert(err).Message(), tt.wantMsg) {
+				t.Errorf("Expected message containing %q, got %q", tt.wantMsg, status.Convert(err).Message())
+			}
+		})
+	}
+}
+
+func TestPredict_NoSuccessCheckTrusts2xx(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(`{"status":"ok","output":[1],"error":true}`))
+
+	// Act
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected any 2xx to be a success without a success check, got %v", err)
+	}
+}
+
+func TestLoadConfig_RejectsInvalidSuccessCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		check string
+	}{
+		{"no field", "  error_values: [true]\n"},
+		{"no values", "  field: error\n"},
+		{"both values", "  field: error\n  success_values: [false]\n  error_values: [true]\n"},
+		{"non-scalar value", "  field: error\n  error_values: [[true]]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadConfig(writeConfig(t, "success_check:\n"+tt.check)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+// backendTensor is one entry of the "outputs" array that multi-tensor
+// backends such as KServe v2 and Triton send. Data holds the values
+// flattened in row-major order; an empty Shape is a scalar.
+type backendTensor struct {
+	Name     string    `json:"name"`
+	Shape    []int64   `json:"shape"`
+	Datatype string    `json:"datatype,omitempty"`
+	Data     []float64 `json:"data"`
+}
+
+// setTensors fills r.Outputs with the flat data of each tensor in a
+// multi-tensor "outputs" array, keyed by name, and r.Shapes with its shape.
+// Each tensor needs a unique name and exactly as many values as its shape
+// holds.
+func (r *APIResponse) setTensors(data []byte) error {
+	var tensors []backendTensor
+	if err := json.Unmarshal(data, &tensors); err != nil {
+		return fmt.Errorf("outputs must be an array of tensors with numeric data: %w", err)
+	}
+	r.Outputs = make(map[string][]float64, len(tensors))
+	r.Shapes = make(map[string][]int64, len(tensors))
+	for i, t := range tensors {
+		if t.Name == "" {
+			return fmt.Errorf("outputs[%d] has no name", i)
+		}
+		if _, ok := r.Outputs[t.Name]; ok {
+			return fmt.Errorf("outputs has more than one tensor named %q", t.Name)
+		}
+		size := int64(1)
+		for _, dim := range t.Shape {
+			if dim < 0 {
+				return fmt.Errorf("tensor %q has a negative dimension in shape %v", t.Name, t.Shape)
+			}
+			// Checked before multiplying so huge dimensions can't overflow
+			// into a size that matches the data.
+			if dim > 0 && size > math.MaxInt64/dim {
+				return fmt.Errorf("tensor %q has shape %v, which holds more values than can be sent", t.Name, t.Shape)
+			}
+			size *= dim
+		}
+		if size != int64(len(t.Data)) {
+			return fmt.Errorf("tensor %q has shape %v, which holds %d values, but %d were sent", t.Name, t.Shape, size, len(t.Data))
+		}
+		if t.Data == nil {
+			t.Data = []float64{}
+		}
+		if t.Shape == nil {
+			t.Shape = []int64{}
+		}
+		r.Outputs[t.Name] = t.Data
+		r.Shapes[t.Name] = t.Shape
+	}
+	return nil
+}
+
+// tensorShapes converts the shapes of a multi-tensor response for
+// PredictResponse.OutputShapes. It returns nil when there are none.
+func tensorShapes(shapes map[string][]int64) map[string]*pb.TensorShape {
+	if len(shapes) == 0 {
+		return nil
+	}
+	out := make(map[string]*pb.TensorShape, len(shapes))
+	for name, dims := range shapes {
+		out[name] = &pb.TensorShape{Dims: dims}
+	}
+	return out
+}
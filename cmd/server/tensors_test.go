@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const multiTensorBody = `{
+	"model_name": "m",
+	"status": "ok",
+	"outputs": [
+		{"name": "logits", "shape": [2, 3], "datatype": "FP32", "data": [1, 2, 3, 4, 5, 6]},
+		{"name": "count", "shape": [], "datatype": "INT64", "data": [2]}
+	]
+}`
+
+func TestAPIResponse_UnmarshalTensors(t *testing.T) {
+	// Act
+	var resp APIResponse
+	if err := json.Unmarshal([]byte(multiTensorBody), &resp); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	// Assert
+	if resp.Output != nil {
+		t.Errorf("Expected no single output, got %v", resp.Output)
+	}
+	if got, want := mustJSON(t, resp.Outputs), `{"count":[2],"logits":[1,2,3,4,5,6]}`; got != want {
+		t.Errorf("Expected Outputs %s, got %s", want, got)
+	}
+	if got, want := mustJSON(t, resp.Shapes), `{"count":[],"logits":[2,3]}`; got != want {
+		t.Errorf("Expected Shapes %s, got %s", want, got)
+	}
+}
+
+func TestAPIResponse_TensorsSurviveCaching(t *testing.T) {
+	var resp APIResponse
+	if err := json.Unmarshal([]byte(multiTensorBody), &resp); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	var cached APIResponse
+	if err := json.Unmarshal([]byte(mustJSON(t, resp)), &cached); err != nil {
+		t.Fatalf("Unmarshal of the cached entry returned error: %v", err)
+	}
+
+	if got, want := mustJSON(t, cached), mustJSON(t, resp); got != want {
+		t.Errorf("Expected the cached entry %s, got %s", want, got)
+	}
+}
+
+func TestAPIResponse_RejectsMalformedTensors(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"shape mismatch", `{"outputs":[{"name":"a","shape":[2,2],"data":[1,2,3]}]}`, "holds 4 values, but 3 were sent"},
+		{"negative dimension", `{"outputs":[{"name":"a","shape":[-1],"data":[1]}]}`, "negative dimension"},
+		{"overflowing shape", `{"outputs":[{"name":"a","shape":[4294967296,4294967296,2],"data":[]}]}`, "more values than can be sent"},
+		{"missing name", `{"outputs":[{"shape":[1],"data":[1]}]}`, "outputs[0] has no name"},
+		{"duplicate name", `{"outputs":[{"name":"a","shape":[1],"data":[1]},{"name":"a","shape":[1],"data":[2]}]}`, "more than one tensor"},
+		{"non-numeric data", `{"outputs":[{"name":"a","shape":[1],"data":["x"]}]}`, "numeric data"},
+		{"with output", `{"output":[1],"outputs":[{"name":"a","shape":[1],"data":[1]}]}`, "cannot both be set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp APIResponse
+
+			err := json.Unmarshal([]byte(tt.body), &resp)
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPredict_MultiTensorOutputs(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, staticBackend(multiTensorBody))
+
+	// Act
+	resp, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if len(resp.GetOutputData()) != 0 {
+		t.Errorf("Expected no OutputData for a multi-tensor model, got %s", resp.GetOutputData())
+	}
+	if got := string(resp.GetOutputs()["logits"]); got != "[1,2,3,4,5,6]" {
+		t.Errorf("Expected the flat logits [1,2,3,4,5,6], got %s", got)
+	}
+	shapes := resp.GetOutputShapes()
+	if got := shapes["logits"].GetDims(); !slices.Equal(got, []int64{2, 3}) {
+		t.Errorf("Expected logits shape [2 3], got %v", got)
+	}
+	if shape, ok := shapes["count"]; !ok || len(shape.GetDims()) != 0 {
+		t.Errorf("Expected count to be a scalar, got %v", shape)
+	}
+}
+
+func TestPredict_MultiTensorShapeMismatch(t *testing.T) {
+	s := newTestServer(t, staticBackend(`{"status":"ok","outputs":[{"name":"a","shape":[3],"data":[1,2]}]}`))
+
+	_, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	if status.Code(err) != codes.Internal {
+		t.Errorf("Expected Internal for a malformed backend response, got %v", err)
+	}
+}
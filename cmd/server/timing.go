@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parseServerTiming extracts the dur of each metric in Server-Timing header
+// values, e.g. `queue;dur=1.5, compute;desc="GPU";dur=20`. Metrics without
+// a valid dur are skipped. It returns nil when nothing was parsed.
+func parseServerTiming(values []string) map[string]float64 {
+	var timings map[string]float64
+	for _, value := range values {
+		for _, metric := range splitUnquoted(value, ',') {
+			params := splitUnquoted(metric, ';')
+			name := strings.TrimSpace(params[0])
+			if name == "" {
+				continue
+			}
+			for _, param := range params[1:] {
+				key, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "dur") {
+					continue
+				}
+				dur, err := strconv.ParseFloat(strings.Trim(strings.TrimSpace(val), `"`), 64)
+				if err != nil {
+					continue
+				}
+				if timings == nil {
+					timings = make(map[string]float64)
+				}
+				timings[name] = dur
+			}
+		}
+	}
+	return timings
+}
+
+// splitUnquoted splits s at sep, ignoring separators inside double quotes.
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			i++
+		case sep:
+			if !quoted {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
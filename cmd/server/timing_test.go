@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+func TestParseServerTiming(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   map[string]float64
+	}{
+		{"absent", nil, nil},
+		{"single", []string{"compute;dur=20.5"}, map[string]float64{"compute": 20.5}},
+		{
+			"several metrics and headers",
+			[]string{`queue;dur=1.5, compute;desc="GPU, batch 4";dur=20`, "postprocess;dur=0.25"},
+			map[string]float64{"queue": 1.5, "compute": 20, "postprocess": 0.25},
+		},
+		{"quoted dur", []string{`compute;dur="7"`}, map[string]float64{"compute": 7}},
+		{"metrics without dur skipped", []string{"cache;desc=hit, compute;dur=bad"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseServerTiming(tt.values); !maps.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPredict_BackendTimings(t *testing.T) {
+	// Arrange
+	withTiming := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server-Timing", `queue;dur=2, compute;desc="onnx";dur=15.5, postprocess;dur=0.5`)
+		w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok"}`))
+	})
+	req := &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}
+
+	// Act
+	resp, err := withTiming.Predict(context.Background(), req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	want := map[string]float64{"queue": 2, "compute": 15.5, "postprocess": 0.5}
+	if got := resp.GetBackendTimingsMs(); !maps.Equal(got, want) {
+		t.Errorf("Expected timings %v, got %v", want, got)
+	}
+
+	// Without the header the field is omitted.
+	plain := newTestServer(t, staticBackend(`{"model_name":"m","output":[1],"status":"ok"}`))
+	resp, err = plain.Predict(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if resp.GetBackendTimingsMs() != nil {
+		t.Errorf("Expected no timings without a Server-Timing header, got %v", resp.GetBackendTimingsMs())
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// certPollInterval is how often waitForFiles checks for missing files.
+const certPollInterval = 250 * time.Millisecond
+
+// tlsVersions maps -tls-min-version values to protocol versions.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newTLSConfig returns the server TLS settings for -tls-min-version and
+// -tls-cipher-suites. cipherSuites is a comma-separated list of Go cipher
+// suite names, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256; empty uses Go's
+// defaults. Only suites Go considers secure are accepted. TLS 1.3 suites
+// are not configurable, so a list is rejected when the minimum is 1.3.
+func newTLSConfig(minVersion, cipherSuites string) (*tls.Config, error) {
+	version, ok := tlsVersions[minVersion]
+	if !ok {
+		return nil, fmt.Errorf("unsupported -tls-min-version %q (want 1.0, 1.1, 1.2 or 1.3)", minVersion)
+	}
+	cfg := &tls.Config{MinVersion: version}
+	if cipherSuites == "" {
+		return cfg, nil
+	}
+	if version == tls.VersionTLS13 {
+		return nil, errors.New("-tls-cipher-suites has no effect with -tls-min-version 1.3")
+	}
+	secure := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		secure[suite.Name] = suite.ID
+	}
+	for name := range strings.SplitSeq(cipherSuites, ",") {
+		name = strings.TrimSpace(name)
+		id, ok := secure[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %q", name)
+		}
+		cfg.CipherSuites = append(cfg.CipherSuites, id)
+	}
+	return cfg, nil
+}
+
+// loadTLSCredentials waits up to wait for the certificate and key files to
+// exist and parse, then loads them into a copy of base. Cert managers often
+// mount the files shortly after the container starts, so a missing file
+// isn't fatal right away.
+func loadTLSCredentials(certFile, keyFile string, wait time.Duration, base *tls.Config) (credentials.TransportCredentials, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both -tls-cert and -tls-key are required")
+	}
+	deadline := time.Now().Add(wait)
+	if err := waitForFiles(wait, certPollInterval, certFile, keyFile); err != nil {
+		return nil, err
+	}
+	// The files may still be partially written; retry until the deadline.
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	for err != nil && time.Now().Add(certPollInterval).Before(deadline) {
+		time.Sleep(certPollInterval)
+		cert, err = tls.LoadX509KeyPair(certFile, keyFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading TLS key pair: %w", err)
+	}
+	cfg := base.Clone()
+	cfg.Certificates = []tls.Certificate{cert}
+	return credentials.NewTLS(cfg), nil
+}
+
+// waitForFiles polls every interval until all paths exist or timeout
+// elapses. Errors other than the file not existing fail immediately.
+func waitForFiles(timeout, interval time.Duration, paths ...string) error {
+	deadline := time.Now().Add(timeout)
+	logged := false
+	for {
+		missing, err := firstMissing(paths)
+		if err != nil {
+			return err
+		}
+		if missing == "" {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s did not appear within %v", missing, timeout)
+		}
+		if !logged {
+			log.Printf("Waiting up to %v for %s to appear", timeout, missing)
+			logged = true
+		}
+		time.Sleep(interval)
+	}
+}
+
+// firstMissing returns the first path that does not exist yet, or "".
+func firstMissing(paths []string) (string, error) {
+	for _, path := range paths {
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return path, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCert writes a throwaway certificate and key to the given
+// paths.
+func writeSelfSignedCert(t *testing.T, certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTLSCredentials_WaitsForFiles(t *testing.T) {
+	// Arrange: the cert manager writes the files shortly after startup.
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		writeSelfSignedCert(t, certPath, keyPath)
+	}()
+
+	// Act
+	start := time.Now()
+	creds, err := loadTLSCredentials(certPath, keyPath, 5*time.Second, &tls.Config{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected credentials once the files appeared, got %v", err)
+	}
+	if creds.Info().SecurityProtocol != "tls" {
+		t.Errorf("Expected TLS credentials, got %q", creds.Info().SecurityProtocol)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("Expected to wait for the files, returned after %v", elapsed)
+	}
+}
+
+func TestLoadTLSCredentials_Errors(t *testing.T) {
+	dir := t.TempDir()
+	garbage := filepath.Join(dir, "garbage.pem")
+	os.WriteFile(garbage, []byte("not a cert"), 0o600)
+
+	tests := []struct {
+		name      string
+		cert, key string
+	}{
+		{"files never appear", filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key")},
+		{"key without cert", "", filepath.Join(dir, "tls.key")},
+		{"invalid contents", garbage, garbage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadTLSCredentials(tt.cert, tt.key, 50*time.Millisecond, &tls.Config{}); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		minVersion  string
+		ciphers     string
+		wantVersion uint16
+		wantCiphers []uint16
+		wantErr     bool
+	}{
+		{"default", "1.2", "", tls.VersionTLS12, nil, false},
+		{"tls 1.3", "1.3", "", tls.VersionTLS13, nil, false},
+		{"cipher allowlist", "1.2", "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384", tls.VersionTLS12,
+			[]uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, false},
+		{"invalid version", "TLS12", "", 0, nil, true},
+		{"unknown cipher", "1.2", "TLS_MADE_UP", 0, nil, true},
+		{"insecure cipher", "1.2", "TLS_RSA_WITH_RC4_128_SHA", 0, nil, true},
+		{"ciphers with tls 1.3", "1.3", "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := newTLSConfig(tt.minVersion, tt.ciphers)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newTLSConfig failed: %v", err)
+			}
+			if cfg.MinVersion != tt.wantVersion {
+				t.Errorf("Expected MinVersion %x, got %x", tt.wantVersion, cfg.MinVersion)
+			}
+			if !slices.Equal(cfg.CipherSuites, tt.wantCiphers) {
+				t.Errorf("Expected cipher suites %v, got %v", tt.wantCiphers, cfg.CipherSuites)
+			}
+		})
+	}
+}
+
+func TestLoadTLSCredentials_EnforcesMinVersion(t *testing.T) {
+	// Arrange: the server requires TLS 1.3; the client offers at most 1.2.
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeSelfSignedCert(t, certPath, keyPath)
+	base, err := newTLSConfig("1.3", "")
+	if err != nil {
+		t.Fatalf("newTLSConfig failed: %v", err)
+	}
+	creds, err := loadTLSCredentials(certPath, keyPath, time.Second, base)
+	if err != nil {
+		t.Fatalf("loadTLSCredentials failed: %v", err)
+	}
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	go func() {
+		client := tls.Client(clientConn, &tls.Config{InsecureSkipVerify: true, MaxVersion: tls.VersionTLS12})
+		client.Handshake()
+		clientConn.Close()
+	}()
+
+	// Act
+	_, _, err = creds.ServerHandshake(serverConn)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected a TLS 1.2 client to be rejected")
+	}
+	if base.Certificates != nil {
+		t.Error("Expected the base config to be left untouched")
+	}
+}
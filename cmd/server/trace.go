@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	connectBuckets = prometheus.ExponentialBuckets(0.0001, 4, 10)
+
+	backendDNSDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "inference_backend_dns_duration_seconds",
+			Help:    "DNS lookup time for new backend connections (seconds)",
+			Buckets: connectBuckets,
+		},
+	)
+	backendConnectDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "inference_backend_connect_duration_seconds",
+			Help:    "TCP connect time for new backend connections (seconds)",
+			Buckets: connectBuckets,
+		},
+	)
+	backendTLSDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "inference_backend_tls_handshake_duration_seconds",
+			Help:    "TLS handshake time for new backend connections (seconds)",
+			Buckets: connectBuckets,
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(backendDNSDuration, backendConnectDuration, backendTLSDuration)
+}
+
+// withConnectionTrace returns ctx with an httptrace hook recording DNS,
+// connect and TLS handshake durations of the request's connection setup.
+// Requests served over a pooled connection record nothing, so the
+// histograms only count connections that had to be established.
+func withConnectionTrace(ctx context.Context) context.Context {
+	var dnsStart, tlsStart time.Time
+	// Dual-stack dials may connect to several addresses concurrently.
+	var mu sync.Mutex
+	connectStarts := make(map[string]time.Time)
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			if info.Err == nil {
+				backendDNSDuration.Observe(time.Since(dnsStart).Seconds())
+			}
+		},
+		ConnectStart: func(network, addr string) {
+			mu.Lock()
+			connectStarts[network+" "+addr] = time.Now()
+			mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			mu.Lock()
+			start := connectStarts[network+" "+addr]
+			mu.Unlock()
+			if err == nil {
+				backendConnectDuration.Observe(time.Since(start).Seconds())
+			}
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				backendTLSDuration.Observe(time.Since(tlsStart).Seconds())
+			}
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestPredict_ConnectionMetrics(t *testing.T) {
+	// Arrange: a TLS backend addressed by hostname, so a new connection
+	// needs a DNS lookup, a TCP connect and a TLS handshake.
+	backend := httptest.NewTLSServer(staticBackend(`{"model_name":"m","output":[1],"status":"ok"}`))
+	t.Cleanup(backend.Close)
+	t.Setenv("MODEL_SERVER_URL", strings.Replace(backend.URL, "127.0.0.1", "localhost", 1))
+	client := backend.Client()
+	// The test certificate is issued for example.com, not localhost.
+	client.Transport.(*http.Transport).TLSClientConfig.ServerName = "example.com"
+	s := &server{httpClient: client, traceConnections: true}
+
+	histograms := map[string]prometheus.Histogram{
+		"dns":     backendDNSDuration,
+		"connect": backendConnectDuration,
+		"tls":     backendTLSDuration,
+	}
+	before := make(map[string]uint64)
+	for name, h := range histograms {
+		before[name] = histogramCount(t, h)
+	}
+
+	// Act: the second call reuses the pooled connection.
+	for range 2 {
+		if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}); err != nil {
+			t.Fatalf("Predict returned error: %v", err)
+		}
+	}
+
+	// Assert
+	for name, h := range histograms {
+		if got := histogramCount(t, h) - before[name]; got < 1 {
+			t.Errorf("Expected the %s histogram to be observed, got %d observations", name, got)
+		}
+	}
+	if got := histogramCount(t, backendTLSDuration) - before["tls"]; got != 1 {
+		t.Errorf("Expected one TLS handshake for two calls over a pooled connection, got %d", got)
+	}
+}
+
+func TestPredict_ConnectionMetricsDisabled(t *testing.T) {
+	backend := httptest.NewServer(staticBackend(`{"model_name":"m","output":[1],"status":"ok"}`))
+	t.Cleanup(backend.Close)
+	t.Setenv("MODEL_SERVER_URL", backend.URL)
+	s := &server{httpClient: backend.Client()}
+	before := histogramCount(t, backendConnectDuration)
+
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}); err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+
+	if got := histogramCount(t, backendConnectDuration) - before; got != 0 {
+		t.Errorf("Expected no connect observations without the flag, got %d", got)
+	}
+}
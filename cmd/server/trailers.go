@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// parseTrailerHeaders splits a comma-separated -backend-trailer-headers
+// value into canonical header names.
+func parseTrailerHeaders(s string) []string {
+	var names []string
+	for name := range strings.SplitSeq(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, http.CanonicalHeaderKey(name))
+		}
+	}
+	return names
+}
+
+// pickHeaders returns the headers in h named in allow, or nil when none of
+// them were sent.
+func pickHeaders(allow []string, h http.Header) http.Header {
+	var picked http.Header
+	for _, name := range allow {
+		if values := h.Values(name); len(values) > 0 {
+			if picked == nil {
+				picked = make(http.Header, len(allow))
+			}
+			picked[name] = values
+		}
+	}
+	return picked
+}
+
+// headerTrailer converts backend headers into gRPC trailer metadata keyed by
+// the lower-case header name.
+func headerTrailer(h http.Header) metadata.MD {
+	md := make(metadata.MD, len(h))
+	for name, values := range h {
+		md.Append(name, values...)
+	}
+	return md
+}
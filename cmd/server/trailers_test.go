@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestPredict_ReturnsBackendHeadersAsTrailers(t *testing.T) {
+	// Arrange
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Model-Instance-Id", "gpu-7")
+		w.Header().Set("Cache-Status", "miss")
+		w.Header().Set("X-Internal", "secret")
+		staticBackend(`{"status":"ok","output":[1]}`)(w, r)
+	})
+	s.trailerHeaders = parseTrailerHeaders("model-instance-id, cache-status,absent")
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterInferenceServer(grpcServer, s)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	// Act
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var trailer metadata.MD
+	_, err = pb.NewInferenceClient(conn).Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}, grpc.Trailer(&trailer))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+	want := map[string]string{"model-instance-id": "gpu-7", "cache-status": "miss"}
+	for key, value := range want {
+		if got := trailer.Get(key); len(got) != 1 || got[0] != value {
+			t.Errorf("Expected trailer %s: %s, got %v", key, value, got)
+		}
+	}
+	for _, key := range []string{"x-internal", "absent"} {
+		if got := trailer.Get(key); len(got) != 0 {
+			t.Errorf("Expected no %s trailer, got %v", key, got)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// modelVersionHeader is the gRPC metadata key clients use to pin a model
+	// version.
+	modelVersionHeader = "x-model-version"
+	// backendModelVersionHeader forwards the pinned version to the backend.
+	backendModelVersionHeader = "X-Model-Version"
+)
+
+// pinnedVersion returns the model version requested through metadata, or ""
+// when the client did not pin one.
+func pinnedVersion(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return firstValue(md, modelVersionHeader)
+}
+
+// checkPinnedVersion returns FailedPrecondition when a version was pinned and
+// the backend served a different (or unreported) one.
+func checkPinnedVersion(pinned, served string) error {
+	if pinned == "" || pinned == served {
+		return nil
+	}
+	return status.Errorf(codes.FailedPrecondition, "requested model version %q but backend served %q", pinned, served)
+}
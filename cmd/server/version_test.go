@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// versionedBackend serves the given version and records the version header
+// it received.
+func versionedBackend(served string, received *string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*received = r.Header.Get(backendModelVersionHeader)
+		w.Write([]byte(`{"model_name":"m","output":[1],"status":"ok","version":"` + served + `"}`))
+	}
+}
+
+func TestPredict_PinnedVersionMatches(t *testing.T) {
+	var received string
+	s := newTestServer(t, versionedBackend("v2", &received))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(modelVersionHeader, "v2"))
+
+	resp, err := s.Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	if err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if received != "v2" {
+		t.Errorf("Expected pinned version to be forwarded to the backend, got %q", received)
+	}
+	if resp.ModelVersion != "v2" {
+		t.Errorf("Expected model version v2, got %q", resp.ModelVersion)
+	}
+}
+
+func TestPredict_PinnedVersionMismatch(t *testing.T) {
+	var received string
+	s := newTestServer(t, versionedBackend("v3", &received))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(modelVersionHeader, "v2"))
+
+	_, err := s.Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition on version mismatch, got %v", err)
+	}
+}
+
+func TestPredict_PinnedVersionMismatchNotCached(t *testing.T) {
+	// Arrange
+	var received string
+	s := newTestServer(t, versionedBackend("v3", &received))
+	store := newFakeCache()
+	s.cache = newPredictionCache(store, time.Minute)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(modelVersionHeader, "v2"))
+
+	// Act
+	_, err := s.Predict(ctx, &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)})
+
+	// Assert
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected FailedPrecondition on version mismatch, got %v", err)
+	}
+	if len(store.values) != 0 {
+		t.Errorf("Expected the mismatched response not to be cached, got %d entries", len(store.values))
+	}
+}
+
+func TestPredict_UnpinnedAcceptsAnyVersion(t *testing.T) {
+	var received string
+	s := newTestServer(t, versionedBackend("v3", &received))
+
+	if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}); err != nil {
+		t.Fatalf("Predict returned error: %v", err)
+	}
+	if received != "" {
+		t.Errorf("Expected no version header without pinning, got %q", received)
+	}
+}
+
+func TestCheckPinnedVersion(t *testing.T) {
+	if err := checkPinnedVersion("", "v1"); err != nil {
+		t.Errorf("Expected no error without a pin, got %v", err)
+	}
+	if err := checkPinnedVersion("v1", ""); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition when the backend reports no version, got %v", err)
+	}
+}
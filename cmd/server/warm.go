@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// warmUpTimeout bounds the startup warm-up, so an unreachable backend can't
+// keep the server not ready forever, even with -backend-timeout=0.
+const warmUpTimeout = 30 * time.Second
+
+// keepWarm pings the backend every interval until ctx is done, so pooled
+// connections stay open and the first request after an idle period doesn't
+// pay for a new connection. A non-positive interval disables it.
+func (s *server) keepWarm(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, interval)
+			if err := s.checkBackend(pingCtx); err != nil && ctx.Err() == nil {
+				log.Printf("Backend keepalive ping failed: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// warmConnections sends n concurrent probe requests to the backend so that n
+// connections are open and idle in the pool before the first burst of
+// predictions, which then skips connection setup. It clears s.warming when
+// done, whether or not the probes succeeded, and returns how many did.
+// Probes still running after timeout are abandoned. Over HTTP/2 the probes
+// share a single connection.
+func (s *server) warmConnections(ctx context.Context, n int, timeout time.Duration) int {
+	defer s.warming.Store(false)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	var warmed atomic.Int32
+	for range n {
+		wg.Go(func() {
+			if err := s.checkBackend(ctx); err != nil {
+				log.Printf("Backend warm-up request failed: %v", err)
+				return
+			}
+			warmed.Add(1)
+		})
+	}
+	wg.Wait()
+	log.Printf("Warmed %d of %d backend connections", warmed.Load(), n)
+	return int(warmed.Load())
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+)
+
+func TestKeepWarm_PingsAtInterval(t *testing.T) {
+	// Arrange
+	var mu sync.Mutex
+	var pings []time.Time
+	remotes := make(map[string]bool)
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		pings = append(pings, time.Now())
+		remotes[r.RemoteAddr] = true
+		w.Write([]byte(`{"Status":"up"}`))
+	})
+
+	// Act
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	start := time.Now()
+	go func() {
+		s.keepWarm(ctx, 20*time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(110 * time.Millisecond)
+	cancel()
+	<-done
+
+	// Assert
+	mu.Lock()
+	defer mu.Unlock()
+	if len(pings) < 3 || len(pings) > 6 {
+		t.Fatalf("Expected about 5 pings at a 20ms interval over 110ms, got %d", len(pings))
+	}
+	if first := pings[0].Sub(start); first < 15*time.Millisecond {
+		t.Errorf("Expected the first ping after one interval, got it after %v", first)
+	}
+	if len(remotes) != 1 {
+		t.Errorf("Expected pings to reuse one pooled connection, used %d", len(remotes))
+	}
+}
+
+func TestKeepWarm_Disabled(t *testing.T) {
+	s := &server{}
+	done := make(chan struct{})
+	go func() {
+		s.keepWarm(context.Background(), 0)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected keepWarm to return immediately when disabled")
+	}
+}
+
+func TestWarmConnections_OpensPoolBeforeReady(t *testing.T) {
+	// Arrange
+	const poolSize = 4
+	var conns atomic.Int32
+	arrived := make(chan struct{}, poolSize)
+	release := make(chan struct{})
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hold every request until poolSize are in flight, so each needs
+		// its own connection.
+		arrived <- struct{}{}
+		<-release
+		w.Write([]byte(`{"output":[1],"status":"ok"}`))
+	}))
+	backend.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	backend.Start()
+	t.Cleanup(backend.Close)
+	t.Setenv("MODEL_SERVER_URL", backend.URL)
+	s := &server{httpClient: newBackendClient(backendDialer(0), false, false, poolSize)}
+	s.serving.Store(true)
+	s.warming.Store(true)
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		s.readyHandler(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return rec.Code
+	}
+
+	// Act
+	done := make(chan int)
+	go func() { done <- s.warmConnections(context.Background(), poolSize, 5*time.Second) }()
+	for range poolSize {
+		<-arrived
+	}
+	readyWhileWarming := ready()
+	close(release)
+	warmed := <-done
+
+	// Assert
+	if readyWhileWarming != http.StatusServiceUnavailable {
+		t.Errorf("Expected not ready while warming, got %d", readyWhileWarming)
+	}
+	if warmed != poolSize || conns.Load() != poolSize {
+		t.Fatalf("Expected %d warm connections, warmed %d and opened %d", poolSize, warmed, conns.Load())
+	}
+	if got := ready(); got != http.StatusOK {
+		t.Errorf("Expected ready after warming, got %d", got)
+	}
+
+	// A burst of poolSize concurrent predictions reuses the warm pool.
+	var wg sync.WaitGroup
+	for range poolSize {
+		wg.Go(func() {
+			if _, err := s.Predict(context.Background(), &pb.PredictRequest{ModelName: "m", InputData: []byte(`[1]`)}); err != nil {
+				t.Errorf("Predict returned error: %v", err)
+			}
+		})
+	}
+	for range poolSize {
+		<-arrived
+	}
+	wg.Wait()
+	if got := conns.Load(); got != poolSize {
+		t.Errorf("Expected the burst to reuse the %d warm connections, %d were opened in total", poolSize, got)
+	}
+}
+
+func TestWarmConnections_TimesOutOnHungBackend(t *testing.T) {
+	// Arrange: a backend that never answers.
+	s := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	})
+	s.warming.Store(true)
+
+	// Act
+	done := make(chan int)
+	go func() { done <- s.warmConnections(context.Background(), 2, 50*time.Millisecond) }()
+
+	// Assert
+	select {
+	case warmed := <-done:
+		if warmed != 0 {
+			t.Errorf("Expected no warmed connections, got %d", warmed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the warm-up to give up after its timeout")
+	}
+	if s.warming.Load() {
+		t.Error("Expected warming to be cleared after the timeout")
+	}
+}
@@ -0,0 +1,138 @@
+// Package quantize encodes model outputs at reduced precision to shrink
+// responses, and decodes them again on the client.
+package quantize
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Supported encodings.
+const (
+	Float16 = "float16"
+	Int8    = "int8"
+)
+
+// Valid reports whether encoding is a supported quantization.
+func Valid(encoding string) bool {
+	return encoding == Float16 || encoding == Int8
+}
+
+// EncodeFloat16 packs values as little-endian IEEE 754 half precision
+// floats, two bytes per value. Values beyond the half range become ±Inf.
+func EncodeFloat16(values []float64) []byte {
+	out := make([]byte, 2*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(out[2*i:], toHalf(v))
+	}
+	return out
+}
+
+// DecodeFloat16 unpacks values written by EncodeFloat16.
+func DecodeFloat16(data []byte) ([]float64, error) {
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("float16 data has odd length %d", len(data))
+	}
+	out := make([]float64, len(data)/2)
+	for i := range out {
+		out[i] = fromHalf(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+	return out, nil
+}
+
+// EncodeInt8 maps values linearly onto signed bytes, one per value, and
+// returns the scale and zero point needed to decode them. The range always
+// includes zero so that zero is represented exactly.
+func EncodeInt8(values []float64) (data []byte, scale float64, zeroPoint int32) {
+	lo, hi := 0.0, 0.0
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	scale = (hi - lo) / 255
+	if scale == 0 {
+		scale = 1
+	}
+	zeroPoint = int32(clamp(math.Round(-128-lo/scale), -128, 127))
+
+	data = make([]byte, len(values))
+	for i, v := range values {
+		q := clamp(math.Round(v/scale)+float64(zeroPoint), -128, 127)
+		data[i] = byte(int8(q))
+	}
+	return data, scale, zeroPoint
+}
+
+// DecodeInt8 reverses EncodeInt8.
+func DecodeInt8(data []byte, scale float64, zeroPoint int32) []float64 {
+	out := make([]float64, len(data))
+	for i, b := range data {
+		out[i] = scale * float64(int32(int8(b))-zeroPoint)
+	}
+	return out
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// toHalf converts v to IEEE 754 binary16, rounding to nearest even.
+func toHalf(v float64) uint16 {
+	bits := math.Float32bits(float32(v))
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23&0xff) - 127 + 15
+	mant := bits & 0x7fffff
+
+	switch {
+	case bits&0x7fffffff == 0:
+		return sign
+	case bits>>23&0xff == 0xff: // Inf or NaN
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	case exp >= 0x1f: // overflow
+		return sign | 0x7c00
+	case exp <= 0: // subnormal or underflow
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := uint16(mant >> shift)
+		rem := mant & (1<<shift - 1)
+		mid := uint32(1) << (shift - 1)
+		if rem > mid || (rem == mid && half&1 == 1) {
+			half++
+		}
+		return sign | half
+	}
+
+	half := uint16(exp)<<10 | uint16(mant>>13)
+	rem := mant & 0x1fff
+	if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		half++ // may carry into the exponent, which is still correct
+	}
+	return sign | half
+}
+
+// fromHalf converts IEEE 754 binary16 bits to a float64.
+func fromHalf(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h >> 10 & 0x1f)
+	mant := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant != 0 {
+			return math.NaN()
+		}
+		return math.Inf(int(sign))
+	}
+	return sign * math.Ldexp(1+mant/1024, exp-15)
+}
@@ -0,0 +1,84 @@
+package quantize
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloat16_RoundTrip(t *testing.T) {
+	values := []float64{0, 1, -1, 0.1, 3.14159, -2.71828, 1e-3, 123.456, -65504, 1e-6}
+
+	decoded, err := DecodeFloat16(EncodeFloat16(values))
+	if err != nil {
+		t.Fatalf("DecodeFloat16 returned error: %v", err)
+	}
+
+	for i, want := range values {
+		// Half precision keeps 11 significant bits; subnormals have an
+		// absolute step of 2^-24.
+		tolerance := math.Max(math.Abs(want)/1024, math.Ldexp(1, -24))
+		if diff := math.Abs(decoded[i] - want); diff > tolerance {
+			t.Errorf("value %d: %v decoded as %v, off by %v (tolerance %v)", i, want, decoded[i], diff, tolerance)
+		}
+	}
+}
+
+func TestFloat16_Bits(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want uint16
+	}{
+		{0, 0x0000},
+		{math.Copysign(0, -1), 0x8000},
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{65504, 0x7bff},
+		{1e6, 0x7c00},
+		{math.Inf(-1), 0xfc00},
+		{math.Ldexp(1, -24), 0x0001},
+		{math.Ldexp(1, -26), 0x0000},
+	}
+	for _, tt := range tests {
+		if got := toHalf(tt.in); got != tt.want {
+			t.Errorf("toHalf(%v) = %#04x, expected %#04x", tt.in, got, tt.want)
+		}
+	}
+	if !math.IsNaN(fromHalf(toHalf(math.NaN()))) {
+		t.Error("Expected NaN to survive a round trip")
+	}
+}
+
+func TestDecodeFloat16_OddLength(t *testing.T) {
+	if _, err := DecodeFloat16([]byte{1, 2, 3}); err == nil {
+		t.Error("Expected an error for odd-length data")
+	}
+}
+
+func TestInt8_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+	}{
+		{"probabilities", []float64{0.01, 0.2, 0.5, 0.29}},
+		{"mixed signs", []float64{-3.5, -1, 0, 2.25, 7}},
+		{"all negative", []float64{-10, -5, -0.5}},
+		{"constant", []float64{4, 4, 4}},
+		{"zeros", []float64{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, scale, zeroPoint := EncodeInt8(tt.values)
+			if len(data) != len(tt.values) {
+				t.Fatalf("Expected one byte per value, got %d bytes", len(data))
+			}
+
+			decoded := DecodeInt8(data, scale, zeroPoint)
+
+			for i, want := range tt.values {
+				if diff := math.Abs(decoded[i] - want); diff > scale/2+1e-12 {
+					t.Errorf("value %d: %v decoded as %v, off by %v (scale %v)", i, want, decoded[i], diff, scale)
+				}
+			}
+		})
+	}
+}
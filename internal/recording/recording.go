@@ -0,0 +1,164 @@
+// Package recording defines the JSON-lines format used to capture
+// request/response pairs on the server and replay them from the client.
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Record is a single line of a recording file. Request and Response hold the
+// protojson encoding of the messages so the format follows the proto schema.
+type Record struct {
+	Time     time.Time       `json:"time"`
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Code     string          `json:"code"`
+	Message  string          `json:"message,omitempty"`
+}
+
+// NewRecord captures a completed call. resp may be nil when err is set.
+func NewRecord(method string, req, resp proto.Message, err error) (Record, error) {
+	rec := Record{
+		Time:   time.Now().UTC(),
+		Method: method,
+		Code:   status.Code(err).String(),
+	}
+	if err != nil {
+		rec.Message = status.Convert(err).Message()
+	}
+
+	reqJSON, mErr := protojson.Marshal(req)
+	if mErr != nil {
+		return rec, fmt.Errorf("encoding request: %w", mErr)
+	}
+	rec.Request = reqJSON
+
+	if resp != nil && err == nil {
+		respJSON, mErr := protojson.Marshal(resp)
+		if mErr != nil {
+			return rec, fmt.Errorf("encoding response: %w", mErr)
+		}
+		rec.Response = respJSON
+	}
+	return rec, nil
+}
+
+// DecodeRequest unmarshals the recorded request into m.
+func (r Record) DecodeRequest(m proto.Message) error {
+	return protojson.Unmarshal(r.Request, m)
+}
+
+// DecodeResponse unmarshals the recorded response into m. It returns false
+// when the call failed and no response was recorded.
+func (r Record) DecodeResponse(m proto.Message) (bool, error) {
+	if len(r.Response) == 0 {
+		return false, nil
+	}
+	return true, protojson.Unmarshal(r.Response, m)
+}
+
+// StatusCode returns the recorded gRPC status code.
+func (r Record) StatusCode() codes.Code {
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if c.String() == r.Code {
+			return c
+		}
+	}
+	return codes.Unknown
+}
+
+// Read parses every record from a JSON-lines stream.
+func Read(r io.Reader) ([]Record, error) {
+	var records []Record
+	dec := json.NewDecoder(r)
+	for {
+		var rec Record
+		if err := dec.Decode(&rec); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return records, fmt.Errorf("record %d: %w", len(records)+1, err)
+		}
+		records = append(records, rec)
+	}
+}
+
+// Writer appends records to an io.Writer from a background goroutine. Write
+// never blocks: when the buffer is full the record is dropped and counted.
+type Writer struct {
+	records chan Record
+	done    chan struct{}
+	dropped atomic.Int64
+
+	closeOnce sync.Once
+	err       error
+}
+
+// NewWriter starts a Writer buffering up to bufferSize pending records.
+func NewWriter(w io.Writer, bufferSize int) *Writer {
+	rw := &Writer{
+		records: make(chan Record, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go rw.run(w)
+	return rw
+}
+
+func (w *Writer) run(out io.Writer) {
+	defer close(w.done)
+	buf := bufio.NewWriter(out)
+	enc := json.NewEncoder(buf)
+	for rec := range w.records {
+		if err := enc.Encode(rec); err != nil && w.err == nil {
+			w.err = err
+		}
+		// Flush whenever we catch up so the file stays current without
+		// paying for a write per record under load.
+		if len(w.records) == 0 {
+			if err := buf.Flush(); err != nil && w.err == nil {
+				w.err = err
+			}
+		}
+	}
+	if err := buf.Flush(); err != nil && w.err == nil {
+		w.err = err
+	}
+}
+
+// Write queues rec and reports whether it was accepted.
+func (w *Writer) Write(rec Record) bool {
+	select {
+	case w.records <- rec:
+		return true
+	default:
+		w.dropped.Add(1)
+		return false
+	}
+}
+
+// Dropped returns the number of records discarded because the buffer was
+// full.
+func (w *Writer) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Close drains pending records, flushes them and returns the first write
+// error encountered. Write must not be called after Close.
+func (w *Writer) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.records)
+	})
+	<-w.done
+	return w.err
+}
@@ -0,0 +1,115 @@
+package recording
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	pb "github.com/arhantsg07/ml-inference-system/proto/inference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecordRoundTrip(t *testing.T) {
+	req := &pb.PredictRequest{ModelName: "sample", InputData: []byte(`[1,2,3]`)}
+	resp := &pb.PredictResponse{OutputData: []byte(`[0.5]`), Status: "ok"}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, 10)
+	ok, err := NewRecord(pb.Inference_Predict_FullMethodName, req, resp, nil)
+	if err != nil {
+		t.Fatalf("NewRecord returned error: %v", err)
+	}
+	failed, err := NewRecord(pb.Inference_Predict_FullMethodName, req, nil, status.Error(codes.InvalidArgument, "bad input"))
+	if err != nil {
+		t.Fatalf("NewRecord returned error: %v", err)
+	}
+	w.Write(ok)
+	w.Write(failed)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Fatalf("Expected 2 JSON lines, got %d:\n%s", lines, buf.String())
+	}
+
+	records, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	gotReq := &pb.PredictRequest{}
+	if err := records[0].DecodeRequest(gotReq); err != nil {
+		t.Fatalf("DecodeRequest returned error: %v", err)
+	}
+	if gotReq.ModelName != "sample" || string(gotReq.InputData) != "[1,2,3]" {
+		t.Errorf("Unexpected decoded request: %v", gotReq)
+	}
+	gotResp := &pb.PredictResponse{}
+	if present, err := records[0].DecodeResponse(gotResp); !present || err != nil {
+		t.Fatalf("Expected recorded response, got present=%v err=%v", present, err)
+	}
+	if string(gotResp.OutputData) != "[0.5]" || records[0].StatusCode() != codes.OK {
+		t.Errorf("Unexpected decoded response: %v (%s)", gotResp, records[0].Code)
+	}
+
+	if present, _ := records[1].DecodeResponse(&pb.PredictResponse{}); present {
+		t.Error("Expected no response for failed call")
+	}
+	if records[1].StatusCode() != codes.InvalidArgument || records[1].Message != "bad input" {
+		t.Errorf("Unexpected failed record: %+v", records[1])
+	}
+}
+
+// blockingWriter blocks every write until release is closed.
+type blockingWriter struct {
+	release chan struct{}
+}
+
+func (b *blockingWriter) Write(p []byte) (int, error) {
+	<-b.release
+	return len(p), nil
+}
+
+func TestWriterDropsWhenFull(t *testing.T) {
+	bw := &blockingWriter{release: make(chan struct{})}
+	// bufio only writes through once its own buffer fills, so use records
+	// large enough to force writes to the blocked writer.
+	w := NewWriter(bw, 1)
+	rec := Record{Method: strings.Repeat("x", 8192), Request: []byte(`{}`)}
+
+	accepted := 0
+	for range 10 {
+		if w.Write(rec) {
+			accepted++
+		}
+	}
+	if w.Dropped() == 0 {
+		t.Errorf("Expected some records to be dropped, accepted %d", accepted)
+	}
+	if int64(accepted)+w.Dropped() != 10 {
+		t.Errorf("Expected accepted+dropped to be 10, got %d+%d", accepted, w.Dropped())
+	}
+
+	close(bw.release)
+	if err := w.Close(); err != nil {
+		t.Errorf("Close returned error: %v", err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriterReportsWriteErrors(t *testing.T) {
+	w := NewWriter(failingWriter{}, 1)
+	w.Write(Record{Request: []byte(`{}`)})
+	if err := w.Close(); err == nil {
+		t.Error("Expected Close to report the write error")
+	}
+}
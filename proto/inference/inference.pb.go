@@ -22,9 +22,41 @@ const (
 )
 
 type PredictRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ModelName     string                 `protobuf:"bytes,1,opt,name=ModelName,proto3" json:"ModelName,omitempty"`
-	InputData     []byte                 `protobuf:"bytes,2,opt,name=InputData,proto3" json:"InputData,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ModelName string                 `protobuf:"bytes,1,opt,name=ModelName,proto3" json:"ModelName,omitempty"`
+	InputData []byte                 `protobuf:"bytes,2,opt,name=InputData,proto3" json:"InputData,omitempty"`
+	// PostProcess names a transform applied to the model output before it is
+	// returned. Supported values: "" (none) and "softmax".
+	PostProcess string `protobuf:"bytes,3,opt,name=PostProcess,proto3" json:"PostProcess,omitempty"`
+	// TopK, when positive, returns only the K most probable classes in
+	// TopKIndices/TopKProbabilities instead of the full output vector.
+	// Requires PostProcess to be "softmax".
+	TopK int32 `protobuf:"varint,4,opt,name=TopK,proto3" json:"TopK,omitempty"`
+	// Quantization shrinks OutputData by encoding it at reduced precision:
+	// "" (JSON, the default), "float16" or "int8". See OutputEncoding.
+	Quantization string `protobuf:"bytes,5,opt,name=Quantization,proto3" json:"Quantization,omitempty"`
+	// Params is an optional JSON object of model parameters (for example
+	// {"temperature": 0.7}) forwarded to the backend as is.
+	Params []byte `protobuf:"bytes,6,opt,name=Params,proto3" json:"Params,omitempty"`
+	// OutputFormat selects how OutputData is encoded: "" or "json" for a
+	// JSON array (the default), "ndjson" for one JSON number per line, or
+	// "float32-le"/"float64-le" for raw little-endian floats. The
+	// x-output-encoding metadata overrides it. Only "json" can be combined
+	// with Quantization.
+	OutputFormat string `protobuf:"bytes,7,opt,name=OutputFormat,proto3" json:"OutputFormat,omitempty"`
+	// CorrelationId is an optional client-supplied identifier (an order or
+	// transaction ID, say) that is logged, forwarded to the backend and
+	// echoed in the response. It can also be sent as x-correlation-id
+	// metadata; this field wins when both are set.
+	CorrelationId string `protobuf:"bytes,8,opt,name=CorrelationId,proto3" json:"CorrelationId,omitempty"`
+	// Precision, when set, rounds every output value to that many decimal
+	// places (0 rounds to integers) before OutputData is encoded. It must
+	// not be negative and cannot be combined with Quantization.
+	Precision *int32 `protobuf:"varint,9,opt,name=Precision,proto3,oneof" json:"Precision,omitempty"`
+	// InputShape, when set, gives the dimensions of a matrix or image input
+	// sent flattened in row-major order in InputData. Their product must be
+	// the number of input values. It is forwarded to the backend as "shape".
+	InputShape    []int64 `protobuf:"varint,10,rep,packed,name=InputShape,proto3" json:"InputShape,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,17 +105,190 @@ func (x *PredictRequest) GetInputData() []byte {
 	return nil
 }
 
-type PredictResponse struct {
+func (x *PredictRequest) GetPostProcess() string {
+	if x != nil {
+		return x.PostProcess
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetTopK() int32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
+}
+
+func (x *PredictRequest) GetQuantization() string {
+	if x != nil {
+		return x.Quantization
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetParams() []byte {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *PredictRequest) GetOutputFormat() string {
+	if x != nil {
+		return x.OutputFormat
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetPrecision() int32 {
+	if x != nil && x.Precision != nil {
+		return *x.Precision
+	}
+	return 0
+}
+
+func (x *PredictRequest) GetInputShape() []int64 {
+	if x != nil {
+		return x.InputShape
+	}
+	return nil
+}
+
+// PredictInputChunk carries part of an input too large for a single message.
+// The server concatenates the Data of all chunks into the JSON input; the
+// other fields are read from the first chunk only.
+type PredictInputChunk struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OutputData    []byte                 `protobuf:"bytes,1,opt,name=OutputData,proto3" json:"OutputData,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=Status,proto3" json:"Status,omitempty"`
+	ModelName     string                 `protobuf:"bytes,1,opt,name=ModelName,proto3" json:"ModelName,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+	PostProcess   string                 `protobuf:"bytes,3,opt,name=PostProcess,proto3" json:"PostProcess,omitempty"`
+	TopK          int32                  `protobuf:"varint,4,opt,name=TopK,proto3" json:"TopK,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictInputChunk) Reset() {
+	*x = PredictInputChunk{}
+	mi := &file_proto_inference_inference_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictInputChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictInputChunk) ProtoMessage() {}
+
+func (x *PredictInputChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inference_inference_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictInputChunk.ProtoReflect.Descriptor instead.
+func (*PredictInputChunk) Descriptor() ([]byte, []int) {
+	return file_proto_inference_inference_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PredictInputChunk) GetModelName() string {
+	if x != nil {
+		return x.ModelName
+	}
+	return ""
+}
+
+func (x *PredictInputChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *PredictInputChunk) GetPostProcess() string {
+	if x != nil {
+		return x.PostProcess
+	}
+	return ""
+}
+
+func (x *PredictInputChunk) GetTopK() int32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
+}
+
+type PredictResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	OutputData        []byte                 `protobuf:"bytes,1,opt,name=OutputData,proto3" json:"OutputData,omitempty"`
+	Status            string                 `protobuf:"bytes,2,opt,name=Status,proto3" json:"Status,omitempty"`
+	TopKIndices       []int32                `protobuf:"varint,3,rep,packed,name=TopKIndices,proto3" json:"TopKIndices,omitempty"`
+	TopKProbabilities []float64              `protobuf:"fixed64,4,rep,packed,name=TopKProbabilities,proto3" json:"TopKProbabilities,omitempty"`
+	// ModelVersion is the version reported by the backend, empty if it did
+	// not report one.
+	ModelVersion string `protobuf:"bytes,5,opt,name=ModelVersion,proto3" json:"ModelVersion,omitempty"`
+	// BackendTimingsMs is the backend's Server-Timing breakdown in
+	// milliseconds by metric name (e.g. "queue", "compute"). Empty when the
+	// backend sent no Server-Timing header.
+	BackendTimingsMs map[string]float64 `protobuf:"bytes,6,rep,name=BackendTimingsMs,proto3" json:"BackendTimingsMs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	// OutputEncoding describes OutputData: empty for a JSON array, "ndjson"
+	// for newline-delimited JSON numbers, otherwise the requested
+	// Quantization. "float16" is little-endian IEEE 754 half
+	// precision values; "int8" is one signed byte q per value, decoded as
+	// QuantScale * (q - QuantZeroPoint).
+	OutputEncoding string  `protobuf:"bytes,7,opt,name=OutputEncoding,proto3" json:"OutputEncoding,omitempty"`
+	QuantScale     float64 `protobuf:"fixed64,8,opt,name=QuantScale,proto3" json:"QuantScale,omitempty"`
+	QuantZeroPoint int32   `protobuf:"varint,9,opt,name=QuantZeroPoint,proto3" json:"QuantZeroPoint,omitempty"`
+	// Outputs holds one JSON array per output head for multi-head models,
+	// or per output tensor for multi-tensor models, keyed by name;
+	// OutputData is empty then. Single-output models leave it empty.
+	Outputs map[string][]byte `protobuf:"bytes,10,rep,name=Outputs,proto3" json:"Outputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Confidence holds the confidence values the backend reported alongside
+	// its output: one value for a scalar, or one per output value. Empty
+	// when the backend sent none.
+	Confidence []float64 `protobuf:"fixed64,11,rep,packed,name=Confidence,proto3" json:"Confidence,omitempty"`
+	// Metadata holds the backend response fields listed in the model's
+	// forward_fields config, as the raw JSON the backend sent, keyed by
+	// field name. Fields the backend didn't send are absent.
+	Metadata map[string][]byte `protobuf:"bytes,12,rep,name=Metadata,proto3" json:"Metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// ModelName is the model name from the request. When it is an alias
+	// from the config, this is still the alias, not the model it resolved
+	// to.
+	ModelName string `protobuf:"bytes,13,opt,name=ModelName,proto3" json:"ModelName,omitempty"`
+	// CorrelationId echoes the correlation ID sent with the request, if any.
+	CorrelationId string `protobuf:"bytes,14,opt,name=CorrelationId,proto3" json:"CorrelationId,omitempty"`
+	// RequestHash is the hex SHA-256 of the resolved model name, pinned
+	// version, Params, InputShape (when set) and input values, stable across
+	// calls and servers.
+	// Clients can use it to index responses; see the README for the exact
+	// scheme.
+	RequestHash string `protobuf:"bytes,15,opt,name=RequestHash,proto3" json:"RequestHash,omitempty"`
+	// OutputShapes holds the shape of each entry in Outputs when the backend
+	// returned named tensors with shapes (KServe v2 or Triton style). The
+	// values in Outputs are then flattened in row-major order. Empty for
+	// outputs the backend sent without shapes.
+	OutputShapes  map[string]*TensorShape `protobuf:"bytes,16,rep,name=OutputShapes,proto3" json:"OutputShapes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PredictResponse) Reset() {
 	*x = PredictResponse{}
-	mi := &file_proto_inference_inference_proto_msgTypes[1]
+	mi := &file_proto_inference_inference_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -95,7 +300,7 @@ func (x *PredictResponse) String() string {
 func (*PredictResponse) ProtoMessage() {}
 
 func (x *PredictResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_inference_inference_proto_msgTypes[1]
+	mi := &file_proto_inference_inference_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -108,7 +313,7 @@ func (x *PredictResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PredictResponse.ProtoReflect.Descriptor instead.
 func (*PredictResponse) Descriptor() ([]byte, []int) {
-	return file_proto_inference_inference_proto_rawDescGZIP(), []int{1}
+	return file_proto_inference_inference_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *PredictResponse) GetOutputData() []byte {
@@ -125,21 +330,622 @@ func (x *PredictResponse) GetStatus() string {
 	return ""
 }
 
+func (x *PredictResponse) GetTopKIndices() []int32 {
+	if x != nil {
+		return x.TopKIndices
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetTopKProbabilities() []float64 {
+	if x != nil {
+		return x.TopKProbabilities
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetBackendTimingsMs() map[string]float64 {
+	if x != nil {
+		return x.BackendTimingsMs
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetOutputEncoding() string {
+	if x != nil {
+		return x.OutputEncoding
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetQuantScale() float64 {
+	if x != nil {
+		return x.QuantScale
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetQuantZeroPoint() int32 {
+	if x != nil {
+		return x.QuantZeroPoint
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetOutputs() map[string][]byte {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetConfidence() []float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetMetadata() map[string][]byte {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *PredictResponse) GetModelName() string {
+	if x != nil {
+		return x.ModelName
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetRequestHash() string {
+	if x != nil {
+		return x.RequestHash
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetOutputShapes() map[string]*TensorShape {
+	if x != nil {
+		return x.OutputShapes
+	}
+	return nil
+}
+
+// TensorShape is the size of each dimension of a tensor; no dimensions
+// means a scalar.
+type TensorShape struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dims          []int64                `protobuf:"varint,1,rep,packed,name=Dims,proto3" json:"Dims,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TensorShape) Reset() {
+	*x = TensorShape{}
+	mi := &file_proto_inference_inference_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TensorShape) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TensorShape) ProtoMessage() {}
+
+func (x *TensorShape) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inference_inference_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TensorShape.ProtoReflect.Descriptor instead.
+func (*TensorShape) Descriptor() ([]byte, []int) {
+	return file_proto_inference_inference_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TensorShape) GetDims() []int64 {
+	if x != nil {
+		return x.Dims
+	}
+	return nil
+}
+
+type EnsembleRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ModelNames lists the ensemble members; each receives InputData.
+	ModelNames []string `protobuf:"bytes,1,rep,name=ModelNames,proto3" json:"ModelNames,omitempty"`
+	InputData  []byte   `protobuf:"bytes,2,opt,name=InputData,proto3" json:"InputData,omitempty"`
+	// Aggregation combines the members' outputs: "mean" (default), "sum" or
+	// "concat". Members are concatenated in ModelNames order.
+	Aggregation   string `protobuf:"bytes,3,opt,name=Aggregation,proto3" json:"Aggregation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnsembleRequest) Reset() {
+	*x = EnsembleRequest{}
+	mi := &file_proto_inference_inference_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnsembleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnsembleRequest) ProtoMessage() {}
+
+func (x *EnsembleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inference_inference_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnsembleRequest.ProtoReflect.Descriptor instead.
+func (*EnsembleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_inference_inference_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EnsembleRequest) GetModelNames() []string {
+	if x != nil {
+		return x.ModelNames
+	}
+	return nil
+}
+
+func (x *EnsembleRequest) GetInputData() []byte {
+	if x != nil {
+		return x.InputData
+	}
+	return nil
+}
+
+func (x *EnsembleRequest) GetAggregation() string {
+	if x != nil {
+		return x.Aggregation
+	}
+	return ""
+}
+
+type EnsembleMemberResult struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ModelName  string                 `protobuf:"bytes,1,opt,name=ModelName,proto3" json:"ModelName,omitempty"`
+	OutputData []byte                 `protobuf:"bytes,2,opt,name=OutputData,proto3" json:"OutputData,omitempty"`
+	Status     string                 `protobuf:"bytes,3,opt,name=Status,proto3" json:"Status,omitempty"`
+	// ErrorCode and Error describe why the member failed; ErrorCode is 0
+	// (OK) when it succeeded.
+	ErrorCode     int32  `protobuf:"varint,4,opt,name=ErrorCode,proto3" json:"ErrorCode,omitempty"`
+	Error         string `protobuf:"bytes,5,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnsembleMemberResult) Reset() {
+	*x = EnsembleMemberResult{}
+	mi := &file_proto_inference_inference_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnsembleMemberResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnsembleMemberResult) ProtoMessage() {}
+
+func (x *EnsembleMemberResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inference_inference_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnsembleMemberResult.ProtoReflect.Descriptor instead.
+func (*EnsembleMemberResult) Descriptor() ([]byte, []int) {
+	return file_proto_inference_inference_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EnsembleMemberResult) GetModelName() string {
+	if x != nil {
+		return x.ModelName
+	}
+	return ""
+}
+
+func (x *EnsembleMemberResult) GetOutputData() []byte {
+	if x != nil {
+		return x.OutputData
+	}
+	return nil
+}
+
+func (x *EnsembleMemberResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *EnsembleMemberResult) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *EnsembleMemberResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type EnsembleResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// OutputData is the aggregate of the successful members' outputs.
+	OutputData    []byte                  `protobuf:"bytes,1,opt,name=OutputData,proto3" json:"OutputData,omitempty"`
+	Members       []*EnsembleMemberResult `protobuf:"bytes,2,rep,name=Members,proto3" json:"Members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnsembleResponse) Reset() {
+	*x = EnsembleResponse{}
+	mi := &file_proto_inference_inference_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnsembleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnsembleResponse) ProtoMessage() {}
+
+func (x *EnsembleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inference_inference_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnsembleResponse.ProtoReflect.Descriptor instead.
+func (*EnsembleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_inference_inference_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EnsembleResponse) GetOutputData() []byte {
+	if x != nil {
+		return x.OutputData
+	}
+	return nil
+}
+
+func (x *EnsembleResponse) GetMembers() []*EnsembleMemberResult {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type LoadTestRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ModelName string                 `protobuf:"bytes,1,opt,name=ModelName,proto3" json:"ModelName,omitempty"`
+	// InputData is sent to the backend on every call.
+	InputData []byte `protobuf:"bytes,2,opt,name=InputData,proto3" json:"InputData,omitempty"`
+	// Concurrency is the number of calls kept in flight at once.
+	Concurrency int32 `protobuf:"varint,3,opt,name=Concurrency,proto3" json:"Concurrency,omitempty"`
+	// Requests is the total number of backend calls to make.
+	Requests      int32 `protobuf:"varint,4,opt,name=Requests,proto3" json:"Requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadTestRequest) Reset() {
+	*x = LoadTestRequest{}
+	mi := &file_proto_inference_inference_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadTestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadTestRequest) ProtoMessage() {}
+
+func (x *LoadTestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inference_inference_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadTestRequest.ProtoReflect.Descriptor instead.
+func (*LoadTestRequest) Descriptor() ([]byte, []int) {
+	return file_proto_inference_inference_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LoadTestRequest) GetModelName() string {
+	if x != nil {
+		return x.ModelName
+	}
+	return ""
+}
+
+func (x *LoadTestRequest) GetInputData() []byte {
+	if x != nil {
+		return x.InputData
+	}
+	return nil
+}
+
+func (x *LoadTestRequest) GetConcurrency() int32 {
+	if x != nil {
+		return x.Concurrency
+	}
+	return 0
+}
+
+func (x *LoadTestRequest) GetRequests() int32 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+type LoadTestResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Requests  int32                  `protobuf:"varint,1,opt,name=Requests,proto3" json:"Requests,omitempty"`
+	Errors    int32                  `protobuf:"varint,2,opt,name=Errors,proto3" json:"Errors,omitempty"`
+	ErrorRate float64                `protobuf:"fixed64,3,opt,name=ErrorRate,proto3" json:"ErrorRate,omitempty"`
+	// ThroughputRps is completed calls, failed ones included, per second of
+	// wall-clock time.
+	ThroughputRps float64 `protobuf:"fixed64,4,opt,name=ThroughputRps,proto3" json:"ThroughputRps,omitempty"`
+	DurationMs    float64 `protobuf:"fixed64,5,opt,name=DurationMs,proto3" json:"DurationMs,omitempty"`
+	// Latency percentiles of the successful calls.
+	LatencyP50Ms  float64 `protobuf:"fixed64,6,opt,name=LatencyP50Ms,proto3" json:"LatencyP50Ms,omitempty"`
+	LatencyP95Ms  float64 `protobuf:"fixed64,7,opt,name=LatencyP95Ms,proto3" json:"LatencyP95Ms,omitempty"`
+	LatencyP99Ms  float64 `protobuf:"fixed64,8,opt,name=LatencyP99Ms,proto3" json:"LatencyP99Ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadTestResponse) Reset() {
+	*x = LoadTestResponse{}
+	mi := &file_proto_inference_inference_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadTestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadTestResponse) ProtoMessage() {}
+
+func (x *LoadTestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_inference_inference_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadTestResponse.ProtoReflect.Descriptor instead.
+func (*LoadTestResponse) Descriptor() ([]byte, []int) {
+	return file_proto_inference_inference_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LoadTestResponse) GetRequests() int32 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *LoadTestResponse) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *LoadTestResponse) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+func (x *LoadTestResponse) GetThroughputRps() float64 {
+	if x != nil {
+		return x.ThroughputRps
+	}
+	return 0
+}
+
+func (x *LoadTestResponse) GetDurationMs() float64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *LoadTestResponse) GetLatencyP50Ms() float64 {
+	if x != nil {
+		return x.LatencyP50Ms
+	}
+	return 0
+}
+
+func (x *LoadTestResponse) GetLatencyP95Ms() float64 {
+	if x != nil {
+		return x.LatencyP95Ms
+	}
+	return 0
+}
+
+func (x *LoadTestResponse) GetLatencyP99Ms() float64 {
+	if x != nil {
+		return x.LatencyP99Ms
+	}
+	return 0
+}
+
 var File_proto_inference_inference_proto protoreflect.FileDescriptor
 
 const file_proto_inference_inference_proto_rawDesc = "" +
 	"\n" +
-	"\x1fproto/inference/inference.proto\x12\tinference\"L\n" +
+	"\x1fproto/inference/inference.proto\x12\tinference\"\xd9\x02\n" +
 	"\x0ePredictRequest\x12\x1c\n" +
 	"\tModelName\x18\x01 \x01(\tR\tModelName\x12\x1c\n" +
-	"\tInputData\x18\x02 \x01(\fR\tInputData\"I\n" +
+	"\tInputData\x18\x02 \x01(\fR\tInputData\x12 \n" +
+	"\vPostProcess\x18\x03 \x01(\tR\vPostProcess\x12\x12\n" +
+	"\x04TopK\x18\x04 \x01(\x05R\x04TopK\x12\"\n" +
+	"\fQuantization\x18\x05 \x01(\tR\fQuantization\x12\x16\n" +
+	"\x06Params\x18\x06 \x01(\fR\x06Params\x12\"\n" +
+	"\fOutputFormat\x18\a \x01(\tR\fOutputFormat\x12$\n" +
+	"\rCorrelationId\x18\b \x01(\tR\rCorrelationId\x12!\n" +
+	"\tPrecision\x18\t \x01(\x05H\x00R\tPrecision\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"InputShape\x18\n" +
+	" \x03(\x03R\n" +
+	"InputShapeB\f\n" +
+	"\n" +
+	"_Precision\"{\n" +
+	"\x11PredictInputChunk\x12\x1c\n" +
+	"\tModelName\x18\x01 \x01(\tR\tModelName\x12\x12\n" +
+	"\x04Data\x18\x02 \x01(\fR\x04Data\x12 \n" +
+	"\vPostProcess\x18\x03 \x01(\tR\vPostProcess\x12\x12\n" +
+	"\x04TopK\x18\x04 \x01(\x05R\x04TopK\"\x83\b\n" +
 	"\x0fPredictResponse\x12\x1e\n" +
 	"\n" +
 	"OutputData\x18\x01 \x01(\fR\n" +
 	"OutputData\x12\x16\n" +
-	"\x06Status\x18\x02 \x01(\tR\x06Status2O\n" +
+	"\x06Status\x18\x02 \x01(\tR\x06Status\x12 \n" +
+	"\vTopKIndices\x18\x03 \x03(\x05R\vTopKIndices\x12,\n" +
+	"\x11TopKProbabilities\x18\x04 \x03(\x01R\x11TopKProbabilities\x12\"\n" +
+	"\fModelVersion\x18\x05 \x01(\tR\fModelVersion\x12\\\n" +
+	"\x10BackendTimingsMs\x18\x06 \x03(\v20.inference.PredictResponse.BackendTimingsMsEntryR\x10BackendTimingsMs\x12&\n" +
+	"\x0eOutputEncoding\x18\a \x01(\tR\x0eOutputEncoding\x12\x1e\n" +
+	"\n" +
+	"QuantScale\x18\b \x01(\x01R\n" +
+	"QuantScale\x12&\n" +
+	"\x0eQuantZeroPoint\x18\t \x01(\x05R\x0eQuantZeroPoint\x12A\n" +
+	"\aOutputs\x18\n" +
+	" \x03(\v2'.inference.PredictResponse.OutputsEntryR\aOutputs\x12\x1e\n" +
+	"\n" +
+	"Confidence\x18\v \x03(\x01R\n" +
+	"Confidence\x12D\n" +
+	"\bMetadata\x18\f \x03(\v2(.inference.PredictResponse.MetadataEntryR\bMetadata\x12\x1c\n" +
+	"\tModelName\x18\r \x01(\tR\tModelName\x12$\n" +
+	"\rCorrelationId\x18\x0e \x01(\tR\rCorrelationId\x12 \n" +
+	"\vRequestHash\x18\x0f \x01(\tR\vRequestHash\x12P\n" +
+	"\fOutputShapes\x18\x10 \x03(\v2,.inference.PredictResponse.OutputShapesEntryR\fOutputShapes\x1aC\n" +
+	"\x15BackendTimingsMsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\x1a:\n" +
+	"\fOutputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01\x1aW\n" +
+	"\x11OutputShapesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.inference.TensorShapeR\x05value:\x028\x01\"!\n" +
+	"\vTensorShape\x12\x12\n" +
+	"\x04Dims\x18\x01 \x03(\x03R\x04Dims\"q\n" +
+	"\x0fEnsembleRequest\x12\x1e\n" +
+	"\n" +
+	"ModelNames\x18\x01 \x03(\tR\n" +
+	"ModelNames\x12\x1c\n" +
+	"\tInputData\x18\x02 \x01(\fR\tInputData\x12 \n" +
+	"\vAggregation\x18\x03 \x01(\tR\vAggregation\"\xa0\x01\n" +
+	"\x14EnsembleMemberResult\x12\x1c\n" +
+	"\tModelName\x18\x01 \x01(\tR\tModelName\x12\x1e\n" +
+	"\n" +
+	"OutputData\x18\x02 \x01(\fR\n" +
+	"OutputData\x12\x16\n" +
+	"\x06Status\x18\x03 \x01(\tR\x06Status\x12\x1c\n" +
+	"\tErrorCode\x18\x04 \x01(\x05R\tErrorCode\x12\x14\n" +
+	"\x05Error\x18\x05 \x01(\tR\x05Error\"m\n" +
+	"\x10EnsembleResponse\x12\x1e\n" +
+	"\n" +
+	"OutputData\x18\x01 \x01(\fR\n" +
+	"OutputData\x129\n" +
+	"\aMembers\x18\x02 \x03(\v2\x1f.inference.EnsembleMemberResultR\aMembers\"\x8b\x01\n" +
+	"\x0fLoadTestRequest\x12\x1c\n" +
+	"\tModelName\x18\x01 \x01(\tR\tModelName\x12\x1c\n" +
+	"\tInputData\x18\x02 \x01(\fR\tInputData\x12 \n" +
+	"\vConcurrency\x18\x03 \x01(\x05R\vConcurrency\x12\x1a\n" +
+	"\bRequests\x18\x04 \x01(\x05R\bRequests\"\x96\x02\n" +
+	"\x10LoadTestResponse\x12\x1a\n" +
+	"\bRequests\x18\x01 \x01(\x05R\bRequests\x12\x16\n" +
+	"\x06Errors\x18\x02 \x01(\x05R\x06Errors\x12\x1c\n" +
+	"\tErrorRate\x18\x03 \x01(\x01R\tErrorRate\x12$\n" +
+	"\rThroughputRps\x18\x04 \x01(\x01R\rThroughputRps\x12\x1e\n" +
+	"\n" +
+	"DurationMs\x18\x05 \x01(\x01R\n" +
+	"DurationMs\x12\"\n" +
+	"\fLatencyP50Ms\x18\x06 \x01(\x01R\fLatencyP50Ms\x12\"\n" +
+	"\fLatencyP95Ms\x18\a \x01(\x01R\fLatencyP95Ms\x12\"\n" +
+	"\fLatencyP99Ms\x18\b \x01(\x01R\fLatencyP99Ms2\xb7\x02\n" +
 	"\tInference\x12B\n" +
-	"\aPredict\x12\x19.inference.PredictRequest\x1a\x1a.inference.PredictResponse\"\x00BEZCgithub.com/arhantsg07/ml-inference-system/proto/inference;inferenceb\x06proto3"
+	"\aPredict\x12\x19.inference.PredictRequest\x1a\x1a.inference.PredictResponse\"\x00\x12Q\n" +
+	"\x11PredictLargeInput\x12\x1c.inference.PredictInputChunk\x1a\x1a.inference.PredictResponse\"\x00(\x01\x12L\n" +
+	"\x0fEnsemblePredict\x12\x1a.inference.EnsembleRequest\x1a\x1b.inference.EnsembleResponse\"\x00\x12E\n" +
+	"\bLoadTest\x12\x1a.inference.LoadTestRequest\x1a\x1b.inference.LoadTestResponse\"\x00BEZCgithub.com/arhantsg07/ml-inference-system/proto/inference;inferenceb\x06proto3"
 
 var (
 	file_proto_inference_inference_proto_rawDescOnce sync.Once
@@ -153,19 +959,42 @@ func file_proto_inference_inference_proto_rawDescGZIP() []byte {
 	return file_proto_inference_inference_proto_rawDescData
 }
 
-var file_proto_inference_inference_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_inference_inference_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_proto_inference_inference_proto_goTypes = []any{
-	(*PredictRequest)(nil),  // 0: inference.PredictRequest
-	(*PredictResponse)(nil), // 1: inference.PredictResponse
+	(*PredictRequest)(nil),       // 0: inference.PredictRequest
+	(*PredictInputChunk)(nil),    // 1: inference.PredictInputChunk
+	(*PredictResponse)(nil),      // 2: inference.PredictResponse
+	(*TensorShape)(nil),          // 3: inference.TensorShape
+	(*EnsembleRequest)(nil),      // 4: inference.EnsembleRequest
+	(*EnsembleMemberResult)(nil), // 5: inference.EnsembleMemberResult
+	(*EnsembleResponse)(nil),     // 6: inference.EnsembleResponse
+	(*LoadTestRequest)(nil),      // 7: inference.LoadTestRequest
+	(*LoadTestResponse)(nil),     // 8: inference.LoadTestResponse
+	nil,                          // 9: inference.PredictResponse.BackendTimingsMsEntry
+	nil,                          // 10: inference.PredictResponse.OutputsEntry
+	nil,                          // 11: inference.PredictResponse.MetadataEntry
+	nil,                          // 12: inference.PredictResponse.OutputShapesEntry
 }
 var file_proto_inference_inference_proto_depIdxs = []int32{
-	0, // 0: inference.Inference.Predict:input_type -> inference.PredictRequest
-	1, // 1: inference.Inference.Predict:output_type -> inference.PredictResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	9,  // 0: inference.PredictResponse.BackendTimingsMs:type_name -> inference.PredictResponse.BackendTimingsMsEntry
+	10, // 1: inference.PredictResponse.Outputs:type_name -> inference.PredictResponse.OutputsEntry
+	11, // 2: inference.PredictResponse.Metadata:type_name -> inference.PredictResponse.MetadataEntry
+	12, // 3: inference.PredictResponse.OutputShapes:type_name -> inference.PredictResponse.OutputShapesEntry
+	5,  // 4: inference.EnsembleResponse.Members:type_name -> inference.EnsembleMemberResult
+	3,  // 5: inference.PredictResponse.OutputShapesEntry.value:type_name -> inference.TensorShape
+	0,  // 6: inference.Inference.Predict:input_type -> inference.PredictRequest
+	1,  // 7: inference.Inference.PredictLargeInput:input_type -> inference.PredictInputChunk
+	4,  // 8: inference.Inference.EnsemblePredict:input_type -> inference.EnsembleRequest
+	7,  // 9: inference.Inference.LoadTest:input_type -> inference.LoadTestRequest
+	2,  // 10: inference.Inference.Predict:output_type -> inference.PredictResponse
+	2,  // 11: inference.Inference.PredictLargeInput:output_type -> inference.PredictResponse
+	6,  // 12: inference.Inference.EnsemblePredict:output_type -> inference.EnsembleResponse
+	8,  // 13: inference.Inference.LoadTest:output_type -> inference.LoadTestResponse
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_proto_inference_inference_proto_init() }
@@ -173,13 +1002,14 @@ func file_proto_inference_inference_proto_init() {
 	if File_proto_inference_inference_proto != nil {
 		return
 	}
+	file_proto_inference_inference_proto_msgTypes[0].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_inference_inference_proto_rawDesc), len(file_proto_inference_inference_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Inference_Predict_FullMethodName = "/inference.Inference/Predict"
+	Inference_Predict_FullMethodName           = "/inference.Inference/Predict"
+	Inference_PredictLargeInput_FullMethodName = "/inference.Inference/PredictLargeInput"
+	Inference_EnsemblePredict_FullMethodName   = "/inference.Inference/EnsemblePredict"
+	Inference_LoadTest_FullMethodName          = "/inference.Inference/LoadTest"
 )
 
 // InferenceClient is the client API for Inference service.
@@ -27,6 +30,11 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type InferenceClient interface {
 	Predict(ctx context.Context, in *PredictRequest, opts ...grpc.CallOption) (*PredictResponse, error)
+	PredictLargeInput(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[PredictInputChunk, PredictResponse], error)
+	EnsemblePredict(ctx context.Context, in *EnsembleRequest, opts ...grpc.CallOption) (*EnsembleResponse, error)
+	// LoadTest benchmarks a backend through the server's own call path. It
+	// is only served when the server runs with -enable-load-test.
+	LoadTest(ctx context.Context, in *LoadTestRequest, opts ...grpc.CallOption) (*LoadTestResponse, error)
 }
 
 type inferenceClient struct {
@@ -47,11 +55,49 @@ func (c *inferenceClient) Predict(ctx context.Context, in *PredictRequest, opts
 	return out, nil
 }
 
+func (c *inferenceClient) PredictLargeInput(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[PredictInputChunk, PredictResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Inference_ServiceDesc.Streams[0], Inference_PredictLargeInput_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PredictInputChunk, PredictResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Inference_PredictLargeInputClient = grpc.ClientStreamingClient[PredictInputChunk, PredictResponse]
+
+func (c *inferenceClient) EnsemblePredict(ctx context.Context, in *EnsembleRequest, opts ...grpc.CallOption) (*EnsembleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnsembleResponse)
+	err := c.cc.Invoke(ctx, Inference_EnsemblePredict_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inferenceClient) LoadTest(ctx context.Context, in *LoadTestRequest, opts ...grpc.CallOption) (*LoadTestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoadTestResponse)
+	err := c.cc.Invoke(ctx, Inference_LoadTest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InferenceServer is the server API for Inference service.
 // All implementations must embed UnimplementedInferenceServer
 // for forward compatibility.
 type InferenceServer interface {
 	Predict(context.Context, *PredictRequest) (*PredictResponse, error)
+	PredictLargeInput(grpc.ClientStreamingServer[PredictInputChunk, PredictResponse]) error
+	EnsemblePredict(context.Context, *EnsembleRequest) (*EnsembleResponse, error)
+	// LoadTest benchmarks a backend through the server's own call path. It
+	// is only served when the server runs with -enable-load-test.
+	LoadTest(context.Context, *LoadTestRequest) (*LoadTestResponse, error)
 	mustEmbedUnimplementedInferenceServer()
 }
 
@@ -65,6 +111,15 @@ type UnimplementedInferenceServer struct{}
 func (UnimplementedInferenceServer) Predict(context.Context, *PredictRequest) (*PredictResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Predict not implemented")
 }
+func (UnimplementedInferenceServer) PredictLargeInput(grpc.ClientStreamingServer[PredictInputChunk, PredictResponse]) error {
+	return status.Error(codes.Unimplemented, "method PredictLargeInput not implemented")
+}
+func (UnimplementedInferenceServer) EnsemblePredict(context.Context, *EnsembleRequest) (*EnsembleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EnsemblePredict not implemented")
+}
+func (UnimplementedInferenceServer) LoadTest(context.Context, *LoadTestRequest) (*LoadTestResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LoadTest not implemented")
+}
 func (UnimplementedInferenceServer) mustEmbedUnimplementedInferenceServer() {}
 func (UnimplementedInferenceServer) testEmbeddedByValue()                   {}
 
@@ -104,6 +159,49 @@ func _Inference_Predict_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Inference_PredictLargeInput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InferenceServer).PredictLargeInput(&grpc.GenericServerStream[PredictInputChunk, PredictResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Inference_PredictLargeInputServer = grpc.ClientStreamingServer[PredictInputChunk, PredictResponse]
+
+func _Inference_EnsemblePredict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnsembleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InferenceServer).EnsemblePredict(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Inference_EnsemblePredict_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InferenceServer).EnsemblePredict(ctx, req.(*EnsembleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Inference_LoadTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadTestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InferenceServer).LoadTest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Inference_LoadTest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InferenceServer).LoadTest(ctx, req.(*LoadTestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Inference_ServiceDesc is the grpc.ServiceDesc for Inference service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +213,21 @@ var Inference_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Predict",
 			Handler:    _Inference_Predict_Handler,
 		},
+		{
+			MethodName: "EnsemblePredict",
+			Handler:    _Inference_EnsemblePredict_Handler,
+		},
+		{
+			MethodName: "LoadTest",
+			Handler:    _Inference_LoadTest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PredictLargeInput",
+			Handler:       _Inference_PredictLargeInput_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/inference/inference.proto",
 }
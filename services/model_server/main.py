@@ -24,6 +24,7 @@ class PredictionResponse(BaseModel):
     model_name : str
     output: List[float]
     status: str
+    version: str
 
 def load_model(model_name: str):
     """check if the models in cache or not"""
@@ -67,7 +68,8 @@ async def predict(request: PredictionRequest):
         return PredictionResponse(
             model_name=request.model_name,
             output=output_list,
-            status="ok"
+            status="ok",
+            version=str(session.get_modelmeta().version)
         )
 
     except HTTPException: